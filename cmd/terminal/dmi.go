@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zenithax-cc/baize/internal/collector/smbios"
+)
+
+// listFlag collects a flag that may be given several times, e.g. -t 4 -t 17.
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ",") }
+
+func (l *listFlag) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// runDMI implements "baize dmi", printing the SMBIOS tables in dmidecode
+// layout, or a single dmidecode -s string. It returns the exit code.
+func runDMI(args []string) int {
+	fs := flag.NewFlagSet("dmi", flag.ContinueOnError)
+	var (
+		types    listFlag
+		keyword  string
+		fromDump string
+		lint     bool
+	)
+	fs.Var(&types, "t", "only display structures of this type: a number, comma-separated numbers or a keyword (bios, system, baseboard, chassis, processor, memory, cache, connector, slot)")
+	fs.StringVar(&keyword, "s", "", "only display the value of this string keyword, e.g. system-manufacturer")
+	fs.StringVar(&fromDump, "from-dump", "", "read a dmidecode --dump-bin file or a copy of /sys/firmware/dmi/tables instead of this host")
+	fs.BoolVar(&lint, "lint", false, "check the SMBIOS data for checksum, length, handle and placeholder problems")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: baize dmi [-from-dump file] [-t type]... [-s keyword] [-lint]\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nString keywords:\n  %s\n", strings.Join(smbios.DMIStringKeywords(), "\n  "))
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	smbios.SetDumpFile(fromDump)
+
+	if lint {
+		return runDMILint()
+	}
+
+	if keyword != "" {
+		values, err := smbios.GetString(keyword)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, v := range values {
+			fmt.Println(v)
+		}
+		return 0
+	}
+
+	selected, err := smbios.ParseDMITypes(types)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if err := smbios.Dmidecode(os.Stdout, selected); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// runDMILint prints the SMBIOS lint report. It returns 1 when errors are
+// found, so intake scripts can reject the server.
+func runDMILint() int {
+	issues, err := smbios.Lint()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	errs := 0
+	for _, issue := range issues {
+		fmt.Println(issue)
+		if issue.Severity == smbios.LintError {
+			errs++
+		}
+	}
+	fmt.Printf("%d errors, %d warnings\n", errs, len(issues)-errs)
+
+	if errs > 0 {
+		return 1
+	}
+	return 0
+}
@@ -6,8 +6,12 @@ import (
 	"flag"
 	"fmt"
 	"log/slog"
+	"os"
 	"time"
 
+	"github.com/zenithax-cc/baize/internal/collector/cpu"
+	"github.com/zenithax-cc/baize/internal/collector/memory"
+	"github.com/zenithax-cc/baize/internal/collector/smbios"
 	"github.com/zenithax-cc/baize/pkg/collector"
 	"github.com/zenithax-cc/baize/pkg/utils"
 )
@@ -17,6 +21,12 @@ type cliCfg struct {
 	module string // target module name, e.g., "cpu", "memory", "all"
 	json   bool   // when true, output results as JSON
 	detail bool   // when true, print detailed view instead of brief summary
+
+	microcodePolicy string // path to a microcode minimum-revision policy file
+	ipmiTempCheck   bool   // cross-check AMD/Hygon CPU temperatures against IPMI sensors
+	powerProfile    string // expected CPU power profile, e.g. "performance"
+	spdI2C          bool   // read DIMM SPDs through i2c-dev where no EEPROM driver is bound
+	smbiosDump      string // SMBIOS dump to decode instead of the local tables
 }
 
 // newCliCfg registers CLI flags and parses them, returning a populated cliCfg.
@@ -25,6 +35,17 @@ func newCliCfg() *cliCfg {
 	flag.StringVar(&res.module, "m", "all", "module name")
 	flag.BoolVar(&res.json, "j", false, "output json")
 	flag.BoolVar(&res.detail, "d", false, "output detail")
+	flag.BoolVar(&res.ipmiTempCheck, "ipmi-temp-check", false, "cross-check AMD/Hygon CPU temperatures against IPMI sensors")
+	flag.Func("power-profile", "expected CPU power profile: performance, balanced or powersave", func(v string) error {
+		if err := cpu.SetPowerProfile(v); err != nil {
+			return err
+		}
+		res.powerProfile = v
+		return nil
+	})
+	flag.BoolVar(&res.spdI2C, "spd-i2c", false, "read DIMM SPD EEPROMs through i2c-dev where no ee1004/spd5118 driver is bound")
+	flag.StringVar(&res.smbiosDump, "smbios-dump", "", "decode SMBIOS from a dmidecode --dump-bin file or a copy of /sys/firmware/dmi/tables instead of this host")
+	flag.StringVar(&res.microcodePolicy, "microcode-policy", "", "microcode policy file (lines of \"family model stepping min_revision\")")
 
 	flag.Parse()
 
@@ -40,14 +61,38 @@ func newCliCfg() *cliCfg {
 // }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "dmi":
+			os.Exit(runDMI(os.Args[2:]))
+		case "pciids":
+			os.Exit(runPCIIDs(os.Args[2:]))
+		}
+	}
+
 	cfg := newCliCfg()
 
+	cpu.SetIPMICrossCheck(cfg.ipmiTempCheck)
+	memory.SetSPDRawI2C(cfg.spdI2C)
+	smbios.SetDumpFile(cfg.smbiosDump)
+
+	// Without its policy the microcode check would silently pass, so a
+	// policy that cannot be loaded stops the run.
+	if cfg.microcodePolicy != "" {
+		if err := cpu.LoadMicrocodePolicy(cfg.microcodePolicy); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
 	// Build the collector manager with the parsed CLI settings.
 	m := collector.Manager{
 		Module: cfg.module,
 		Detail: cfg.detail,
 		Json:   cfg.json,
 		Log:    slog.Default(),
+
+		SMBIOSOnly: cfg.smbiosDump != "",
 	}
 
 	start := time.Now()
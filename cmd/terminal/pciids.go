@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/zenithax-cc/baize/internal/collector/pci"
+)
+
+// runPCIIDs implements "baize pciids", listing the pci.ids databases and
+// which one is used, and "baize pciids update", refreshing the cache from a
+// file or, only with -download, from upstream. It returns the exit code.
+func runPCIIDs(args []string) int {
+	if len(args) == 0 || args[0] != "update" {
+		if len(args) > 0 && args[0] != "list" {
+			fmt.Fprintln(os.Stderr, "Usage: baize pciids [list | update -from file | update -download]")
+			return 2
+		}
+		printPCIIDsSources()
+		return 0
+	}
+
+	fs := flag.NewFlagSet("pciids update", flag.ContinueOnError)
+	var (
+		from     string
+		download bool
+	)
+	fs.StringVar(&from, "from", "", "pci.ids file to install in the cache, plain or gzip compressed")
+	fs.BoolVar(&download, "download", false, "download pci.ids from upstream (the only command that uses the network)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: baize pciids update -from file | -download\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if (from == "") == !download {
+		fs.Usage()
+		return 2
+	}
+
+	var (
+		src *pci.PCIIDsSource
+		err error
+	)
+	if download {
+		src, err = pci.DownloadPCIIDs()
+	} else {
+		src, err = pci.UpdatePCIIDs(from)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Printf("Installed pci.ids version %s in %s\n", src.Version, src.Path)
+
+	printPCIIDsSources()
+	return 0
+}
+
+// printPCIIDsSources lists the databases, the one in use marked with "*".
+func printPCIIDsSources() {
+	for _, s := range pci.PCIIDsSources() {
+		mark := " "
+		if s.Active {
+			mark = "*"
+		}
+		version := s.Version
+		if version == "" {
+			version = "unknown"
+		}
+		fmt.Printf("%s %-30s %s\n", mark, s.Path, version)
+	}
+}
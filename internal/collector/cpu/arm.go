@@ -0,0 +1,250 @@
+// Package cpu - arm.go identifies ARM processors by decoding the MIDR_EL1
+// register into implementer and core names, and fills in the fields that lscpu
+// and turbostat leave empty on aarch64 servers.
+package cpu
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+// midrImplementers maps the MIDR_EL1 implementer code to the vendor name.
+var midrImplementers = map[uint32]string{
+	0x41: "ARM",
+	0x42: "Broadcom",
+	0x43: "Cavium",
+	0x44: "DEC",
+	0x46: "Fujitsu",
+	0x48: "HiSilicon",
+	0x49: "Infineon",
+	0x4d: "Motorola/Freescale",
+	0x4e: "NVIDIA",
+	0x50: "APM",
+	0x51: "Qualcomm",
+	0x53: "Samsung",
+	0x56: "Marvell",
+	0x61: "Apple",
+	0x66: "Faraday",
+	0x69: "Intel",
+	0x6d: "Microsoft",
+	0x70: "Phytium",
+	0xc0: "Ampere",
+}
+
+// midrParts maps implementer code and part number to the core name.
+var midrParts = map[uint32]map[uint32]string{
+	0x41: {
+		0xd03: "Cortex-A53",
+		0xd04: "Cortex-A35",
+		0xd05: "Cortex-A55",
+		0xd07: "Cortex-A57",
+		0xd08: "Cortex-A72",
+		0xd09: "Cortex-A73",
+		0xd0a: "Cortex-A75",
+		0xd0b: "Cortex-A76",
+		0xd0c: "Neoverse-N1",
+		0xd0d: "Cortex-A77",
+		0xd40: "Neoverse-V1",
+		0xd41: "Cortex-A78",
+		0xd44: "Cortex-X1",
+		0xd46: "Cortex-A510",
+		0xd47: "Cortex-A710",
+		0xd48: "Cortex-X2",
+		0xd49: "Neoverse-N2",
+		0xd4a: "Neoverse-E1",
+		0xd4f: "Neoverse-V2",
+		0xd84: "Neoverse-V3",
+		0xd8e: "Neoverse-N3",
+	},
+	0x43: {
+		0x0a1: "ThunderX 88XX",
+		0x0af: "ThunderX2 99xx",
+		0x0b8: "ThunderX3 T110",
+	},
+	0x46: {
+		0x001: "A64FX",
+	},
+	0x48: {
+		0xd01: "TaiShan-v110",
+		0xd02: "TaiShan-v120",
+	},
+	0x4e: {
+		0x004: "Carmel",
+	},
+	0x50: {
+		0x000: "X-Gene",
+	},
+	0x51: {
+		0xc00: "Falkor",
+		0xc01: "Saphira",
+	},
+	0x70: {
+		0x303: "FTC310",
+		0x660: "FTC660",
+		0x661: "FTC661",
+		0x662: "FTC662",
+		0x663: "FTC663",
+		0x664: "FTC664",
+		0x862: "FTC862",
+	},
+	0xc0: {
+		0xac3: "Ampere-1",
+		0xac4: "Ampere-1a",
+	},
+}
+
+// midr holds the decoded fields of a MIDR_EL1 value.
+type midr struct {
+	implementer  uint32
+	variant      uint32
+	architecture uint32
+	part         uint32
+	revision     uint32
+}
+
+// vendor returns the implementer name, or the hex code when unknown.
+func (m midr) vendor() string {
+	if name, ok := midrImplementers[m.implementer]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%02x", m.implementer)
+}
+
+// core returns the core name, or the hex part number when unknown.
+func (m midr) core() string {
+	if name, ok := midrParts[m.implementer][m.part]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%03x", m.part)
+}
+
+// String formats the MIDR as "r<variant>p<revision>" prefixed with the raw value.
+func (m midr) String() string {
+	raw := m.implementer<<24 | m.variant<<20 | m.architecture<<16 | m.part<<4 | m.revision
+	return fmt.Sprintf("0x%08x (r%dp%d)", raw, m.variant, m.revision)
+}
+
+// decodeMIDR splits a raw MIDR_EL1 value into its fields.
+func decodeMIDR(v uint64) midr {
+	return midr{
+		implementer:  uint32(v>>24) & 0xff,
+		variant:      uint32(v>>20) & 0xf,
+		architecture: uint32(v>>16) & 0xf,
+		part:         uint32(v>>4) & 0xfff,
+		revision:     uint32(v) & 0xf,
+	}
+}
+
+// readMIDRs returns the distinct MIDR values of all online CPUs. The sysfs
+// register file is preferred; /proc/cpuinfo is used when it is not exposed.
+func readMIDRs() ([]midr, error) {
+	seen := make(map[midr]bool, 2)
+
+	files, _ := filepath.Glob(filepath.Join(sysfsCPU, "cpu[0-9]*", "regs", "identification", "midr_el1"))
+	for _, file := range files {
+		line, err := utils.ReadOneLineFile(file)
+		if err != nil {
+			continue
+		}
+		v, err := strconv.ParseUint(strings.TrimPrefix(line, "0x"), 16, 64)
+		if err != nil {
+			continue
+		}
+		seen[decodeMIDR(v)] = true
+	}
+
+	if len(seen) == 0 {
+		procs, err := readCPUInfo()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, proc := range procs {
+			impl, err1 := strconv.ParseUint(proc["CPU implementer"], 0, 32)
+			part, err2 := strconv.ParseUint(proc["CPU part"], 0, 32)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			variant, _ := strconv.ParseUint(proc["CPU variant"], 0, 32)
+			revision, _ := strconv.ParseUint(proc["CPU revision"], 0, 32)
+
+			// "CPU architecture" is printed as 8 for AArch64, while the MIDR
+			// field reads 0xf ("defined by CPUID scheme") on every ARMv7+ core.
+			seen[midr{
+				implementer:  uint32(impl),
+				variant:      uint32(variant),
+				architecture: 0xf,
+				part:         uint32(part),
+				revision:     uint32(revision),
+			}] = true
+		}
+	}
+
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("no MIDR found in sysfs or %s", procCPUInfo)
+	}
+
+	res := make([]midr, 0, len(seen))
+	for m := range seen {
+		res = append(res, m)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].implementer != res[j].implementer {
+			return res[i].implementer < res[j].implementer
+		}
+		return res[i].part < res[j].part
+	})
+
+	return res, nil
+}
+
+// collectFromMIDR sets the vendor, core name and MIDR of an ARM processor.
+// lscpu on older distributions prints only the raw implementer code and no model
+// name, so the SMBIOS processor version is used as the model name when present.
+func (c *CPU) collectFromMIDR() error {
+	midrs, err := readMIDRs()
+	if err != nil {
+		return err
+	}
+
+	vendors := make([]string, 0, len(midrs))
+	cores := make([]string, 0, len(midrs))
+	raws := make([]string, 0, len(midrs))
+	for _, m := range midrs {
+		if v := m.vendor(); !slices.Contains(vendors, v) {
+			vendors = append(vendors, v)
+		}
+		if name := m.core(); !slices.Contains(cores, name) {
+			cores = append(cores, name)
+		}
+		raws = append(raws, m.String())
+	}
+
+	c.VendorID = strings.Join(vendors, "/")
+	c.CoreName = strings.Join(cores, "/")
+	c.MIDR = strings.Join(raws, ", ")
+
+	for _, entry := range c.CPUEntries {
+		if entry.populated && entry.Version != "" {
+			c.ModelName = entry.Version
+			break
+		}
+	}
+
+	if c.ModelName == "" || c.ModelName == "-" {
+		c.ModelName = c.CoreName
+	}
+
+	return nil
+}
+
+// isARM reports whether the running architecture is 32- or 64-bit ARM.
+func (c *CPU) isARM() bool {
+	return strings.HasPrefix(c.Architecture, archARM) || strings.HasPrefix(c.Architecture, "arm")
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/zenithax-cc/baize/internal/collector/smbios"
 	"github.com/zenithax-cc/baize/pkg/utils"
 )
 
@@ -30,21 +32,12 @@ const (
 	statusPopulatedEnabled = "Populated, Enabled"
 )
 
-// socketIDMap maps common socket designation strings to normalized zero-based
-// socket index strings, enabling consistent cross-platform CPU socket matching.
-var (
-	socketIDMap = map[string]string{
-		"P0": "0", "Proc 1": "0", "CPU 1": "0", "CPU01": "0", "CPU1": "0", "Socket 1": "0",
-		"P1": "1", "Proc 2": "1", "CPU 2": "1", "CPU02": "1", "CPU2": "1", "Socket 2": "1",
-	}
-)
-
 // New creates and returns a new CPU instance with default values:
-// HyperThreading is pre-set to "Supported Enabled" and PowerState to "PowerSaving".
+// HyperThreading is pre-set to "Supported Enabled"; PowerState is derived
+// from the power-management configuration during collection.
 func New() *CPU {
 	return &CPU{
 		HyperThreading: htSupported,
-		PowerState:     powerStatePowerSaving,
 		CPUEntries:     make([]*SMBIOSCPUEntry, 0, 2),
 	}
 }
@@ -53,6 +46,13 @@ func New() *CPU {
 // (lscpu, SMBIOS, turbostat) and then associating per-core data.
 // All errors from sub-collectors are joined and returned together.
 func (c *CPU) Collect(ctx context.Context) error {
+	// An SMBIOS dump describes another host: report its Type 4 sockets only,
+	// without this host's lscpu, threads, power or microcode data.
+	if smbios.FromDump() {
+		c.HyperThreading = ""
+		return c.collectFromSMBIOS(ctx)
+	}
+
 	errs := make([]error, 0, 4)
 
 	// Collect basic CPU info from lscpu command output.
@@ -65,19 +65,101 @@ func (c *CPU) Collect(ctx context.Context) error {
 		errs = append(errs, err)
 	}
 
-	// Collect per-thread frequency and temperature via turbostat.
-	if err := c.collectFromTurbostat(ctx); err != nil {
+	// Collect per-thread frequency and temperature via turbostat. turbostat is
+	// x86-only, so its absence is not reported on ARM.
+	if err := c.collectFromTurbostat(ctx); err != nil && !c.isARM() {
+		errs = append(errs, err)
+	}
+
+	// Fall back to sysfs topology and cpufreq so threads can still be
+	// associated to sockets and report their frequency.
+	if len(c.threads) == 0 {
+		threads, err := collectTopology()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		c.threads = threads
+
+		if err := c.collectFromCPUFreq(); err != nil && c.isARM() {
+			errs = append(errs, err)
+		}
+	}
+	c.ClustersPerSocket = c.clustersPerSocket()
+
+	// Measure package power from RAPL where turbostat did not report it.
+	if c.Watt == "" && !c.isARM() {
+		if err := c.collectFromRAPL(ctx); err != nil && !errors.Is(err, errNoRAPL) {
+			errs = append(errs, err)
+		}
+	}
+
+	// Collect cpufreq, cpuidle and uncore configuration and check the power profile.
+	if err := c.collectPowerManagement(); err != nil && expectedProfile != "" {
 		errs = append(errs, err)
 	}
 
+	// Decode MIDR into vendor and core names on ARM.
+	if c.isARM() {
+		if err := c.collectFromMIDR(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	// Associate threads and temperature readings to their SMBIOS CPU entries.
-	if err := c.associateCores(); err != nil {
+	if err := c.associateCores(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Collect thermal throttling counters. The therm_throt driver serves Intel
+	// and Zhaoxin only, so their absence is reported for Intel alone.
+	if err := c.collectThermalThrottle(ctx); err != nil && c.VendorID == "Intel" {
+		errs = append(errs, err)
+	}
+
+	// Collect kernel vulnerability status and mitigation overrides.
+	if err := c.collectVulnerabilities(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Collect the loaded microcode revision per socket and apply the policy.
+	if err := c.collectMicrocode(); err != nil {
 		errs = append(errs, err)
 	}
 
+	c.diagnose()
+
 	return errors.Join(errs...)
 }
 
+// diagnose evaluates the collected data and sets Diagnose and DiagnoseDetail.
+func (c *CPU) diagnose() {
+	var details []string
+
+	for _, m := range c.MicrocodeEntries {
+		switch m.Status {
+		case microcodeOutdated:
+			details = append(details, fmt.Sprintf("socket %s microcode %s is below minimum %s", m.PhysicalID, m.Revision, m.Minimum))
+		case microcodeInconsistent:
+			details = append(details, fmt.Sprintf("socket %s threads report different microcode revisions", m.PhysicalID))
+		}
+	}
+
+	details = append(details, c.temperatureMismatches...)
+	details = append(details, c.throttleIssues()...)
+
+	if pm := c.PowerManagement; pm != nil && pm.ProfileCompliance == profileNonCompliant {
+		details = append(details, fmt.Sprintf("power profile %q: %s", pm.Profile, strings.Join(pm.Deviations, ", ")))
+	}
+
+	if len(details) == 0 {
+		c.Diagnose = diagnoseHealthy
+		return
+	}
+
+	c.Diagnose = diagnoseUnhealthy
+	c.DiagnoseDetail = strings.Join(details, "; ")
+}
+
 // Name returns the collector identifier string used for module routing.
 func (c *CPU) Name() string {
 	return "cpu"
@@ -111,9 +193,9 @@ func (c *CPU) BriefPrintln() {
 }
 
 // associateCores links per-thread turbostat data (frequency, temperature) to
-// the corresponding SMBIOS CPU entry based on socket designation mapping.
-// It also collects vendor-specific per-core temperatures (Intel/AMD).
-func (c *CPU) associateCores() error {
+// the corresponding SMBIOS CPU entry based on the socket mapping.
+// It also collects vendor-specific per-core temperatures.
+func (c *CPU) associateCores(ctx context.Context) error {
 
 	var (
 		err     error
@@ -121,25 +203,57 @@ func (c *CPU) associateCores() error {
 		tempMap map[string]int
 	)
 
+	// Resolve every populated socket to a kernel physical package ID.
+	if err := c.mapSockets(); err != nil {
+		errs = append(errs, err)
+	}
+
 	// Collect per-core temperatures according to the CPU vendor.
 	switch c.VendorID {
 	case "Intel":
 		tempMap, err = collectIntelTemperature()
-	case "AMD":
-		tempMap, err = collectAMDTemperature()
+	case "AMD", "Hygon":
+		tempMap, err = c.collectK10Temperature()
+		if err == nil {
+			err = c.crossCheckIPMITemperature(ctx, tempMap)
+		}
+	case "Zhaoxin":
+		tempMap, err = collectZhaoxinTemperature()
+	default:
+		if c.isARM() {
+			tempMap, err = collectSoCTemperature(len(c.kernelPackages()))
+		}
+	}
+
+	// turbostat provides the package temperature on x86 only; elsewhere report
+	// the hottest package-level reading (keys without a "-<coreID>" suffix).
+	if c.TemperatureCelsius == "" {
+		hottest, found := 0, false
+		for key, t := range tempMap {
+			if strings.Contains(key, "-") {
+				continue
+			}
+			if !found || t > hottest {
+				hottest, found = t, true
+			}
+		}
+		if found {
+			c.TemperatureCelsius = fmt.Sprintf("%d ℃", hottest)
+		}
 	}
 
 	if err != nil {
 		errs = append(errs, err)
 	}
+	c.temperatures = tempMap
 
 	for _, entry := range c.CPUEntries {
-		// Resolve the socket designation to a normalized physical ID index.
-		id, ok := socketIDMap[entry.SocketDesignation]
-		if !ok {
-			errs = append(errs, errors.New("socket designation not found"))
+		if entry.PhysicalID == "" {
 			continue
 		}
+		if temp, ok := tempMap[entry.PhysicalID]; ok {
+			entry.Temperature = fmt.Sprintf("%d ℃", temp)
+		}
 		for _, thread := range c.threads {
 			// Assign temperature from per-core key (physicalID-coreID) if available.
 			if temp, ok := tempMap[thread.PhysicalID+"-"+thread.CoreID]; ok {
@@ -152,7 +266,7 @@ func (c *CPU) associateCores() error {
 			}
 
 			// Attach this thread to the matching SMBIOS CPU entry.
-			if thread.PhysicalID == id {
+			if thread.PhysicalID == entry.PhysicalID {
 				entry.ThreadEntries = append(entry.ThreadEntries, thread)
 			}
 		}
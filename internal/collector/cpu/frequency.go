@@ -1,15 +1,18 @@
 // Package cpu - frequency.go collects per-thread CPU frequency and power metrics
-// using the turbostat tool and parses its columnar stderr output.
+// using the turbostat tool and parses its columnar stderr output. When turbostat
+// is unavailable (e.g. on ARM), frequencies are read from the cpufreq sysfs.
 package cpu
 
 import (
 	"bytes"
 	"context"
 	"errors"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/zenithax-cc/baize/pkg/execute"
+	"github.com/zenithax-cc/baize/pkg/utils"
 )
 
 const (
@@ -62,7 +65,7 @@ func (c *CPU) collectFromTurbostat(ctx context.Context) error {
 
 	// Populate package-level temperature and power from the summary line.
 	if idx, ok := headerIndex[coreTmp]; ok && idx < len(summaryLine) {
-		c.TemperatureCelsius = summaryLine[idx] + " °C"
+		c.TemperatureCelsius = summaryLine[idx] + " ℃"
 	}
 	if idx, ok := headerIndex[pkgWatt]; ok && idx < len(summaryLine) {
 		c.Watt = summaryLine[idx] + " W"
@@ -115,9 +118,13 @@ func (c *CPU) collectFromTurbostat(ctx context.Context) error {
 	}
 
 	// If the minimum busy frequency is notably above the base (TSC) frequency,
-	// the CPU is running in performance governor mode.
+	// the CPU is running in performance mode. This is only an estimate for
+	// hosts without cpufreq; collectPowerManagement overrides it from the
+	// configured governor and EPP.
 	if minFreq-50 > baseFreq {
 		c.PowerState = powerStatePerformance
+	} else {
+		c.PowerState = powerStatePowerSaving
 	}
 
 	c.MaxFreqMHz = formatMHz(maxFreq)
@@ -146,3 +153,81 @@ func getFloatValue(key string, header []string, headerIndex map[string]int) floa
 	}
 	return -1
 }
+
+// collectFromCPUFreq fills per-thread frequencies and the base/min/max summary
+// from /sys/devices/system/cpu/cpu*/cpufreq. The base frequency is taken from
+// the ACPI CPPC nominal frequency (ARM servers), the intel_pstate base frequency
+// or, failing both, the SMBIOS Type 4 current speed. cpuinfo_max_freq is the
+// boost maximum on most ARM servers, so it is never reported as the base.
+func (c *CPU) collectFromCPUFreq() error {
+	if len(c.threads) == 0 {
+		return errors.New("no cpu threads for cpufreq collection")
+	}
+
+	var minFreq, maxFreq, baseFreq int
+	for _, thread := range c.threads {
+		dir := filepath.Join(sysfsCPU, "cpu"+thread.ProcessorID)
+
+		cur, err := utils.ReadSysfsInt(filepath.Join(dir, "cpufreq", "scaling_cur_freq"))
+		if err != nil {
+			continue
+		}
+		mhz := cur / 1000
+		thread.CoreFrequency = formatMHz(mhz)
+
+		if mhz > maxFreq {
+			maxFreq = mhz
+		}
+		if minFreq == 0 || mhz < minFreq {
+			minFreq = mhz
+		}
+
+		if baseFreq == 0 {
+			baseFreq = readBaseFreq(dir)
+		}
+	}
+
+	if maxFreq == 0 {
+		return errors.New("cpufreq is not available")
+	}
+
+	c.MaxFreqMHz = formatMHz(maxFreq)
+	c.MinFreqMHz = formatMHz(minFreq)
+	if baseFreq == 0 {
+		baseFreq = c.smbiosSpeed()
+	}
+	if baseFreq > 0 {
+		c.BasedFreqMHz = formatMHz(baseFreq)
+	}
+
+	return nil
+}
+
+// readBaseFreq returns the base frequency in MHz of the CPU whose sysfs
+// directory is dir, or 0 when no source is available.
+func readBaseFreq(dir string) int {
+	if v, err := utils.ReadSysfsInt(filepath.Join(dir, "acpi_cppc", "nominal_freq")); err == nil && v > 0 {
+		return v
+	}
+
+	if v, err := utils.ReadSysfsInt(filepath.Join(dir, "cpufreq", "base_frequency")); err == nil && v > 0 {
+		return v / 1000
+	}
+
+	return 0
+}
+
+// smbiosSpeed returns the current speed in MHz of the first populated SMBIOS
+// processor, or 0 when firmware does not report it.
+func (c *CPU) smbiosSpeed() int {
+	for _, e := range c.CPUEntries {
+		if !e.populated {
+			continue
+		}
+		if mhz, err := strconv.Atoi(strings.TrimSuffix(e.CurrentSpeed, " MHz")); err == nil && mhz > 0 {
+			return mhz
+		}
+	}
+
+	return 0
+}
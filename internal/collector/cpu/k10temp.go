@@ -0,0 +1,274 @@
+// Package cpu - k10temp.go reads AMD and Hygon processor temperatures from the
+// k10temp hwmon driver. Each k10temp instance is bound to the data fabric
+// function 3 PCI device of one node (0000:00:18.3 for node 0, 00:19.3 for node
+// 1, ...), which is how readings are attributed to sockets. IPMI readings can
+// optionally be compared against k10temp as a cross-check.
+package cpu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/baize/pkg/execute"
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+const (
+	ipmitool = "/usr/bin/ipmitool"
+
+	// k10tempFirstNodeDevice is the PCI device number of node 0's data fabric.
+	k10tempFirstNodeDevice = 0x18
+
+	// ipmiTempTolerance is the largest accepted difference in °C between the
+	// k10temp and IPMI readings of a socket.
+	ipmiTempTolerance = 10
+)
+
+// k10tempNames lists hwmon names of the AMD k10temp driver and the Hygon builds
+// of it shipped by vendor kernels.
+var k10tempNames = map[string]bool{
+	"k10temp":    true,
+	"hygon_temp": true,
+}
+
+// ipmiCPUTempRegex matches BMC CPU temperature sensor names such as
+// "CPU1 Temp", "CPU0_Temp", "P1 Temp" or "Proc 2 Temp".
+var ipmiCPUTempRegex = regexp.MustCompile(`(?i)^(?:cpu|proc|p)[\s_]*(\d+)[\s_-]*(?:temp|dts|tctl)`)
+
+// ipmiCrossCheck enables comparing k10temp readings with the BMC sensors.
+var ipmiCrossCheck bool
+
+// SetIPMICrossCheck enables or disables the IPMI temperature cross-check for
+// AMD and Hygon processors. It is disabled by default because ipmitool sdr
+// queries are slow on many BMCs.
+func SetIPMICrossCheck(enable bool) {
+	ipmiCrossCheck = enable
+}
+
+// k10tempNode holds the readings of one k10temp instance.
+type k10tempNode struct {
+	node int
+	tctl int
+	tdie int
+	// hasTdie is set when the driver reports Tdie (Tctl with offset removed).
+	hasTdie bool
+	ccds    []ccdTemp
+}
+
+// ccdTemp is the temperature of one core complex die.
+type ccdTemp struct {
+	index int
+	temp  int
+}
+
+// control returns Tdie when available and Tctl otherwise.
+func (n k10tempNode) control() int {
+	if n.hasTdie {
+		return n.tdie
+	}
+	return n.tctl
+}
+
+// readK10tempNodes returns the readings of every k10temp instance, ordered by node.
+func readK10tempNodes() ([]k10tempNode, error) {
+	dirs := hwmonByName(k10tempNames)
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no k10temp hwmon device found under %s", hwmon)
+	}
+
+	res := make([]k10tempNode, 0, len(dirs))
+	for _, dir := range dirs {
+		bdf, err := utils.ReadLinkBase(filepath.Join(dir, "device"))
+		if err != nil {
+			continue
+		}
+
+		node, ok := dfNode(bdf)
+		if !ok {
+			continue
+		}
+
+		n := k10tempNode{node: node}
+		hasTctl := false
+		for label, temp := range hwmonLabels(dir) {
+			switch {
+			case label == "Tctl":
+				n.tctl, hasTctl = temp, true
+			case label == "Tdie":
+				n.tdie, n.hasTdie = temp, true
+			case strings.HasPrefix(label, "Tccd"):
+				idx, err := strconv.Atoi(strings.TrimPrefix(label, "Tccd"))
+				if err == nil {
+					n.ccds = append(n.ccds, ccdTemp{index: idx, temp: temp})
+				}
+			}
+		}
+
+		if !hasTctl && !n.hasTdie {
+			continue
+		}
+
+		sort.Slice(n.ccds, func(i, j int) bool { return n.ccds[i].index < n.ccds[j].index })
+		res = append(res, n)
+	}
+
+	if len(res) == 0 {
+		return nil, errors.New("k10temp hwmon reports no Tctl/Tdie")
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].node < res[j].node })
+
+	return res, nil
+}
+
+// dfNode extracts the node number from a data fabric PCI address such as
+// "0000:00:19.3". The DF devices of all nodes live on bus 0 from device 0x18.
+func dfNode(bdf string) (int, bool) {
+	parts := strings.Split(bdf, ":")
+	if len(parts) != 3 {
+		return 0, false
+	}
+
+	dev, _, ok := strings.Cut(parts[2], ".")
+	if !ok {
+		return 0, false
+	}
+
+	d, err := strconv.ParseUint(dev, 16, 8)
+	if err != nil || d < k10tempFirstNodeDevice {
+		return 0, false
+	}
+
+	return int(d) - k10tempFirstNodeDevice, true
+}
+
+// collectK10Temperature maps k10temp nodes to physical packages and returns
+// the package temperatures keyed by physical ID. First-generation EPYC has
+// several nodes per package; nodes are numbered package by package, so the
+// hottest node of each package is used. Per-CCD readings are stored on the
+// matching SMBIOS entry.
+func (c *CPU) collectK10Temperature() (map[string]int, error) {
+	nodes, err := readK10tempNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs := c.kernelPackages()
+	if len(pkgs) == 0 {
+		pkgs = []packageInfo{{id: 0, minAPIC: -1}}
+	}
+
+	nodesPerPkg := max(len(nodes)/len(pkgs), 1)
+
+	res := make(map[string]int, len(pkgs))
+	ccds := make(map[string][]string, len(pkgs))
+	for _, n := range nodes {
+		idx := n.node / nodesPerPkg
+		if idx >= len(pkgs) {
+			continue
+		}
+		pid := strconv.Itoa(pkgs[idx].id)
+
+		if cur, ok := res[pid]; !ok || n.control() > cur {
+			res[pid] = n.control()
+		}
+		for _, ccd := range n.ccds {
+			ccds[pid] = append(ccds[pid], fmt.Sprintf("Tccd%d %d ℃", ccd.index, ccd.temp))
+		}
+	}
+
+	for _, entry := range c.CPUEntries {
+		if v, ok := ccds[entry.PhysicalID]; ok {
+			entry.CCDTemperature = strings.Join(v, ", ")
+		}
+	}
+
+	return res, nil
+}
+
+// collectIPMITemperature reads CPU temperature sensors from the BMC without a
+// shell. Sensor numbering may be zero- or one-based; the result is keyed by
+// zero-based socket index.
+func collectIPMITemperature(ctx context.Context) (map[int]int, error) {
+	output := execute.CommandWithContext(ctx, ipmitool, "sdr", "type", "temperature")
+	if output.Err != nil {
+		return nil, output.Err
+	}
+
+	raw := make(map[int]int, 2)
+	base := 1
+	for _, line := range strings.Split(string(output.Stdout), "\n") {
+		// e.g. "CPU1 Temp | 30h | ok | 3.1 | 45 degrees C"
+		parts := strings.Split(line, "|")
+		if len(parts) != 5 {
+			continue
+		}
+
+		m := ipmiCPUTempRegex.FindStringSubmatch(strings.TrimSpace(parts[0]))
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+
+		fields := strings.Fields(parts[4])
+		if len(fields) < 2 || fields[1] != "degrees" {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+
+		if n == 0 {
+			base = 0
+		}
+		raw[n] = int(value)
+	}
+
+	if len(raw) == 0 {
+		return nil, errors.New("no CPU temperature sensor found in ipmitool sdr output")
+	}
+
+	res := make(map[int]int, len(raw))
+	for n, v := range raw {
+		res[n-base] = v
+	}
+
+	return res, nil
+}
+
+// crossCheckIPMITemperature compares package temperatures with the BMC CPU
+// sensors when the cross-check is enabled and ipmitool is installed, and
+// records sockets whose readings differ by more than ipmiTempTolerance.
+func (c *CPU) crossCheckIPMITemperature(ctx context.Context, temps map[string]int) error {
+	if !ipmiCrossCheck || len(temps) == 0 || !utils.FileExists(ipmitool) {
+		return nil
+	}
+
+	ipmi, err := collectIPMITemperature(ctx)
+	if err != nil {
+		return fmt.Errorf("ipmi temperature cross-check: %w", err)
+	}
+
+	for i, p := range c.kernelPackages() {
+		pid := strconv.Itoa(p.id)
+		local, ok1 := temps[pid]
+		bmc, ok2 := ipmi[i]
+		if !ok1 || !ok2 {
+			continue
+		}
+
+		if diff := local - bmc; diff > ipmiTempTolerance || diff < -ipmiTempTolerance {
+			c.temperatureMismatches = append(c.temperatureMismatches,
+				fmt.Sprintf("socket %s temperature k10temp %d ℃ differs from IPMI %d ℃", pid, local, bmc))
+		}
+	}
+
+	return nil
+}
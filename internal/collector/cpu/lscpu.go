@@ -2,6 +2,8 @@ package cpu
 
 import (
 	"bytes"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/zenithax-cc/baize/pkg/execute"
@@ -16,6 +18,9 @@ var (
 	vendorMap = map[string]string{
 		"AuthenticAMD": "AMD",
 		"GenuineIntel": "Intel",
+		"HygonGenuine": "Hygon",
+		"CentaurHauls": "Zhaoxin",
+		"Shanghai":     "Zhaoxin",
 		"0x48":         "HiSilicon",
 	}
 )
@@ -42,7 +47,8 @@ var lscpuFieldSetters = map[string]fieldSetter{
 	"Core(s) per socket":  func(info *CPU, value string) { info.CoresPerSocket = value },
 	"Socket(s)":           func(info *CPU, value string) { info.Sockets = value },
 	"Vendor ID": func(info *CPU, value string) {
-		if vendor, ok := vendorMap[value]; ok {
+		// Zhaoxin reports "  Shanghai  " with padding spaces.
+		if vendor, ok := vendorMap[strings.TrimSpace(value)]; ok {
 			info.VendorID = vendor
 		} else {
 			info.VendorID = value
@@ -80,3 +86,40 @@ func (c *CPU) collectFromLscpu() error {
 
 	return scanner.Err()
 }
+
+// Signature identifies a CPU generation by vendor and the CPUID family and
+// model of the first processor, as decoded by the kernel.
+type Signature struct {
+	// Vendor is normalized like CPU.VendorID, e.g. "Intel" or "AMD".
+	Vendor string
+	Family int
+	Model  int
+}
+
+// ReadSignature reads the CPU signature from /proc/cpuinfo.
+func ReadSignature() (Signature, error) {
+	procs, err := readCPUInfo()
+	if err != nil {
+		return Signature{}, err
+	}
+	if len(procs) == 0 {
+		return Signature{}, fmt.Errorf("no processor in %s", procCPUInfo)
+	}
+
+	proc := procs[0]
+	vendor := strings.TrimSpace(proc["vendor_id"])
+	if v, ok := vendorMap[vendor]; ok {
+		vendor = v
+	}
+
+	family, err := strconv.Atoi(proc["cpu family"])
+	if err != nil {
+		return Signature{}, fmt.Errorf("cpu family %q: %w", proc["cpu family"], err)
+	}
+	model, err := strconv.Atoi(proc["model"])
+	if err != nil {
+		return Signature{}, fmt.Errorf("model %q: %w", proc["model"], err)
+	}
+
+	return Signature{Vendor: vendor, Family: family, Model: model}, nil
+}
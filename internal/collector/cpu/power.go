@@ -0,0 +1,340 @@
+// Package cpu - power.go reports the CPU power-management configuration from
+// sysfs: cpufreq scaling driver and governor, EPP/EPB, turbo state, cpuidle
+// C-states and Intel uncore frequency limits. It also checks the configuration
+// against an expected power profile.
+package cpu
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+const (
+	cpuidleDir = "/sys/devices/system/cpu/cpuidle"
+	uncoreDir  = "/sys/devices/system/cpu/intel_uncore_frequency"
+
+	turboEnabled  = "Enabled"
+	turboDisabled = "Disabled"
+
+	profileCompliant    = "Compliant"
+	profileNonCompliant = "Non-compliant"
+)
+
+// epbNames maps the x86 energy_perf_bias value to the kernel's named presets.
+var epbNames = map[int]string{
+	0:  "performance",
+	4:  "balance-performance",
+	6:  "normal",
+	8:  "balance-power",
+	15: "power",
+}
+
+// powerProfile describes the settings expected by a named power profile. Empty
+// lists and strings accept any value.
+type powerProfile struct {
+	governors []string
+	epps      []string
+	turbo     string
+}
+
+// powerProfiles lists the supported profile names. intel_pstate and amd-pstate
+// in active mode only offer the "performance" and "powersave" governors, so the
+// balanced profile is expressed mostly through EPP.
+var powerProfiles = map[string]powerProfile{
+	"performance": {
+		governors: []string{"performance"},
+		epps:      []string{"performance"},
+		turbo:     turboEnabled,
+	},
+	"balanced": {
+		governors: []string{"powersave", "schedutil", "ondemand", "conservative"},
+		epps:      []string{"default", "balance_performance", "balance_power"},
+	},
+	"powersave": {
+		governors: []string{"powersave", "conservative"},
+		epps:      []string{"balance_power", "power"},
+	},
+}
+
+// expectedProfile is the profile hosts are checked against; empty disables the check.
+var expectedProfile string
+
+// SetPowerProfile sets the power profile every host is expected to follow.
+// Supported names are "performance", "balanced" and "powersave".
+func SetPowerProfile(name string) error {
+	if _, ok := powerProfiles[name]; !ok && name != "" {
+		names := slices.Sorted(maps.Keys(powerProfiles))
+		return fmt.Errorf("unknown power profile %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+
+	expectedProfile = name
+
+	return nil
+}
+
+// collectPowerManagement reads the power-management configuration of every
+// thread and summarizes it into c.PowerManagement.
+func (c *CPU) collectPowerManagement() error {
+	pm := &PowerManagement{}
+
+	governors := make(map[string]int, 2)
+	epps := make(map[string]int, 2)
+	for _, thread := range c.threads {
+		dir := filepath.Join(sysfsCPU, "cpu"+thread.ProcessorID, "cpufreq")
+
+		if v, err := utils.ReadOneLineFile(filepath.Join(dir, "scaling_governor")); err == nil {
+			thread.Governor = v
+			governors[v]++
+		}
+		if v, err := utils.ReadOneLineFile(filepath.Join(dir, "energy_performance_preference")); err == nil {
+			thread.EPP = v
+			epps[v]++
+		}
+		if pm.ScalingDriver == "" {
+			pm.ScalingDriver, _ = utils.ReadOneLineFile(filepath.Join(dir, "scaling_driver"))
+		}
+	}
+
+	pm.Governor = summarizeCounts(governors)
+	pm.EPP = summarizeCounts(epps)
+	pm.DriverStatus = readPstateStatus(pm.ScalingDriver)
+	pm.EPB = readEPB()
+	pm.Turbo = readTurbo()
+	pm.CPUIdleDriver, _ = utils.ReadOneLineFile(filepath.Join(cpuidleDir, "current_driver"))
+	pm.CPUIdleGovernor, _ = utils.ReadOneLineFile(filepath.Join(cpuidleDir, "current_governor_ro"))
+	if pm.CPUIdleGovernor == "" {
+		pm.CPUIdleGovernor, _ = utils.ReadOneLineFile(filepath.Join(cpuidleDir, "current_governor"))
+	}
+	pm.CStates = c.readCStates()
+	pm.UncoreFrequencies = readUncoreFrequencies()
+
+	if len(governors) == 0 && len(epps) == 0 && len(pm.CStates) == 0 && pm.CPUIdleDriver == "" {
+		return fmt.Errorf("no cpufreq or cpuidle information under %s", sysfsCPU)
+	}
+
+	// Derive the power state from the configuration rather than measured
+	// frequencies: the performance governor or EPP pins the CPU at high clocks.
+	if len(governors) > 0 || len(epps) > 0 {
+		c.PowerState = powerStatePowerSaving
+		if (len(governors) == 1 && governors["performance"] > 0) || (len(epps) == 1 && epps["performance"] > 0) {
+			c.PowerState = powerStatePerformance
+		}
+	}
+
+	if expectedProfile != "" {
+		pm.Profile = expectedProfile
+		pm.Deviations = checkPowerProfile(powerProfiles[expectedProfile], governors, epps, pm.Turbo)
+		pm.ProfileCompliance = profileCompliant
+		if len(pm.Deviations) > 0 {
+			pm.ProfileCompliance = profileNonCompliant
+		}
+	}
+
+	c.PowerManagement = pm
+
+	return nil
+}
+
+// checkPowerProfile returns one message per setting that deviates from p.
+func checkPowerProfile(p powerProfile, governors, epps map[string]int, turbo string) []string {
+	var res []string
+
+	for _, g := range slices.Sorted(maps.Keys(governors)) {
+		if len(p.governors) > 0 && !slices.Contains(p.governors, g) {
+			res = append(res, fmt.Sprintf("%d CPUs use governor %q", governors[g], g))
+		}
+	}
+
+	for _, e := range slices.Sorted(maps.Keys(epps)) {
+		if len(p.epps) > 0 && !slices.Contains(p.epps, e) {
+			res = append(res, fmt.Sprintf("%d CPUs use EPP %q", epps[e], e))
+		}
+	}
+
+	if p.turbo != "" && turbo != "" && turbo != p.turbo {
+		res = append(res, "turbo is "+strings.ToLower(turbo))
+	}
+
+	return res
+}
+
+// readPstateStatus returns the operating mode (active/passive/guided) of the
+// intel_pstate or amd-pstate driver.
+func readPstateStatus(driver string) string {
+	var dir string
+	switch {
+	case strings.HasPrefix(driver, "intel_"):
+		dir = "intel_pstate"
+	case strings.HasPrefix(driver, "amd-pstate"):
+		dir = "amd_pstate"
+	default:
+		return ""
+	}
+
+	status, _ := utils.ReadOneLineFile(filepath.Join(sysfsCPU, dir, "status"))
+	return status
+}
+
+// readEPB returns the energy_perf_bias of cpu0 with its preset name.
+func readEPB() string {
+	v, err := utils.ReadSysfsInt(filepath.Join(sysfsCPU, "cpu0", "power", "energy_perf_bias"))
+	if err != nil {
+		return ""
+	}
+
+	if name, ok := epbNames[v]; ok {
+		return fmt.Sprintf("%d (%s)", v, name)
+	}
+	return strconv.Itoa(v)
+}
+
+// readTurbo returns whether turbo/boost is enabled. intel_pstate exposes
+// no_turbo; acpi-cpufreq and amd-pstate expose a global or per-policy boost flag.
+func readTurbo() string {
+	if v, err := utils.ReadSysfsInt(filepath.Join(sysfsCPU, "intel_pstate", "no_turbo")); err == nil {
+		if v == 0 {
+			return turboEnabled
+		}
+		return turboDisabled
+	}
+
+	paths := []string{filepath.Join(sysfsCPU, "cpufreq", "boost")}
+	if policies, err := filepath.Glob(filepath.Join(sysfsCPU, "cpufreq", "policy*", "boost")); err == nil {
+		paths = append(paths, policies...)
+	}
+
+	for _, path := range paths {
+		if v, err := utils.ReadSysfsInt(path); err == nil {
+			if v == 1 {
+				return turboEnabled
+			}
+			return turboDisabled
+		}
+	}
+
+	return ""
+}
+
+// readCStates aggregates the cpuidle states of all threads. Usage and residency
+// time are summed; a state is reported as disabled on the threads that have it
+// disabled.
+func (c *CPU) readCStates() []*CState {
+	var (
+		states []*CState
+		byName = make(map[string]*CState, 8)
+		times  = make(map[string]uint64, 8)
+	)
+
+	for _, thread := range c.threads {
+		dirs, err := filepath.Glob(filepath.Join(sysfsCPU, "cpu"+thread.ProcessorID, "cpuidle", "state[0-9]*"))
+		if err != nil {
+			continue
+		}
+		sortByIndex(dirs, "state")
+
+		for _, dir := range dirs {
+			name, err := utils.ReadOneLineFile(filepath.Join(dir, "name"))
+			if err != nil {
+				continue
+			}
+
+			st, ok := byName[name]
+			if !ok {
+				st = &CState{Name: name}
+				st.Description, _ = utils.ReadOneLineFile(filepath.Join(dir, "desc"))
+				if v, err := utils.ReadOneLineFile(filepath.Join(dir, "latency")); err == nil {
+					st.Latency = v + " us"
+				}
+				if v, err := utils.ReadOneLineFile(filepath.Join(dir, "residency")); err == nil {
+					st.TargetResidency = v + " us"
+				}
+				byName[name] = st
+				states = append(states, st)
+			}
+
+			st.cpus++
+			if v, err := utils.ReadSysfsInt(filepath.Join(dir, "disable")); err == nil && v != 0 {
+				st.disabled++
+			}
+			if v, err := utils.ReadSysfsUint64(filepath.Join(dir, "usage")); err == nil {
+				st.usage += v
+			}
+			if v, err := utils.ReadSysfsUint64(filepath.Join(dir, "time")); err == nil {
+				times[name] += v
+			}
+		}
+	}
+
+	for _, st := range states {
+		switch {
+		case st.disabled == 0:
+			st.Disabled = "No"
+		case st.disabled == st.cpus:
+			st.Disabled = "Yes"
+		default:
+			st.Disabled = fmt.Sprintf("Partial (%d/%d CPUs)", st.disabled, st.cpus)
+		}
+		st.Usage = strconv.FormatUint(st.usage, 10)
+		st.Residency = fmt.Sprintf("%.1f s", float64(times[st.Name])/1e6)
+	}
+
+	return states
+}
+
+// readUncoreFrequencies reads the Intel uncore frequency limits of every
+// package/die (or power domain on newer kernels).
+func readUncoreFrequencies() []*UncoreFrequency {
+	entries, err := os.ReadDir(uncoreDir)
+	if err != nil {
+		return nil
+	}
+
+	khz := func(dir, name string) string {
+		v, err := utils.ReadSysfsInt(filepath.Join(dir, name))
+		if err != nil {
+			return ""
+		}
+		return formatMHz(v / 1000)
+	}
+
+	res := make([]*UncoreFrequency, 0, len(entries))
+	for _, e := range entries {
+		dir := filepath.Join(uncoreDir, e.Name())
+		if !utils.FileExists(filepath.Join(dir, "max_freq_khz")) {
+			continue
+		}
+
+		res = append(res, &UncoreFrequency{
+			Name:       e.Name(),
+			MinFreq:    khz(dir, "min_freq_khz"),
+			MaxFreq:    khz(dir, "max_freq_khz"),
+			InitialMin: khz(dir, "initial_min_freq_khz"),
+			InitialMax: khz(dir, "initial_max_freq_khz"),
+			Current:    khz(dir, "current_freq_khz"),
+		})
+	}
+
+	return res
+}
+
+// summarizeCounts formats a value histogram as "performance" when uniform or
+// "performance (8), powersave (56)" when mixed.
+func summarizeCounts(counts map[string]int) string {
+	keys := slices.Sorted(maps.Keys(counts))
+	if len(keys) <= 1 {
+		return strings.Join(keys, "")
+	}
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s (%d)", k, counts[k]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,102 @@
+// Package cpu - rapl.go measures package power from the powercap RAPL energy
+// counters. It is used when turbostat does not report PkgWatt, which is the
+// case on Hygon, Zhaoxin and hosts without turbostat installed.
+package cpu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+const (
+	powercap = "/sys/class/powercap"
+
+	// raplInterval is the sampling window between two energy readings.
+	raplInterval = time.Second
+)
+
+// errNoRAPL is returned when the kernel exposes no RAPL package domain.
+var errNoRAPL = errors.New("no RAPL package domain found under " + powercap)
+
+// raplDomain is one package-level RAPL zone (e.g. intel-rapl:0 "package-0").
+type raplDomain struct {
+	path     string
+	maxRange uint64 // max_energy_range_uj, used to handle counter wrap-around
+}
+
+// raplPackageDomains returns the top-level package zones. AMD and Hygon kernels
+// register their RAPL counters under the same "intel-rapl" control type.
+func raplPackageDomains() []raplDomain {
+	dirs, err := filepath.Glob(filepath.Join(powercap, "intel-rapl:*"))
+	if err != nil {
+		return nil
+	}
+
+	res := make([]raplDomain, 0, 2)
+	for _, dir := range dirs {
+		// Sub-zones such as intel-rapl:0:0 (core) are nested domains.
+		if strings.Count(filepath.Base(dir), ":") != 1 {
+			continue
+		}
+
+		name, err := utils.ReadOneLineFile(filepath.Join(dir, "name"))
+		if err != nil || !strings.HasPrefix(name, "package") {
+			continue
+		}
+
+		maxRange, _ := utils.ReadSysfsUint64(filepath.Join(dir, "max_energy_range_uj"))
+		res = append(res, raplDomain{path: dir, maxRange: maxRange})
+	}
+
+	return res
+}
+
+// collectFromRAPL samples the package energy counters over raplInterval and
+// stores the summed package power in c.Watt.
+func (c *CPU) collectFromRAPL(ctx context.Context) error {
+	domains := raplPackageDomains()
+	if len(domains) == 0 {
+		return errNoRAPL
+	}
+
+	start := make([]uint64, len(domains))
+	for i, d := range domains {
+		v, err := utils.ReadSysfsUint64(filepath.Join(d.path, "energy_uj"))
+		if err != nil {
+			return fmt.Errorf("read RAPL energy: %w", err)
+		}
+		start[i] = v
+	}
+
+	begin := time.Now()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(raplInterval):
+	}
+	elapsed := time.Since(begin).Seconds()
+
+	var totalUJ uint64
+	for i, d := range domains {
+		v, err := utils.ReadSysfsUint64(filepath.Join(d.path, "energy_uj"))
+		if err != nil {
+			return fmt.Errorf("read RAPL energy: %w", err)
+		}
+
+		if v >= start[i] {
+			totalUJ += v - start[i]
+		} else if d.maxRange > 0 {
+			totalUJ += d.maxRange - start[i] + v
+		}
+	}
+
+	c.Watt = fmt.Sprintf("%.2f W", float64(totalUJ)/1e6/elapsed)
+
+	return nil
+}
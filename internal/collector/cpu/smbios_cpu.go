@@ -18,9 +18,10 @@ func (c *CPU) collectFromSMBIOS(ctx context.Context) error {
 		c.CPUEntries = append(c.CPUEntries, &SMBIOSCPUEntry{
 			SocketDesignation: cpu.SocketDesignation,
 			ProcessorType:     cpu.ProcessorType.String(),
-			Family:            cpu.GetFamily().String(),
+			Family:            cpu.GetFamilyName(),
 			Manufacturer:      cpu.Manufacturer,
 			Version:           cpu.Version,
+			Signature:         cpu.GetSignature(),
 			ExternalClock:     formatMHz(int(cpu.ExternalClock)),
 			CurrentSpeed:      formatMHz(int(cpu.CurrentSpeed)),
 			Status:            cpu.Status.String(),
@@ -29,6 +30,8 @@ func (c *CPU) collectFromSMBIOS(ctx context.Context) error {
 			CoreEnabled:       strconv.Itoa(cpu.GetCoreEnabled()),
 			ThreadCount:       strconv.Itoa(cpu.GetThreadCount()),
 			Characteristics:   cpu.Characteristics.StringList(),
+			handle:            cpu.Handle,
+			populated:         cpu.Status&0x40 != 0,
 		})
 	}
 
@@ -7,12 +7,18 @@ type CPU struct {
 	// ModelName is the human-readable CPU model string (e.g., "Intel(R) Xeon(R) ...").
 	ModelName string `json:"model_name,omitempty" name:"Model" output:"both" color:"defaultGreen"`
 	// VendorID is the normalized CPU vendor name (e.g., "Intel", "AMD", "ARM").
-	VendorID     string `json:"vendor_id,omitempty" name:"Vendor" output:"both"`
+	VendorID string `json:"vendor_id,omitempty" name:"Vendor" output:"both"`
+	// CoreName is the microarchitecture decoded from MIDR_EL1 on ARM (e.g., "Neoverse-N1").
+	CoreName string `json:"core_name,omitempty" name:"Core Name" output:"both"`
+	// MIDR lists the distinct raw MIDR_EL1 values with their revision (ARM only).
+	MIDR         string `json:"midr,omitempty" name:"MIDR" output:"detail"`
 	Architecture string `json:"architecture,omitempty" name:"Architecture" output:"both"`
 	// Sockets is the total number of physical CPU sockets detected by lscpu.
 	Sockets        string `json:"sockets,omitempty" name:"Socket(s)" output:"both"`
 	CoresPerSocket string `json:"cores_per_socket,omitempty" name:"Cores Per Socket" output:"both"`
 	ThreadsPerCore string `json:"threads_per_core,omitempty" name:"Threads Per Core" output:"both"`
+	// ClustersPerSocket is the number of core clusters per socket from sysfs topology.
+	ClustersPerSocket string `json:"clusters_per_socket,omitempty" name:"Clusters Per Socket" output:"detail"`
 	// HyperThreading indicates the HT/SMT support and enable state.
 	HyperThreading string `json:"hyper_threading,omitempty" name:"Hyper Threading" output:"both"`
 	CPUOpMode      string `json:"cpu_op_mode,omitempty"`
@@ -32,7 +38,8 @@ type CPU struct {
 	L1iCache string `json:"l1i_cache,omitempty"`
 	L2Cache  string `json:"l2_cache,omitempty"`
 	L3Cache  string `json:"l3_cache,omitempty"`
-	// PowerState reflects the active CPU frequency scaling governor mode.
+	// PowerState reflects the configured governor/EPP, or the turbostat
+	// estimate when cpufreq is not available.
 	PowerState   string `json:"power_state,omitempty" name:"Power State" output:"both" color:"powerGreen"`
 	BasedFreqMHz string `json:"based_freq_mhz,omitempty" name:"Frequency" output:"both"`
 	MaxFreqMHz   string `json:"max_freq_mhz,omitempty" name:"Core Frequency Max" output:"both"`
@@ -40,36 +47,69 @@ type CPU struct {
 	// TemperatureCelsius is the package-level temperature in Celsius.
 	TemperatureCelsius string `json:"temperature_celsius,omitempty" name:"Temperature" output:"both"`
 	// Watt is the CPU package power consumption reported by turbostat.
-	Watt           string `json:"watt,omitempty" name:"Watt" output:"both"`
+	Watt string `json:"watt,omitempty" name:"Watt" output:"both"`
+	// SocketMapping names the method used to associate SMBIOS sockets with
+	// kernel physical package IDs.
+	SocketMapping string `json:"socket_mapping,omitempty" name:"Socket Mapping" output:"detail"`
+	// Microcode is the loaded microcode revision, or one revision per socket when they differ.
+	Microcode      string `json:"microcode,omitempty" name:"Microcode" output:"both"`
 	Diagnose       string `json:"diagnose,omitempty" name:"Diagnose" color:"Diagnose" output:"both"`
 	DiagnoseDetail string `json:"diagnose_detail,omitempty" name:"Diagnose Detail" output:"both" color:"Diagnose"`
 	// Flags lists the CPU feature flags as reported by lscpu.
 	Flags []string `json:"flags,omitempty"`
+	// PowerManagement holds the cpufreq, cpuidle and uncore configuration.
+	PowerManagement *PowerManagement `json:"power_management,omitempty" name:"Power Management" output:"detail"`
+	// MitigationOverrides lists the kernel command-line parameters that change
+	// the default vulnerability mitigations (e.g., "mitigations=off").
+	MitigationOverrides []string `json:"mitigation_overrides,omitempty"`
+	// Vulnerabilities holds the kernel's status for each known CPU vulnerability.
+	Vulnerabilities []*Vulnerability `json:"vulnerabilities,omitempty" name:"Vulnerability" output:"detail"`
+	// MicrocodeEntries holds the loaded microcode revision of each socket.
+	MicrocodeEntries []*MicrocodeEntry `json:"microcode_entries,omitempty" name:"Microcode Entry" output:"detail"`
+	// ThermalThrottles holds the thermal throttling counters of each package.
+	ThermalThrottles []*ThermalThrottle `json:"thermal_throttles,omitempty" name:"Thermal Throttle" output:"detail"`
 	// CPUEntries contains per-socket detailed data sourced from SMBIOS type-4 tables.
 	CPUEntries []*SMBIOSCPUEntry `json:"cpu_entries,omitempty" name:"CPU Entry" output:"detail"`
 	// threads holds per-logical-thread turbostat data; not exported in JSON.
 	threads []*ThreadEntry
+	// temperatureMismatches records sockets whose IPMI and kernel temperatures disagree.
+	temperatureMismatches []string
+	// temperatures holds the readings keyed by "<pid>" and "<pid>-<coreID>".
+	temperatures map[string]int
 }
 
 // SMBIOSCPUEntry represents per-socket CPU information decoded from
 // SMBIOS (dmidecode) Type 4 - Processor Information tables.
 type SMBIOSCPUEntry struct {
 	// SocketDesignation is the motherboard-printed socket label (e.g., "CPU1", "P0").
-	SocketDesignation string   `json:"socket_designation,omitempty" name:"Socket Designation"`
-	ProcessorType     string   `json:"processor_type,omitempty"`
-	Family            string   `json:"family,omitempty"`
-	Manufacturer      string   `json:"manufacturer,omitempty"`
-	Version           string   `json:"version,omitempty"`
-	ExternalClock     string   `json:"external_clock,omitempty"`
-	CurrentSpeed      string   `json:"current_speed,omitempty"`
-	Status            string   `json:"status,omitempty"`
-	Voltage           string   `json:"voltage,omitempty"`
-	CoreCount         string   `json:"core_count,omitempty"`
-	CoreEnabled       string   `json:"core_enabled,omitempty"`
-	ThreadCount       string   `json:"threads_count,omitempty"`
-	Characteristics   []string `json:"characteristics,omitempty"`
+	SocketDesignation string `json:"socket_designation,omitempty" name:"Socket Designation"`
+	// PhysicalID is the kernel physical package ID mapped to this socket;
+	// empty for unpopulated sockets.
+	PhysicalID    string `json:"physical_id,omitempty"`
+	ProcessorType string `json:"processor_type,omitempty"`
+	Family        string `json:"family,omitempty"`
+	Manufacturer  string `json:"manufacturer,omitempty"`
+	Version       string `json:"version,omitempty"`
+	// Signature is the decoded SMBIOS processor ID (MIDR or SoC ID on ARM).
+	Signature       string   `json:"signature,omitempty"`
+	ExternalClock   string   `json:"external_clock,omitempty"`
+	CurrentSpeed    string   `json:"current_speed,omitempty"`
+	Status          string   `json:"status,omitempty"`
+	Voltage         string   `json:"voltage,omitempty"`
+	CoreCount       string   `json:"core_count,omitempty"`
+	CoreEnabled     string   `json:"core_enabled,omitempty"`
+	ThreadCount     string   `json:"threads_count,omitempty"`
+	Characteristics []string `json:"characteristics,omitempty"`
+	// Temperature is the package temperature of this socket.
+	Temperature string `json:"temperature,omitempty"`
+	// CCDTemperature lists the per-CCD readings of AMD/Hygon processors (e.g., "Tccd1 45 ℃, Tccd2 47 ℃").
+	CCDTemperature string `json:"ccd_temperature,omitempty"`
 	// ThreadEntries holds per-logical-thread data associated with this socket.
 	ThreadEntries []*ThreadEntry `json:"thread_entries,omitempty"`
+	// handle is the SMBIOS structure handle, used for handle-order mapping.
+	handle uint16
+	// populated reports whether a processor is installed in the socket.
+	populated bool
 }
 
 // ThreadEntry stores per-logical-CPU thread data collected from turbostat output.
@@ -78,8 +118,126 @@ type ThreadEntry struct {
 	ProcessorID string `json:"processor_id,omitempty"`
 	// CoreID is the physical core identifier within the socket.
 	CoreID string `json:"core_id,omitempty"`
+	// ClusterID is the core cluster identifier within the socket (ARM).
+	ClusterID string `json:"cluster_id,omitempty"`
 	// PhysicalID is the socket (package) identifier.
 	PhysicalID    string `json:"physical_id,omitempty"`
 	CoreFrequency string `json:"core_frequency,omitempty"`
 	Temperature   string `json:"temperature,omitempty"`
+	// Governor and EPP are the cpufreq scaling governor and energy performance preference.
+	Governor string `json:"governor,omitempty"`
+	EPP      string `json:"epp,omitempty"`
+}
+
+// Vulnerability stores one entry of /sys/devices/system/cpu/vulnerabilities.
+type Vulnerability struct {
+	Name string `json:"name,omitempty"`
+	// State is the normalized status: "Not Affected", "Mitigated", "Vulnerable" or "Unknown".
+	State string `json:"state,omitempty" name:"State" output:"detail"`
+	// Detail is the raw status line reported by the kernel.
+	Detail string `json:"detail,omitempty" name:"Detail" output:"detail"`
+}
+
+// MicrocodeEntry stores the loaded microcode revision of one socket together
+// with the processor signature used to look up the policy minimum.
+type MicrocodeEntry struct {
+	PhysicalID string `json:"physical_id,omitempty" name:"Physical ID" output:"detail"`
+	Revision   string `json:"revision,omitempty" name:"Revision" output:"detail"`
+	CPUFamily  string `json:"cpu_family,omitempty"`
+	CPUModel   string `json:"cpu_model,omitempty"`
+	Stepping   string `json:"stepping,omitempty"`
+	// Minimum is the policy minimum for this signature, empty when no policy applies.
+	Minimum string `json:"minimum,omitempty" name:"Minimum" output:"detail"`
+	// Status is "OK", "Outdated" or "Inconsistent"; empty when no policy applies
+	// and all threads of the socket agree.
+	Status string `json:"status,omitempty" name:"Status" output:"detail" color:"Diagnose"`
+}
+
+// PowerManagement summarizes the CPU power-management configuration.
+// Per-CPU values are reported as a single value when uniform and as a
+// "value (count)" list when CPUs differ.
+type PowerManagement struct {
+	// ScalingDriver is the cpufreq driver (e.g., "intel_pstate", "amd-pstate-epp", "acpi-cpufreq").
+	ScalingDriver string `json:"scaling_driver,omitempty" name:"Scaling Driver" output:"detail"`
+	// DriverStatus is the intel_pstate/amd_pstate operating mode (active, passive, guided).
+	DriverStatus string `json:"driver_status,omitempty" name:"Driver Status" output:"detail"`
+	Governor     string `json:"governor,omitempty" name:"Governor" output:"detail"`
+	// EPP is the energy performance preference (HWP / CPPC).
+	EPP string `json:"epp,omitempty" name:"EPP" output:"detail"`
+	// EPB is the x86 energy performance bias of cpu0.
+	EPB             string `json:"epb,omitempty" name:"EPB" output:"detail"`
+	Turbo           string `json:"turbo,omitempty" name:"Turbo" output:"detail"`
+	CPUIdleDriver   string `json:"cpuidle_driver,omitempty" name:"CPU Idle Driver" output:"detail"`
+	CPUIdleGovernor string `json:"cpuidle_governor,omitempty" name:"CPU Idle Governor" output:"detail"`
+	// Profile is the expected power profile the configuration was checked against.
+	Profile           string `json:"profile,omitempty" name:"Profile" output:"detail"`
+	ProfileCompliance string `json:"profile_compliance,omitempty" name:"Profile Compliance" output:"detail" color:"Diagnose"`
+	// Deviations lists the settings that do not match Profile.
+	Deviations        []string           `json:"deviations,omitempty"`
+	CStates           []*CState          `json:"c_states,omitempty" name:"C-State" output:"detail"`
+	UncoreFrequencies []*UncoreFrequency `json:"uncore_frequencies,omitempty" name:"Uncore Frequency" output:"detail"`
+}
+
+// CState describes one cpuidle state aggregated over all CPUs.
+type CState struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty" name:"Description" output:"detail"`
+	// Latency is the exit latency and TargetResidency the minimum residency for the state to pay off.
+	Latency         string `json:"latency,omitempty" name:"Latency" output:"detail"`
+	TargetResidency string `json:"target_residency,omitempty" name:"Target Residency" output:"detail"`
+	// Usage and Residency are the entry count and total time spent, summed over CPUs.
+	Usage     string `json:"usage,omitempty" name:"Usage" output:"detail"`
+	Residency string `json:"residency,omitempty" name:"Residency" output:"detail"`
+	// Disabled is "No", "Yes" or "Partial (n/m CPUs)".
+	Disabled string `json:"disabled,omitempty" name:"Disabled" output:"detail"`
+
+	cpus     int
+	disabled int
+	usage    uint64
+}
+
+// ThermalThrottle summarizes the thermal throttling of one package since boot.
+type ThermalThrottle struct {
+	Name       string `json:"name,omitempty"`
+	PhysicalID string `json:"physical_id,omitempty"`
+	// PackageEvents, PackageTime and PackageMaxTime are the package-level
+	// throttle count, total and longest throttle time.
+	PackageEvents  string `json:"package_events,omitempty" name:"Package Events" output:"detail"`
+	PackageTime    string `json:"package_time,omitempty" name:"Package Time" output:"detail"`
+	PackageMaxTime string `json:"package_max_time,omitempty" name:"Package Max Time" output:"detail"`
+	// CoreEvents and CoreTime are summed over the cores of the package.
+	CoreEvents string `json:"core_events,omitempty" name:"Core Events" output:"detail"`
+	CoreTime   string `json:"core_time,omitempty" name:"Core Time" output:"detail"`
+	// ThrottledCores is e.g. "2 of 24 (core 3, 9)".
+	ThrottledCores string `json:"throttled_cores,omitempty" name:"Throttled Cores" output:"detail"`
+	// LastThrottled is the time of the last throttle event logged by the
+	// kernel, or whether the counters still increase on kernels since 5.9.
+	LastThrottled   string `json:"last_throttled,omitempty" name:"Last Throttled" output:"detail"`
+	Temperature     string `json:"temperature,omitempty" name:"Temperature" output:"detail"`
+	MaxTemperature  string `json:"max_temperature,omitempty" name:"Max Temperature" output:"detail"`
+	CritTemperature string `json:"crit_temperature,omitempty" name:"Critical Temperature" output:"detail"`
+
+	cores          int
+	throttledCores []string
+	packageDir     string
+	coreDirs       []string
+	lastLogged     bool
+	throttlingNow  bool
+	packageCount   uint64
+	coreCount      uint64
+	coreTotalMS    uint64
+	hasCoreTime    bool
+	thresholds     tempThresholds
+	temperature    int
+	hasTemperature bool
+}
+
+// UncoreFrequency holds the Intel uncore frequency limits of one package/die.
+type UncoreFrequency struct {
+	Name       string `json:"name,omitempty"`
+	MinFreq    string `json:"min_freq,omitempty" name:"Min Frequency" output:"detail"`
+	MaxFreq    string `json:"max_freq,omitempty" name:"Max Frequency" output:"detail"`
+	InitialMin string `json:"initial_min_freq,omitempty" name:"Initial Min Frequency" output:"detail"`
+	InitialMax string `json:"initial_max_freq,omitempty" name:"Initial Max Frequency" output:"detail"`
+	Current    string `json:"current_freq,omitempty" name:"Current Frequency" output:"detail"`
 }
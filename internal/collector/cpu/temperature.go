@@ -1,5 +1,7 @@
 // Package cpu - temperature.go collects per-core and per-package CPU temperatures
-// from vendor-specific sources: IPMI (AMD) and hwmon coretemp sysfs (Intel).
+// from vendor-specific sources: hwmon coretemp sysfs (Intel), k10temp hwmon
+// (AMD/Hygon, see k10temp.go), zhaoxin/via cputemp hwmon (Zhaoxin) and SoC
+// hwmon devices or thermal zones (ARM).
 package cpu
 
 import (
@@ -8,63 +10,40 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
-	"github.com/zenithax-cc/baize/pkg/execute"
+	"github.com/zenithax-cc/baize/pkg/utils"
 )
 
 const (
-	ipmitool = "/usr/bin/ipmitool"
-	hwmon    = "/sys/class/hwmon"
+	hwmon   = "/sys/class/hwmon"
+	thermal = "/sys/class/thermal"
 )
 
-// collectAMDTemperature reads per-socket CPU temperatures via IPMI SDR.
-// It returns a map of normalized socket ID (e.g. "0", "1") to temperature in Celsius.
-func collectAMDTemperature() (map[string]int, error) {
-	output := execute.ShellCommand(fmt.Sprintf("%s sdr type temperature | egrep 'CPU[0-9]+[_ ]Temp'", ipmitool))
-	if output.Err != nil {
-		return nil, output.Err
-	}
-
-	cpus := strings.Split(strings.TrimSpace(string(output.Stdout)), "\n")
-	if len(cpus) == 0 || (len(cpus) == 1 && cpus[0] == "") {
-		return nil, errors.New("amd cpu temperature not found")
-	}
-
-	res := make(map[string]int, len(cpus))
-	for _, cpu := range cpus {
-		parts := strings.Split(cpu, "|")
-		if len(parts) != 5 {
-			continue
-		}
-
-		// Extract first 4 chars of sensor name (e.g. "CPU0") as the socket key.
-		name := strings.TrimSpace(parts[0])
-		if len(name) < 4 {
-			continue
-		}
-		name = name[:4]
-
-		// Extract temperature value from the last field (first 2 chars).
-		rawVal := strings.TrimSpace(parts[4])
-		if len(rawVal) < 2 {
-			continue
-		}
-		value := rawVal[:2]
-
-		if socketID, exists := socketIDMap[name]; exists {
-			n, err := strconv.Atoi(value)
-			if err != nil {
-				continue
-			}
-			res[socketID] = n
-		}
-	}
+// zhaoxinTempNames lists hwmon names of the per-core Zhaoxin temperature drivers.
+var zhaoxinTempNames = map[string]bool{
+	"zhaoxin_cputemp": true,
+	"zhaoxin_temp":    true,
+	"via_cputemp":     true,
+}
 
-	return res, nil
+// socHwmonNames lists the hwmon "name" values of ARM SoC temperature drivers.
+// Multi-socket platforms register one device per socket, in socket order.
+var socHwmonNames = map[string]bool{
+	"smpro":        true, // Ampere Altra / AmpereOne SMpro
+	"xgene_hwmon":  true, // APM X-Gene / early Ampere eMAG
+	"scpi_sensors": true, // SCPI firmware sensors
+	"cpu_thermal":  true,
+	"soc_thermal":  true,
 }
 
+// socSensorRegex matches hwmon labels and thermal zone types describing the
+// processor die rather than VRMs or DIMMs.
+var socSensorRegex = regexp.MustCompile(`(?i)^(soc|cpu|core|cluster|pkg|package)([\s_-]|\d|$)`)
+
 // collectIntelTemperature reads per-core and per-package temperatures from the
 // kernel hwmon coretemp sysfs interface (/sys/class/hwmon/hwmon*/temp*_label).
 // Returns a map with two key formats:
@@ -164,3 +143,190 @@ func collectIntelTemperature() (map[string]int, error) {
 
 	return res, nil
 }
+
+// collectSoCTemperature reads per-socket temperatures of ARM processors. SoC
+// hwmon devices are preferred; thermal zones whose type names the CPU or SoC are
+// used otherwise. The returned map is keyed by physical package ID.
+func collectSoCTemperature(sockets int) (map[string]int, error) {
+	temps := socHwmonTemperatures()
+	if len(temps) == 0 {
+		temps = thermalZoneTemperatures()
+	}
+
+	if len(temps) == 0 {
+		return nil, errors.New("no SoC temperature sensor found")
+	}
+
+	res := make(map[string]int, len(temps))
+
+	// One reading per socket: map in order. Otherwise the sources cannot be
+	// attributed to a socket and the hottest reading is reported for socket 0.
+	if len(temps) == sockets {
+		for i, t := range temps {
+			res[strconv.Itoa(i)] = t
+		}
+		return res, nil
+	}
+
+	hottest := temps[0]
+	for _, t := range temps[1:] {
+		hottest = max(hottest, t)
+	}
+	res["0"] = hottest
+
+	return res, nil
+}
+
+// socHwmonTemperatures returns the hottest processor reading of every SoC hwmon
+// device, ordered by hwmon index.
+func socHwmonTemperatures() []int {
+	res := make([]int, 0, 2)
+	for _, dir := range hwmonByName(socHwmonNames) {
+		inputs, _ := filepath.Glob(filepath.Join(dir, "temp*_input"))
+		hottest, found := 0, false
+		for _, input := range inputs {
+			// Skip labelled sensors that belong to VRMs, DIMMs and the like.
+			label, err := utils.ReadOneLineFile(strings.Replace(input, "_input", "_label", 1))
+			if err == nil && !socSensorRegex.MatchString(label) {
+				continue
+			}
+
+			milliDeg, err := utils.ReadSysfsInt(input)
+			if err != nil {
+				continue
+			}
+
+			deg := milliDeg / 1000
+			if !found || deg > hottest {
+				hottest, found = deg, true
+			}
+		}
+
+		if found {
+			res = append(res, hottest)
+		}
+	}
+
+	return res
+}
+
+// thermalZoneTemperatures returns the readings of thermal zones whose type names
+// the CPU or SoC, ordered by zone index.
+func thermalZoneTemperatures() []int {
+	zones, err := filepath.Glob(filepath.Join(thermal, "thermal_zone*"))
+	if err != nil {
+		return nil
+	}
+	sortByIndex(zones, "thermal_zone")
+
+	res := make([]int, 0, 2)
+	for _, zone := range zones {
+		typ, err := utils.ReadOneLineFile(filepath.Join(zone, "type"))
+		if err != nil || !socSensorRegex.MatchString(typ) {
+			continue
+		}
+
+		milliDeg, err := utils.ReadSysfsInt(filepath.Join(zone, "temp"))
+		if err != nil {
+			continue
+		}
+		res = append(res, milliDeg/1000)
+	}
+
+	return res
+}
+
+// sortByIndex sorts sysfs paths such as ".../hwmon10" numerically by the index
+// that follows prefix in the base name.
+func sortByIndex(paths []string, prefix string) {
+	index := func(p string) int {
+		n, _ := strconv.Atoi(strings.TrimPrefix(filepath.Base(p), prefix))
+		return n
+	}
+	sort.Slice(paths, func(i, j int) bool { return index(paths[i]) < index(paths[j]) })
+}
+
+// collectZhaoxinTemperature reads per-core temperatures from the Zhaoxin
+// cputemp drivers. Each hwmon device is bound to one logical CPU (platform
+// device "<driver>.<cpu>"), which is resolved to its package and core via sysfs
+// topology. Package temperatures are the hottest core of each package.
+func collectZhaoxinTemperature() (map[string]int, error) {
+	dirs := hwmonByName(zhaoxinTempNames)
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no zhaoxin cputemp hwmon device found under %s", hwmon)
+	}
+
+	res := make(map[string]int, len(dirs))
+	for _, dir := range dirs {
+		dev, err := utils.ReadLinkBase(filepath.Join(dir, "device"))
+		if err != nil {
+			continue
+		}
+		_, cpuID, ok := strings.Cut(dev, ".")
+		if !ok {
+			continue
+		}
+
+		topo := filepath.Join(sysfsCPU, "cpu"+cpuID, "topology")
+		pid, err1 := utils.ReadOneLineFile(filepath.Join(topo, "physical_package_id"))
+		coreID, err2 := utils.ReadOneLineFile(filepath.Join(topo, "core_id"))
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		milliDeg, err := utils.ReadSysfsInt(filepath.Join(dir, "temp1_input"))
+		if err != nil {
+			continue
+		}
+
+		temp := milliDeg / 1000
+		res[pid+"-"+coreID] = temp
+		if cur, ok := res[pid]; !ok || temp > cur {
+			res[pid] = temp
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, errors.New("zhaoxin cputemp hwmon reports no temperature")
+	}
+
+	return res, nil
+}
+
+// hwmonByName returns the /sys/class/hwmon entries whose "name" is in names.
+func hwmonByName(names map[string]bool) []string {
+	dirs, err := filepath.Glob(filepath.Join(hwmon, "hwmon*"))
+	if err != nil {
+		return nil
+	}
+	sortByIndex(dirs, "hwmon")
+
+	res := make([]string, 0, 2)
+	for _, dir := range dirs {
+		name, err := utils.ReadOneLineFile(filepath.Join(dir, "name"))
+		if err == nil && names[name] {
+			res = append(res, dir)
+		}
+	}
+	return res
+}
+
+// hwmonLabels returns the readings of a hwmon device keyed by temp*_label, in
+// degrees Celsius.
+func hwmonLabels(dir string) map[string]int {
+	labels, _ := filepath.Glob(filepath.Join(dir, "temp*_label"))
+
+	res := make(map[string]int, len(labels))
+	for _, label := range labels {
+		name, err := utils.ReadOneLineFile(label)
+		if err != nil {
+			continue
+		}
+		milliDeg, err := utils.ReadSysfsInt(strings.Replace(label, "_label", "_input", 1))
+		if err != nil {
+			continue
+		}
+		res[name] = milliDeg / 1000
+	}
+	return res
+}
@@ -0,0 +1,324 @@
+// Package cpu - throttle.go reports thermal throttling since boot. The x86
+// therm_throt driver counts per-core and per-package throttle events and time
+// under /sys/devices/system/cpu/cpu*/thermal_throttle; kernels before 5.9 also
+// log every event, which tells how recently a CPU throttled. Newer kernels only
+// keep the counters, so a short sample tells whether it is throttling now.
+// Package temperatures are compared with the hwmon max/crit thresholds.
+package cpu
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+// throttleLogRegex matches the therm_throt kernel messages, e.g.
+// "CPU12: Package temperature above threshold, cpu clock throttled (total events = 3)".
+var throttleLogRegex = regexp.MustCompile(`^CPU(\d+): (Core|Package) temperature (?:is )?above threshold`)
+
+// throttleInterval is the sampling window used to detect ongoing throttling
+// when the kernel does not log throttle events.
+const throttleInterval = time.Second
+
+// coretempNames lists the hwmon name of the Intel coretemp driver.
+var coretempNames = map[string]bool{"coretemp": true}
+
+// throttleCounters holds the counters of one core or package.
+type throttleCounters struct {
+	count   uint64
+	totalMS uint64
+	maxMS   uint64
+	// hasTime is set when the kernel provides the time counters.
+	hasTime bool
+}
+
+// tempThresholds are the hwmon max and crit temperatures of a package in °C.
+type tempThresholds struct {
+	max  int
+	crit int
+}
+
+// collectThermalThrottle reads the throttle counters of every core and package
+// and stores one ThermalThrottle entry per package.
+func (c *CPU) collectThermalThrottle(ctx context.Context) error {
+	type coreKey struct{ pid, core string }
+
+	var (
+		pkgs     = make(map[string]*ThermalThrottle, 2)
+		order    []string
+		seenCore = make(map[coreKey]bool, len(c.threads))
+		cpuPkg   = make(map[string]string, len(c.threads))
+	)
+
+	for _, thread := range c.threads {
+		dir := filepath.Join(sysfsCPU, "cpu"+thread.ProcessorID, "thermal_throttle")
+		if !utils.PathExists(dir) {
+			continue
+		}
+		cpuPkg[thread.ProcessorID] = thread.PhysicalID
+
+		tt, ok := pkgs[thread.PhysicalID]
+		if !ok {
+			tt = &ThermalThrottle{Name: "Package " + thread.PhysicalID, PhysicalID: thread.PhysicalID, packageDir: dir}
+			pc := readThrottleCounters(dir, "package")
+			tt.packageCount = pc.count
+			tt.PackageEvents = strconv.FormatUint(pc.count, 10)
+			if pc.hasTime {
+				tt.PackageTime = formatMS(pc.totalMS)
+				tt.PackageMaxTime = formatMS(pc.maxMS)
+			}
+			pkgs[thread.PhysicalID] = tt
+			order = append(order, thread.PhysicalID)
+		}
+
+		// Core counters are shared by the SMT siblings of a core.
+		key := coreKey{thread.PhysicalID, thread.CoreID}
+		if seenCore[key] {
+			continue
+		}
+		seenCore[key] = true
+		tt.cores++
+		tt.coreDirs = append(tt.coreDirs, dir)
+
+		cc := readThrottleCounters(dir, "core")
+		tt.hasCoreTime = tt.hasCoreTime || cc.hasTime
+		if cc.count > 0 {
+			tt.coreCount += cc.count
+			tt.coreTotalMS += cc.totalMS
+			tt.throttledCores = append(tt.throttledCores, thread.CoreID)
+		}
+	}
+
+	if len(pkgs) == 0 {
+		return fmt.Errorf("no thermal_throttle counters under %s", sysfsCPU)
+	}
+
+	lastSeen := lastThrottleEvents(cpuPkg)
+	thresholds := c.readTempThresholds()
+
+	sortNumeric(order)
+	res := make([]*ThermalThrottle, 0, len(order))
+	for _, pid := range order {
+		tt := pkgs[pid]
+		sortNumeric(tt.throttledCores)
+
+		tt.CoreEvents = strconv.FormatUint(tt.coreCount, 10)
+		if tt.hasCoreTime {
+			tt.CoreTime = formatMS(tt.coreTotalMS)
+		}
+		tt.ThrottledCores = fmt.Sprintf("%d of %d", len(tt.throttledCores), tt.cores)
+		if len(tt.throttledCores) > 0 {
+			tt.ThrottledCores += " (core " + strings.Join(tt.throttledCores, ", ") + ")"
+		}
+		if t, ok := lastSeen[pid]; ok {
+			tt.LastThrottled = fmt.Sprintf("%s (%s ago)", t.Format(time.DateTime), time.Since(t).Truncate(time.Minute))
+			tt.lastLogged = true
+		}
+
+		if th, ok := thresholds[pid]; ok {
+			if th.max > 0 {
+				tt.MaxTemperature = fmt.Sprintf("%d ℃", th.max)
+			}
+			if th.crit > 0 {
+				tt.CritTemperature = fmt.Sprintf("%d ℃", th.crit)
+			}
+			tt.thresholds = th
+		}
+		if t, ok := c.temperatures[pid]; ok {
+			tt.Temperature = fmt.Sprintf("%d ℃", t)
+			tt.temperature, tt.hasTemperature = t, true
+		}
+
+		res = append(res, tt)
+	}
+
+	c.ThermalThrottles = res
+
+	return sampleThrottling(ctx, res)
+}
+
+// sampleThrottling re-reads the counters of the packages that throttled but
+// have no logged event after throttleInterval, and reports whether they are
+// still throttling. Packages that never throttled are not sampled.
+func sampleThrottling(ctx context.Context, pkgs []*ThermalThrottle) error {
+	var pending []*ThermalThrottle
+	for _, tt := range pkgs {
+		if !tt.lastLogged && tt.packageCount+tt.coreCount > 0 {
+			pending = append(pending, tt)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(throttleInterval):
+	}
+
+	for _, tt := range pending {
+		n := tt.sampleCount() - tt.packageCount - tt.coreCount
+		if n > 0 {
+			tt.throttlingNow = true
+			tt.LastThrottled = fmt.Sprintf("now (%d events in %s)", n, throttleInterval)
+			continue
+		}
+		tt.LastThrottled = fmt.Sprintf("unknown, not in the last %s (kernel does not log throttle events)", throttleInterval)
+	}
+	return nil
+}
+
+// sampleCount re-reads the package counter and the core counters of the package.
+func (tt *ThermalThrottle) sampleCount() uint64 {
+	n := readThrottleCounters(tt.packageDir, "package").count
+	for _, dir := range tt.coreDirs {
+		n += readThrottleCounters(dir, "core").count
+	}
+	return n
+}
+
+// throttleIssues returns one diagnosis message per package that throttled or
+// runs at or above its hwmon max threshold.
+func (c *CPU) throttleIssues() []string {
+	var res []string
+	for _, tt := range c.ThermalThrottles {
+		var parts []string
+		if tt.packageCount > 0 {
+			parts = append(parts, fmt.Sprintf("package throttled %d times", tt.packageCount))
+		}
+		if len(tt.throttledCores) > 0 {
+			parts = append(parts, fmt.Sprintf("%d cores throttled %d times", len(tt.throttledCores), tt.coreCount))
+		}
+		if len(parts) > 0 {
+			msg := fmt.Sprintf("socket %s %s since boot", tt.PhysicalID, strings.Join(parts, ", "))
+			switch {
+			case tt.lastLogged:
+				msg += ", last at " + tt.LastThrottled
+			case tt.throttlingNow:
+				msg += ", still throttling"
+			}
+			res = append(res, msg)
+		}
+
+		if !tt.hasTemperature {
+			continue
+		}
+		switch th := tt.thresholds; {
+		case th.crit > 0 && tt.temperature >= th.crit:
+			res = append(res, fmt.Sprintf("socket %s temperature %d ℃ reached critical threshold %d ℃", tt.PhysicalID, tt.temperature, th.crit))
+		case th.max > 0 && tt.temperature >= th.max:
+			res = append(res, fmt.Sprintf("socket %s temperature %d ℃ reached max threshold %d ℃", tt.PhysicalID, tt.temperature, th.max))
+		}
+	}
+	return res
+}
+
+// readThrottleCounters reads the <scope>_throttle_{count,total_time_ms,max_time_ms}
+// files; the time counters exist since Linux 5.18.
+func readThrottleCounters(dir, scope string) throttleCounters {
+	var tc throttleCounters
+	tc.count, _ = utils.ReadSysfsUint64(filepath.Join(dir, scope+"_throttle_count"))
+	if v, err := utils.ReadSysfsUint64(filepath.Join(dir, scope+"_throttle_total_time_ms")); err == nil {
+		tc.totalMS, tc.hasTime = v, true
+	}
+	tc.maxMS, _ = utils.ReadSysfsUint64(filepath.Join(dir, scope+"_throttle_max_time_ms"))
+	return tc
+}
+
+// lastThrottleEvents returns the wall-clock time of the most recent throttle
+// message per package. Kernels since 5.9 no longer log throttle events, in
+// which case the result is empty.
+func lastThrottleEvents(cpuPkg map[string]string) map[string]time.Time {
+	entries, err := utils.ReadKernelLog()
+	if err != nil {
+		return nil
+	}
+
+	boot, err := utils.BootTime()
+	if err != nil {
+		return nil
+	}
+
+	res := make(map[string]time.Time, 2)
+	for _, e := range entries {
+		m := throttleLogRegex.FindStringSubmatch(e.Message)
+		if m == nil {
+			continue
+		}
+		pid, ok := cpuPkg[m[1]]
+		if !ok {
+			continue
+		}
+		if t := boot.Add(e.Timestamp); t.After(res[pid]) {
+			res[pid] = t
+		}
+	}
+	return res
+}
+
+// readTempThresholds returns the hwmon max/crit thresholds keyed by physical
+// package ID. coretemp reports them per "Package id" sensor; k10temp reports
+// them, when at all, on Tctl and they are the same for every socket.
+func (c *CPU) readTempThresholds() map[string]tempThresholds {
+	res := make(map[string]tempThresholds, 2)
+
+	read := func(input string) tempThresholds {
+		var th tempThresholds
+		if v, err := utils.ReadSysfsInt(strings.Replace(input, "_input", "_max", 1)); err == nil {
+			th.max = v / 1000
+		}
+		if v, err := utils.ReadSysfsInt(strings.Replace(input, "_input", "_crit", 1)); err == nil {
+			th.crit = v / 1000
+		}
+		return th
+	}
+
+	for _, dir := range hwmonByName(coretempNames) {
+		labels, _ := filepath.Glob(filepath.Join(dir, "temp*_label"))
+		for _, label := range labels {
+			name, err := utils.ReadOneLineFile(label)
+			if err != nil || !strings.HasPrefix(name, "Package id ") {
+				continue
+			}
+			res[strings.TrimPrefix(name, "Package id ")] = read(strings.Replace(label, "_label", "_input", 1))
+		}
+	}
+	if len(res) > 0 {
+		return res
+	}
+
+	for _, dir := range hwmonByName(k10tempNames) {
+		th := read(filepath.Join(dir, "temp1_input"))
+		if th.max == 0 && th.crit == 0 {
+			continue
+		}
+		for _, p := range c.kernelPackages() {
+			res[strconv.Itoa(p.id)] = th
+		}
+		break
+	}
+
+	return res
+}
+
+// formatMS formats a millisecond counter as a duration.
+func formatMS(ms uint64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+// sortNumeric sorts numeric strings by value.
+func sortNumeric(ids []string) {
+	sort.Slice(ids, func(i, j int) bool {
+		a, _ := strconv.Atoi(ids[i])
+		b, _ := strconv.Atoi(ids[j])
+		return a < b
+	})
+}
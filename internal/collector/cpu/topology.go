@@ -0,0 +1,265 @@
+// Package cpu - topology.go associates SMBIOS Type 4 processor entries with the
+// kernel's physical package IDs. Three methods are tried in order: parsing the
+// socket number out of the designation label, ordering sockets by APIC ID, and
+// finally falling back to SMBIOS handle order.
+package cpu
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+// Socket mapping methods reported in CPU.SocketMapping.
+const (
+	socketMappingLabel  = "Designation Label"
+	socketMappingAPICID = "APIC ID Order"
+	socketMappingHandle = "SMBIOS Handle Order"
+)
+
+// socketLabelRegex extracts the socket number from designations such as "P0",
+// "CPU 2", "CPU02", "Proc 3", "Socket 4" or "CPU7_SOCKET". Prefixes must start
+// a word and a bare "P" must be followed directly by the digit, so "SP3r3" and
+// "DIMM_P1" do not match.
+var socketLabelRegex = regexp.MustCompile(`(?i)\b(?:(?:cpu|proc(?:essor)?|socket|skt)[\s_#-]*|p)(\d+)`)
+
+// packageInfo describes one kernel physical package.
+type packageInfo struct {
+	id      int
+	minAPIC int // lowest APIC ID of the package, -1 when unknown
+}
+
+// collectTopology reads physical package, cluster and core IDs of all online
+// logical CPUs from sysfs. It is used when turbostat is unavailable so that thread
+// association still works.
+func collectTopology() ([]*ThreadEntry, error) {
+	dirs, err := filepath.Glob(filepath.Join(sysfsCPU, "cpu[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+
+	threads := make([]*ThreadEntry, 0, len(dirs))
+	for _, dir := range dirs {
+		pkgID, err := utils.ReadOneLineFile(filepath.Join(dir, "topology", "physical_package_id"))
+		if err != nil {
+			continue
+		}
+		coreID, _ := utils.ReadOneLineFile(filepath.Join(dir, "topology", "core_id"))
+		// cluster_id is only exposed by kernels 5.16+ and is -1 when firmware
+		// does not describe clusters.
+		clusterID, _ := utils.ReadOneLineFile(filepath.Join(dir, "topology", "cluster_id"))
+		if clusterID == "-1" {
+			clusterID = ""
+		}
+
+		threads = append(threads, &ThreadEntry{
+			ProcessorID: strings.TrimPrefix(filepath.Base(dir), "cpu"),
+			CoreID:      coreID,
+			ClusterID:   clusterID,
+			PhysicalID:  pkgID,
+		})
+	}
+
+	if len(threads) == 0 {
+		return nil, fmt.Errorf("no cpu topology found in %s", sysfsCPU)
+	}
+
+	sort.Slice(threads, func(i, j int) bool {
+		a, _ := strconv.Atoi(threads[i].ProcessorID)
+		b, _ := strconv.Atoi(threads[j].ProcessorID)
+		return a < b
+	})
+
+	return threads, nil
+}
+
+// clustersPerSocket counts the distinct clusters of the first package, or
+// returns an empty string when the kernel reports no cluster IDs.
+func (c *CPU) clustersPerSocket() string {
+	if len(c.threads) == 0 {
+		return ""
+	}
+
+	clusters := make(map[string]bool, 8)
+	for _, t := range c.threads {
+		if t.ClusterID != "" && t.PhysicalID == c.threads[0].PhysicalID {
+			clusters[t.ClusterID] = true
+		}
+	}
+
+	if len(clusters) == 0 {
+		return ""
+	}
+	return strconv.Itoa(len(clusters))
+}
+
+// kernelPackages returns the distinct physical packages seen by the kernel,
+// together with their lowest APIC ID when /proc/cpuinfo provides one.
+func (c *CPU) kernelPackages() []packageInfo {
+	pkgs := make(map[int]*packageInfo, 2)
+	for _, t := range c.threads {
+		id, err := strconv.Atoi(t.PhysicalID)
+		if err != nil {
+			continue
+		}
+		if _, ok := pkgs[id]; !ok {
+			pkgs[id] = &packageInfo{id: id, minAPIC: -1}
+		}
+	}
+
+	if procs, err := readCPUInfo(); err == nil {
+		for _, proc := range procs {
+			id, err := strconv.Atoi(proc["physical id"])
+			if err != nil {
+				continue
+			}
+
+			apic, ok := proc["initial apicid"]
+			if !ok {
+				apic = proc["apicid"]
+			}
+			v, err := strconv.Atoi(apic)
+			if err != nil {
+				continue
+			}
+
+			p, ok := pkgs[id]
+			if !ok {
+				p = &packageInfo{id: id, minAPIC: -1}
+				pkgs[id] = p
+			}
+			if p.minAPIC < 0 || v < p.minAPIC {
+				p.minAPIC = v
+			}
+		}
+	}
+
+	res := make([]packageInfo, 0, len(pkgs))
+	for _, p := range pkgs {
+		res = append(res, *p)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].id < res[j].id })
+
+	return res
+}
+
+// mapSockets assigns a kernel physical package ID to every populated SMBIOS
+// processor entry and records the method that succeeded in c.SocketMapping.
+func (c *CPU) mapSockets() error {
+	populated := make([]*SMBIOSCPUEntry, 0, len(c.CPUEntries))
+	for _, entry := range c.CPUEntries {
+		if entry.populated {
+			populated = append(populated, entry)
+		}
+	}
+	if len(populated) == 0 {
+		return nil
+	}
+
+	pkgs := c.kernelPackages()
+	if len(pkgs) == 0 {
+		return errors.New("no physical package found for socket mapping")
+	}
+
+	valid := make(map[int]bool, len(pkgs))
+	for _, p := range pkgs {
+		valid[p.id] = true
+	}
+
+	if ids, ok := mapByLabel(populated, valid); ok {
+		c.applySocketMapping(populated, ids, socketMappingLabel)
+		return nil
+	}
+
+	if len(populated) != len(pkgs) {
+		return fmt.Errorf("socket mapping failed: %d populated SMBIOS processors, %d kernel packages", len(populated), len(pkgs))
+	}
+
+	// SMBIOS lists processors in the same order firmware enumerates them, which
+	// follows the APIC ID layout. Handle order alone is used when APIC IDs are
+	// not available (e.g. non-x86 platforms).
+	sort.SliceStable(populated, func(i, j int) bool { return populated[i].handle < populated[j].handle })
+
+	method := socketMappingHandle
+	if hasAPIC(pkgs) {
+		method = socketMappingAPICID
+		sort.SliceStable(pkgs, func(i, j int) bool { return pkgs[i].minAPIC < pkgs[j].minAPIC })
+	}
+
+	ids := make([]int, len(pkgs))
+	for i, p := range pkgs {
+		ids[i] = p.id
+	}
+	c.applySocketMapping(populated, ids, method)
+
+	return nil
+}
+
+// applySocketMapping stores ids[i] as the physical ID of entries[i].
+func (c *CPU) applySocketMapping(entries []*SMBIOSCPUEntry, ids []int, method string) {
+	for i, entry := range entries {
+		entry.PhysicalID = strconv.Itoa(ids[i])
+	}
+	c.SocketMapping = method
+}
+
+// mapByLabel parses a socket number out of every designation. The numbering is
+// treated as zero-based when any label carries 0 and as one-based otherwise.
+// The mapping is accepted only when the labels are unique and every resulting
+// ID is a package known to the kernel.
+func mapByLabel(entries []*SMBIOSCPUEntry, valid map[int]bool) ([]int, bool) {
+	nums := make([]int, len(entries))
+	base := 1
+	for i, entry := range entries {
+		n, ok := parseSocketLabel(entry.SocketDesignation)
+		if !ok {
+			return nil, false
+		}
+		if n == 0 {
+			base = 0
+		}
+		nums[i] = n
+	}
+
+	seen := make(map[int]bool, len(nums))
+	for i := range nums {
+		nums[i] -= base
+		if seen[nums[i]] || !valid[nums[i]] {
+			return nil, false
+		}
+		seen[nums[i]] = true
+	}
+
+	return nums, true
+}
+
+// parseSocketLabel returns the socket number found in a designation string.
+func parseSocketLabel(label string) (int, bool) {
+	m := socketLabelRegex.FindStringSubmatch(label)
+	if m == nil {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// hasAPIC reports whether every package has a known APIC ID.
+func hasAPIC(pkgs []packageInfo) bool {
+	for _, p := range pkgs {
+		if p.minAPIC < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,338 @@
+// Package cpu - vulnerability.go reports the kernel's view of CPU vulnerabilities,
+// the mitigation overrides given on the kernel command line, and the loaded
+// microcode revision of every socket.
+package cpu
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+const (
+	sysfsCPU           = "/sys/devices/system/cpu"
+	vulnerabilitiesDir = "/sys/devices/system/cpu/vulnerabilities"
+	procCPUInfo        = "/proc/cpuinfo"
+	procCmdline        = "/proc/cmdline"
+
+	// Vulnerability states derived from the sysfs status prefix.
+	vulnNotAffected = "Not Affected"
+	vulnMitigated   = "Mitigated"
+	vulnVulnerable  = "Vulnerable"
+	vulnUnknown     = "Unknown"
+
+	// Microcode policy evaluation results.
+	microcodeOK           = "OK"
+	microcodeOutdated     = "Outdated"
+	microcodeInconsistent = "Inconsistent"
+)
+
+// mitigationParams lists the kernel command-line parameters that override the
+// default CPU vulnerability mitigations (see Documentation/admin-guide/kernel-parameters).
+var mitigationParams = map[string]struct{}{
+	"mitigations":                 {},
+	"nospectre_v1":                {},
+	"nospectre_v2":                {},
+	"spectre_v2":                  {},
+	"spectre_v2_user":             {},
+	"spectre_bhi":                 {},
+	"nospectre_bhb":               {},
+	"spec_store_bypass_disable":   {},
+	"nospec_store_bypass_disable": {},
+	"ssbd":                        {},
+	"pti":                         {},
+	"nopti":                       {},
+	"l1tf":                        {},
+	"mds":                         {},
+	"tsx":                         {},
+	"tsx_async_abort":             {},
+	"mmio_stale_data":             {},
+	"retbleed":                    {},
+	"srbds":                       {},
+	"gather_data_sampling":        {},
+	"spec_rstack_overflow":        {},
+	"reg_file_data_sampling":      {},
+	"indirect_target_selection":   {},
+	"kvm.nx_huge_pages":           {},
+	"nosmt":                       {},
+	"noibrs":                      {},
+	"noibpb":                      {},
+	"kpti":                        {},
+	"ssbd_disable":                {},
+}
+
+// MicrocodePolicy maps a processor signature "<family>-<model>-<stepping>"
+// (decimal values as shown in /proc/cpuinfo) to the minimum acceptable
+// microcode revision for that signature.
+type MicrocodePolicy map[string]uint64
+
+// microcodePolicy is the active policy; sockets whose signature is not listed
+// are never flagged.
+var microcodePolicy MicrocodePolicy
+
+// SetMicrocodePolicy replaces the active microcode policy.
+func SetMicrocodePolicy(p MicrocodePolicy) {
+	microcodePolicy = p
+}
+
+// LoadMicrocodePolicy reads a microcode policy file and makes it the active policy.
+// Each non-empty line has the form "<family> <model> <stepping> <min revision>",
+// e.g. "6 143 8 0x2b000590"; lines starting with '#' are comments.
+func LoadMicrocodePolicy(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open microcode policy %s: %w", path, err)
+	}
+	defer file.Close()
+
+	policy := make(MicrocodePolicy)
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			return fmt.Errorf("%s:%d: expected 4 fields, got %d", path, lineNo, len(fields))
+		}
+
+		rev, err := parseMicrocode(fields[3])
+		if err != nil {
+			return fmt.Errorf("%s:%d: invalid revision %q: %w", path, lineNo, fields[3], err)
+		}
+
+		policy[signatureKey(fields[0], fields[1], fields[2])] = rev
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read microcode policy %s: %w", path, err)
+	}
+
+	SetMicrocodePolicy(policy)
+
+	return nil
+}
+
+// collectVulnerabilities reads /sys/devices/system/cpu/vulnerabilities/* and the
+// mitigation-related parameters from /proc/cmdline.
+func (c *CPU) collectVulnerabilities() error {
+	entries, err := os.ReadDir(vulnerabilitiesDir)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", vulnerabilitiesDir, err)
+	}
+
+	c.Vulnerabilities = make([]*Vulnerability, 0, len(entries))
+	for _, entry := range entries {
+		status, err := utils.ReadOneLineFile(filepath.Join(vulnerabilitiesDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		c.Vulnerabilities = append(c.Vulnerabilities, &Vulnerability{
+			Name:   entry.Name(),
+			State:  vulnerabilityState(status),
+			Detail: status,
+		})
+	}
+
+	if cmdline, err := utils.ReadOneLineFile(procCmdline); err == nil {
+		c.MitigationOverrides = mitigationOverrides(cmdline)
+	}
+
+	return nil
+}
+
+// vulnerabilityState normalizes a sysfs vulnerability status line.
+func vulnerabilityState(status string) string {
+	switch {
+	case strings.HasPrefix(status, "Not affected"):
+		return vulnNotAffected
+	case strings.HasPrefix(status, "Mitigation"):
+		return vulnMitigated
+	case strings.HasPrefix(status, "Vulnerable"):
+		return vulnVulnerable
+	default:
+		return vulnUnknown
+	}
+}
+
+// mitigationOverrides returns the kernel command-line parameters that alter the
+// default vulnerability mitigations, in the order they appear.
+func mitigationOverrides(cmdline string) []string {
+	var res []string
+	for _, param := range strings.Fields(cmdline) {
+		key, _, _ := strings.Cut(param, "=")
+		if _, ok := mitigationParams[key]; ok {
+			res = append(res, param)
+		}
+	}
+	return res
+}
+
+// collectMicrocode determines the loaded microcode revision for every socket.
+// The revision is read from sysfs when available and from /proc/cpuinfo otherwise;
+// sockets whose threads report different revisions are marked inconsistent.
+func (c *CPU) collectMicrocode() error {
+	procs, err := readCPUInfo()
+	if err != nil {
+		return err
+	}
+
+	byPkg := make(map[string]*MicrocodeEntry, 2)
+	for _, proc := range procs {
+		rev := proc["microcode"]
+		versionFile := filepath.Join(sysfsCPU, "cpu"+proc["processor"], "microcode", "version")
+		if v, err := utils.ReadOneLineFile(versionFile); err == nil {
+			rev = v
+		}
+		if rev == "" {
+			continue
+		}
+
+		pid := proc["physical id"]
+		if pid == "" {
+			pid = "0"
+		}
+
+		entry, ok := byPkg[pid]
+		if !ok {
+			byPkg[pid] = &MicrocodeEntry{
+				PhysicalID: pid,
+				Revision:   formatMicrocode(rev),
+				CPUFamily:  proc["cpu family"],
+				CPUModel:   proc["model"],
+				Stepping:   proc["stepping"],
+			}
+			continue
+		}
+
+		if formatMicrocode(rev) != entry.Revision {
+			entry.Status = microcodeInconsistent
+		}
+	}
+
+	c.MicrocodeEntries = make([]*MicrocodeEntry, 0, len(byPkg))
+	for _, entry := range byPkg {
+		entry.evaluate(microcodePolicy)
+		c.MicrocodeEntries = append(c.MicrocodeEntries, entry)
+	}
+
+	sort.Slice(c.MicrocodeEntries, func(i, j int) bool {
+		a, _ := strconv.Atoi(c.MicrocodeEntries[i].PhysicalID)
+		b, _ := strconv.Atoi(c.MicrocodeEntries[j].PhysicalID)
+		return a < b
+	})
+
+	c.Microcode = microcodeSummary(c.MicrocodeEntries)
+
+	return nil
+}
+
+// microcodeSummary returns the common revision of all sockets, or a
+// "P<id>: <rev>" list when the sockets differ.
+func microcodeSummary(entries []*MicrocodeEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+
+	same := true
+	parts := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.Revision != entries[0].Revision {
+			same = false
+		}
+		parts = append(parts, "P"+e.PhysicalID+": "+e.Revision)
+	}
+
+	if same {
+		return entries[0].Revision
+	}
+	return strings.Join(parts, ", ")
+}
+
+// evaluate compares the loaded revision with the policy minimum for the entry's
+// signature. An inconsistent socket keeps its status regardless of the policy.
+func (m *MicrocodeEntry) evaluate(policy MicrocodePolicy) {
+	minimum, ok := policy[signatureKey(m.CPUFamily, m.CPUModel, m.Stepping)]
+	if !ok {
+		return
+	}
+
+	m.Minimum = fmt.Sprintf("0x%x", minimum)
+	if m.Status == microcodeInconsistent {
+		return
+	}
+
+	rev, err := parseMicrocode(m.Revision)
+	if err != nil {
+		return
+	}
+
+	if rev < minimum {
+		m.Status = microcodeOutdated
+		return
+	}
+	m.Status = microcodeOK
+}
+
+// signatureKey builds the policy key from decimal family, model and stepping.
+func signatureKey(family, model, stepping string) string {
+	return strings.Join([]string{family, model, stepping}, "-")
+}
+
+// parseMicrocode parses a microcode revision written in hex, with or without "0x".
+func parseMicrocode(s string) (uint64, error) {
+	s = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "0x")
+	return strconv.ParseUint(s, 16, 64)
+}
+
+// formatMicrocode normalizes a microcode revision to lower-case "0x" hex form.
+func formatMicrocode(s string) string {
+	v, err := parseMicrocode(s)
+	if err != nil {
+		return s
+	}
+	return fmt.Sprintf("0x%x", v)
+}
+
+// readCPUInfo parses /proc/cpuinfo into one key/value map per logical processor.
+func readCPUInfo() ([]map[string]string, error) {
+	lines, err := utils.ReadLines(procCPUInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res []map[string]string
+		cur map[string]string
+	)
+	for _, line := range lines {
+		k, v, ok := utils.ParseLineKeyValue(line, ":")
+		if !ok {
+			if strings.TrimSpace(line) == "" && cur != nil {
+				res = append(res, cur)
+				cur = nil
+			}
+			continue
+		}
+
+		if cur == nil {
+			cur = make(map[string]string, 32)
+		}
+		cur[k] = v
+	}
+
+	if cur != nil {
+		res = append(res, cur)
+	}
+
+	return res, nil
+}
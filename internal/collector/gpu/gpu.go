@@ -14,12 +14,23 @@ import (
 )
 
 type GPU struct {
-	GraphicsCard []*GraphicsCard `json:"graphics_card,omitzero"`
+	GraphicsCard []*GraphicsCard `json:"graphics_card,omitzero" name:"Graphics Card" output:"both"`
+	Accelerators []*Accelerator  `json:"accelerators,omitzero" name:"Accelerator" output:"both"`
 }
 
 type GraphicsCard struct {
-	IsOnBoard bool     `json:"is_on_board,omitzero" name:"On Board" color:"trueGreen"`
-	PCIe      *pci.PCI `json:"pcie,omitzero"`
+	IsOnBoard      bool     `json:"is_on_board,omitzero" name:"On Board" color:"trueGreen"`
+	PCIe           *pci.PCI `json:"pcie,omitzero"`
+	Diagnose       string   `json:"diagnose,omitzero" name:"Diagnose" output:"both" color:"Diagnose"`
+	DiagnoseDetail string   `json:"diagnose_detail,omitzero" name:"Diagnose Detail" output:"both"`
+}
+
+// Accelerator is a processing accelerator or co-processor, e.g. an AI
+// accelerator or a crypto and compression offload engine.
+type Accelerator struct {
+	PCIe           *pci.PCI `json:"pcie,omitzero"`
+	Diagnose       string   `json:"diagnose,omitzero" name:"Diagnose" output:"both" color:"Diagnose"`
+	DiagnoseDetail string   `json:"diagnose_detail,omitzero" name:"Diagnose Detail" output:"both"`
 }
 
 const (
@@ -53,14 +64,19 @@ func (g *GPU) Collect(ctx context.Context) error {
 		return err
 	}
 
+	accelErr := g.collectAccelerators(ctx)
+
 	if err := g.fromDrm(ctx); err == nil {
-		return nil
+		return accelErr
 	}
 
 	if err := g.fromLspci(ctx); err == nil {
-		return nil
+		return accelErr
 	}
 
+	if len(g.Accelerators) > 0 {
+		return accelErr
+	}
 	return errNotFound
 }
 
@@ -168,6 +184,7 @@ func (g *GPU) collectGraphicsCards(ctx context.Context, cardsBus []string) error
 				IsOnBoard: isOnBoard(p.VendorID, p.DeviceID),
 				PCIe:      p,
 			}
+			p.MergeDiagnose(&card.Diagnose, &card.DiagnoseDetail)
 
 			res[idx] = card
 			return nil
@@ -199,6 +216,47 @@ func (g *GPU) collectGraphicsCards(ctx context.Context, cardsBus []string) error
 	return nil
 }
 
+// collectAccelerators collects the processing accelerators and co-processors.
+// Their virtual functions are reported under them.
+func (g *GPU) collectAccelerators(ctx context.Context) error {
+	buses, err := pci.GetAcceleratorPCIBus()
+	if err != nil {
+		return err
+	}
+
+	res := make([]*Accelerator, len(buses))
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(maxConcurrency)
+	for i, bus := range buses {
+		eg.Go(func() error {
+			if err := egCtx.Err(); err != nil {
+				return err
+			}
+
+			p := pci.New(bus)
+			if err := p.Collect(); err != nil {
+				return nil
+			}
+
+			acc := &Accelerator{PCIe: p}
+			p.MergeDiagnose(&acc.Diagnose, &acc.DiagnoseDetail)
+			res[i] = acc
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	g.Accelerators = make([]*Accelerator, 0, len(res))
+	for _, acc := range res {
+		if acc != nil {
+			g.Accelerators = append(g.Accelerators, acc)
+		}
+	}
+	return nil
+}
+
 func isOnBoard(vendorID, deviceID string) bool {
 	var sb strings.Builder
 	sb.Grow(9)
@@ -0,0 +1,247 @@
+package mce
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MCi_STATUS bits common to Intel and AMD (Intel SDM Vol. 3B 15.3.2.2, AMD
+// APM Vol. 2 9.3.2.2).
+const (
+	statusVAL   = 1 << 63 // register contents are valid
+	statusOVER  = 1 << 62 // an earlier error was overwritten
+	statusUC    = 1 << 61 // uncorrected error
+	statusEN    = 1 << 60 // error reporting was enabled
+	statusMISCV = 1 << 59 // MCi_MISC is valid
+	statusADDRV = 1 << 58 // MCi_ADDR is valid
+	statusPCC   = 1 << 57 // processor context corrupt
+
+	// Intel software error recovery (MCG_SER_P) bits.
+	statusS  = 1 << 56 // signaled via machine check exception
+	statusAR = 1 << 55 // software recovery action required
+
+	// AMD specific bits.
+	statusDeferred = 1 << 44 // error deferred to consumption
+	statusPoison   = 1 << 43 // data poisoned
+
+	mcaCodeMask = 0xffff
+	// mcaFilterBit is the "F" correction report filtering bit in compound codes.
+	mcaFilterBit = 1 << 12
+)
+
+// CPU vendors as they appear in kernel, mcelog and rasdaemon records.
+const (
+	vendorIntel = "Intel"
+	vendorAMD   = "AMD"
+	vendorHygon = "Hygon"
+)
+
+// Severities.
+const (
+	severityCorrected   = "Corrected"
+	severityDeferred    = "Deferred"
+	severityUncorrected = "Uncorrected"
+	severityFatal       = "Fatal"
+)
+
+// MCA error classes.
+const (
+	typeBus          = "Bus/Interconnect"
+	typeCache        = "Cache"
+	typeMemory       = "Memory Controller"
+	typeTLB          = "TLB"
+	typeInternal     = "Internal"
+	typeUnclassified = "Unclassified"
+)
+
+// kernelVendors maps the x86_vendor number printed in "PROCESSOR v:cpuid"
+// kernel log lines and stored in rasdaemon's cpuvendor column.
+var kernelVendors = map[int]string{
+	0: vendorIntel,
+	2: vendorAMD,
+	9: vendorHygon,
+}
+
+// simpleCodes are the MCA simple error codes.
+var simpleCodes = map[uint64]string{
+	0x0000: "No error",
+	0x0001: "Unclassified error",
+	0x0002: "Microcode ROM parity error",
+	0x0003: "External error",
+	0x0004: "FRC error",
+	0x0005: "Internal parity error",
+	0x0006: "SMM handler code access violation",
+	0x0400: "Internal timer error",
+	0x0e0b: "I/O error",
+}
+
+var (
+	cacheLevels  = []string{"L0", "L1", "L2", "Generic level"}
+	transactions = []string{"Instruction", "Data", "Generic"}
+	requests     = []string{
+		"generic error", "generic read", "generic write", "data read",
+		"data write", "instruction fetch", "prefetch", "eviction", "snoop",
+	}
+	memTransactions = []string{
+		"generic undefined request", "memory read", "memory write",
+		"address/command", "memory scrubbing",
+	}
+	participations = []string{
+		"local processor originated", "local processor responded",
+		"local processor observed", "generic",
+	}
+	memoryIO = []string{"memory", "reserved", "I/O", "other"}
+)
+
+// isAMD reports whether the vendor uses the AMD MCi_STATUS layout.
+func isAMD(vendor string) bool {
+	return vendor == vendorAMD || vendor == vendorHygon
+}
+
+// decode fills the severity, error type, description and flags of e from its
+// raw MCi_STATUS value.
+func (e *MCEError) decode() {
+	s := e.status
+
+	e.Status = fmt.Sprintf("0x%016x", s)
+	if s&statusADDRV != 0 {
+		e.Address = fmt.Sprintf("0x%x", e.addr)
+	}
+	if s&statusMISCV != 0 {
+		e.Misc = fmt.Sprintf("0x%x", e.misc)
+	}
+
+	e.Severity = severity(s, e.vendor)
+	e.ErrorType, e.Description = decodeMCACode(s & mcaCodeMask)
+	e.Flags = statusFlags(s, e.vendor)
+}
+
+// severity classifies an MCi_STATUS value. Uncorrected Intel errors are
+// further split by the software error recovery class.
+func severity(s uint64, vendor string) string {
+	switch {
+	case s&statusUC == 0 && isAMD(vendor) && s&statusDeferred != 0:
+		return severityDeferred
+	case s&statusUC == 0:
+		return severityCorrected
+	case s&statusPCC != 0:
+		return severityFatal
+	case isAMD(vendor):
+		if s&statusDeferred != 0 {
+			return severityDeferred
+		}
+		return severityUncorrected
+	case s&statusS != 0 && s&statusAR != 0:
+		return severityUncorrected + " (SRAR)"
+	case s&statusS != 0:
+		return severityUncorrected + " (SRAO)"
+	default:
+		return severityUncorrected + " (UCNA)"
+	}
+}
+
+// isUncorrected reports whether a severity counts as uncorrected.
+func isUncorrected(sev string) bool {
+	return strings.HasPrefix(sev, severityUncorrected) || sev == severityFatal
+}
+
+// statusFlags lists the set flag bits of an MCi_STATUS value.
+func statusFlags(s uint64, vendor string) string {
+	flags := []struct {
+		bit  uint64
+		name string
+		amd  bool
+	}{
+		{bit: statusOVER, name: "OVER"},
+		{bit: statusUC, name: "UC"},
+		{bit: statusEN, name: "EN"},
+		{bit: statusMISCV, name: "MISCV"},
+		{bit: statusADDRV, name: "ADDRV"},
+		{bit: statusPCC, name: "PCC"},
+		{bit: statusS, name: "S"},
+		{bit: statusAR, name: "AR"},
+		{bit: statusDeferred, name: "Deferred", amd: true},
+		{bit: statusPoison, name: "Poison", amd: true},
+	}
+
+	amd := isAMD(vendor)
+	res := make([]string, 0, len(flags))
+	for _, f := range flags {
+		if s&f.bit == 0 {
+			continue
+		}
+		// S and AR are Intel-only; AMD uses those bits for other purposes.
+		if (f.amd && !amd) || (amd && (f.bit == statusS || f.bit == statusAR)) {
+			continue
+		}
+		res = append(res, f.name)
+	}
+
+	return strings.Join(res, ", ")
+}
+
+// decodeMCACode returns the error class and a description of an MCA error
+// code (Intel SDM Vol. 3B Table 15-9). AMD uses the same encoding in the low
+// 16 bits of MCi_STATUS.
+func decodeMCACode(code uint64) (string, string) {
+	if desc, ok := simpleCodes[code]; ok {
+		if code == 0x0400 {
+			return typeInternal, desc
+		}
+		return typeUnclassified, desc
+	}
+
+	c := code &^ mcaFilterBit
+	ll := c & 0x3
+	tt := (c >> 2) & 0x3
+	rrrr := (c >> 4) & 0xf
+
+	switch {
+	case c&0xf800 == 0x0800:
+		pp := (c >> 9) & 0x3
+		ii := (c >> 2) & 0x3
+		desc := fmt.Sprintf("%s %s %s, %s", cacheLevels[ll], lookup(requests, rrrr),
+			lookup(memoryIO, ii), lookup(participations, pp))
+		if c&(1<<8) != 0 {
+			desc += ", timeout"
+		}
+		return typeBus, desc
+	case c&0xfc00 == 0x0400:
+		return typeInternal, fmt.Sprintf("Internal unclassified error 0x%04x", code)
+	case c&0xff00 == 0x0100:
+		return typeCache, fmt.Sprintf("%s %s %s", cacheLevels[ll], lookup(transactions, tt), lookup(requests, rrrr))
+	case c&0xff80 == 0x0080:
+		mmm := (c >> 4) & 0x7
+		desc := lookup(memTransactions, mmm)
+		if ch := c & 0xf; ch != 0xf {
+			desc += fmt.Sprintf(", channel %d", ch)
+		} else {
+			desc += ", channel unspecified"
+		}
+		return typeMemory, desc
+	case c&0xfff0 == 0x0010:
+		return typeTLB, fmt.Sprintf("%s %s TLB", cacheLevels[ll], lookup(transactions, tt))
+	case c&0xfffc == 0x000c:
+		return typeCache, fmt.Sprintf("%s generic cache hierarchy", cacheLevels[ll])
+	}
+
+	return typeUnclassified, fmt.Sprintf("Unknown error code 0x%04x", code)
+}
+
+// memoryChannel returns the channel encoded in a memory controller error
+// code, or -1 when it is unspecified or the code is of another class.
+func memoryChannel(status uint64) int {
+	c := (status & mcaCodeMask) &^ mcaFilterBit
+	if c&0xff80 != 0x0080 || c&0xf == 0xf {
+		return -1
+	}
+	return int(c & 0xf)
+}
+
+// lookup returns names[i] or a numeric placeholder for reserved encodings.
+func lookup(names []string, i uint64) string {
+	if int(i) < len(names) {
+		return names[i]
+	}
+	return fmt.Sprintf("reserved (%d)", i)
+}
@@ -0,0 +1,393 @@
+// Package mce collects and decodes machine check exceptions (MCE).
+//
+// It concurrently collects:
+//   - Machine check settings and bank controls (/sys/devices/system/machinecheck)
+//   - Machine check records printed to the kernel log (/dev/kmsg or dmesg)
+//   - The mcelog daemon log (/var/log/mcelog) when present
+//   - The rasdaemon mce_record table (/var/lib/rasdaemon/ras-mc_event.db) when present
+//
+// Events reported by more than one source are merged. Each event's MCi_STATUS
+// is decoded into severity (corrected, deferred, uncorrected, fatal) and MCA
+// error class (cache, memory controller, bus/interconnect, ...), and the event
+// is attributed to a socket and core, and to a DIMM for memory controller
+// errors when the EDAC labels allow it.
+package mce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zenithax-cc/baize/internal/collector/rasdaemon"
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+const (
+	// maxReportedErrors caps the number of individual events kept in Errors.
+	maxReportedErrors = 100
+
+	// correctedThreshold is the number of corrected events at one location
+	// above which the location is reported in the diagnosis.
+	correctedThreshold = 10
+)
+
+// collectTask pairs a human-readable task name with its collection function.
+type collectTask struct {
+	name string
+	fn   func() ([]*MCEError, error)
+}
+
+// New creates and returns an initialised MCE collector.
+func New() *MCE {
+	return &MCE{}
+}
+
+// Collect reads the machine check settings and all event sources
+// concurrently, then merges, decodes and attributes the events. Missing
+// optional sources (mcelog, rasdaemon) are not errors.
+func (m *MCE) Collect(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Sources are listed in merge priority: persistent stores first, so that
+	// their richer records win over the kernel log copy of the same event.
+	tasks := []collectTask{
+		{name: sourceRasdaemon, fn: collectRasdaemon},
+		{name: sourceMcelog, fn: collectMcelog},
+		{name: sourceKernel, fn: collectKernelLog},
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		errs    []error
+		results = make([][]*MCEError, len(tasks))
+		found   = make([]bool, len(tasks))
+	)
+	wg.Add(len(tasks) + 1)
+
+	go func() {
+		defer wg.Done()
+		if err := m.collectMachineCheck(); err != nil {
+			mu.Lock()
+			errs = append(errs, fmt.Errorf("mce machinecheck: %w", err))
+			mu.Unlock()
+		}
+	}()
+
+	for i, task := range tasks {
+		go func(i int, t collectTask) {
+			defer wg.Done()
+
+			select {
+			case <-ctx.Done():
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("mce %s: %w", t.name, ctx.Err()))
+				mu.Unlock()
+				return
+			default:
+			}
+
+			events, err := t.fn()
+			if errors.Is(err, rasdaemon.ErrNoDatabase) {
+				return
+			}
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("mce %s: %w", t.name, err))
+				mu.Unlock()
+				return
+			}
+
+			results[i] = events
+			found[i] = events != nil
+		}(i, task)
+	}
+
+	wg.Wait()
+
+	var sources []string
+	for i, task := range tasks {
+		if found[i] {
+			sources = append(sources, task.name)
+		}
+	}
+	m.Sources = strings.Join(sources, ", ")
+
+	events := merge(results)
+	attribute(events)
+	m.summarize(events)
+	m.diagnose()
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// merge concatenates the per-source events, drops copies of the same event
+// reported by a lower-priority source and orders the result by time. Copies
+// are recognized by CPU, bank and register values only: kernel log records
+// without a PROCESSOR line have no wall-clock time, while rasdaemon and
+// mcelog stamp the same event. Repeats within one source are separate events.
+func merge(results [][]*MCEError) []*MCEError {
+	// kept holds the events of the higher-priority sources by key.
+	kept := make(map[string][]*MCEError)
+
+	var res []*MCEError
+	for _, events := range results {
+		matched := make(map[string]int)
+		added := make(map[string][]*MCEError)
+		for _, e := range events {
+			key := fmt.Sprintf("%d/%d/%x/%x/%x", e.cpu, e.bank, e.status, e.addr, e.misc)
+			if prior := kept[key]; matched[key] < len(prior) {
+				// Keep the higher-priority record, with a time if only the
+				// copy has one.
+				p := prior[matched[key]]
+				matched[key]++
+				if p.unixTime == 0 {
+					p.unixTime = e.unixTime
+				}
+				continue
+			}
+			added[key] = append(added[key], e)
+			res = append(res, e)
+		}
+		for key, events := range added {
+			kept[key] = append(kept[key], events...)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].unixTime < res[j].unixTime })
+
+	return res
+}
+
+// attribute decodes every event and resolves its socket, core and, for memory
+// controller errors, the DIMM or memory channel.
+func attribute(events []*MCEError) {
+	var dimms []edacDIMM
+	dimmsLoaded := false
+
+	for _, e := range events {
+		e.decode()
+
+		if e.cpu >= 0 {
+			e.CPU = strconv.Itoa(e.cpu)
+			if e.socket < 0 {
+				e.socket = packageID(e.cpu)
+			}
+			e.Core = coreID(e.cpu)
+		}
+		if e.socket >= 0 {
+			e.Socket = strconv.Itoa(e.socket)
+		}
+
+		e.Bank = strconv.Itoa(e.bank)
+		if e.bankName != "" {
+			e.Bank += " (" + e.bankName + ")"
+		}
+
+		// Kernel log records only know the time since boot until their
+		// PROCESSOR line supplies the wall-clock time.
+		if e.unixTime > 0 && (e.Time == "" || e.Source == sourceKernel) {
+			e.Time = time.Unix(e.unixTime, 0).Format(time.DateTime)
+		}
+
+		if e.Location != "" || e.ErrorType != typeMemory || e.socket < 0 {
+			continue
+		}
+
+		ch := memoryChannel(e.status)
+		if ch < 0 {
+			e.Location = "socket " + e.Socket
+			continue
+		}
+
+		if !dimmsLoaded {
+			dimms, dimmsLoaded = readEdacDIMMs(), true
+		}
+		e.Location = locateDIMM(dimms, e.socket, ch)
+	}
+}
+
+// locateDIMM returns the EDAC label of the DIMMs on a socket and channel.
+// Several candidates are listed when the channel holds more than one DIMM or
+// the socket has several memory controllers; without EDAC labels the socket
+// and channel are reported.
+func locateDIMM(dimms []edacDIMM, socket, channel int) string {
+	var labels []string
+	for _, d := range dimms {
+		if d.socket == socket && d.channel == channel {
+			labels = append(labels, d.label)
+		}
+	}
+
+	if len(labels) == 0 {
+		return fmt.Sprintf("socket %d channel %d", socket, channel)
+	}
+	return strings.Join(labels, " / ")
+}
+
+// summarize counts events by severity and by location/error type, and keeps
+// the most recent events.
+func (m *MCE) summarize(events []*MCEError) {
+	var corrected, deferred, uncorrected int
+
+	byKey := make(map[string]*ErrorSummary)
+	counts := make(map[*ErrorSummary][2]int)
+	for _, e := range events {
+		loc := e.Location
+		switch {
+		case loc != "":
+		case e.Socket != "" && e.Core != "":
+			loc = fmt.Sprintf("socket %s core %s", e.Socket, e.Core)
+		case e.Socket != "":
+			loc = "socket " + e.Socket
+		default:
+			loc = "CPU " + e.CPU
+		}
+
+		key := loc + "|" + e.ErrorType
+		s, ok := byKey[key]
+		if !ok {
+			s = &ErrorSummary{Location: loc, ErrorType: e.ErrorType}
+			byKey[key] = s
+			m.Summaries = append(m.Summaries, s)
+		}
+
+		c := counts[s]
+		switch {
+		case e.Severity == severityCorrected:
+			corrected++
+			c[0]++
+		case e.Severity == severityDeferred:
+			deferred++
+			c[1]++
+		case isUncorrected(e.Severity):
+			uncorrected++
+			c[1]++
+		}
+		counts[s] = c
+
+		if e.Time != "" {
+			s.LastSeen = e.Time
+		}
+	}
+
+	for s, c := range counts {
+		s.Corrected = strconv.Itoa(c[0])
+		s.Uncorrected = strconv.Itoa(c[1])
+	}
+
+	m.TotalErrors = strconv.Itoa(len(events))
+	m.CorrectedErrors = strconv.Itoa(corrected)
+	m.DeferredErrors = strconv.Itoa(deferred)
+	m.UncorrectedErrors = strconv.Itoa(uncorrected)
+
+	if len(events) > maxReportedErrors {
+		events = events[len(events)-maxReportedErrors:]
+	}
+	m.Errors = events
+}
+
+// diagnose evaluates the collected events and settings to produce a top-level
+// health summary.
+func (m *MCE) diagnose() {
+	var (
+		issues   []string
+		critical bool
+	)
+
+	if n, _ := strconv.Atoi(m.UncorrectedErrors); n > 0 {
+		critical = true
+		issues = append(issues, fmt.Sprintf("%d uncorrected machine check(s)", n))
+	}
+	if n, _ := strconv.Atoi(m.DeferredErrors); n > 0 {
+		issues = append(issues, fmt.Sprintf("%d deferred machine check(s)", n))
+	}
+
+	for _, s := range m.Summaries {
+		if n, _ := strconv.Atoi(s.Corrected); n >= correctedThreshold {
+			issues = append(issues, fmt.Sprintf("%d corrected %s error(s) on %s", n, strings.ToLower(s.ErrorType), s.Location))
+		}
+	}
+
+	if mc := m.MachineCheck; mc != nil {
+		if mc.IgnoreCE == "Yes" {
+			issues = append(issues, "corrected errors are ignored (ignore_ce)")
+		}
+		if mc.DisabledBanks != "" {
+			issues = append(issues, "MCA bank(s) "+mc.DisabledBanks+" disabled")
+		}
+	}
+
+	switch {
+	case len(issues) == 0:
+		m.Diagnose = "OK"
+	case critical:
+		m.Diagnose = "CRITICAL"
+		m.DiagnoseDetail = strings.Join(issues, "; ")
+	default:
+		m.Diagnose = "WARNING"
+		m.DiagnoseDetail = strings.Join(issues, "; ")
+	}
+}
+
+// Name returns the module identifier used by the collector Manager.
+func (m *MCE) Name() string {
+	return "mce"
+}
+
+// JSON serializes the MCE struct to indented JSON and writes it to stdout.
+func (m *MCE) JSON() error {
+	return utils.JSONPrintln(m)
+}
+
+// BriefPrintln prints the event counts, per-location summary and diagnosis.
+func (m *MCE) BriefPrintln() {
+	type MCEBrief struct {
+		Sources           string          `name:"Sources" output:"both"`
+		TotalErrors       string          `name:"Total Errors" output:"both"`
+		CorrectedErrors   string          `name:"Corrected Errors" output:"both"`
+		UncorrectedErrors string          `name:"Uncorrected Errors" output:"both"`
+		Summaries         []*ErrorSummary `name:"Error Summary" output:"both"`
+		Diagnose          string          `name:"Diagnose" output:"both" color:"Diagnose"`
+		DiagnoseDetail    string          `name:"Diagnose Detail" output:"both" color:"Diagnose"`
+	}
+
+	brief := &MCEBrief{
+		Sources:           m.Sources,
+		TotalErrors:       m.TotalErrors,
+		CorrectedErrors:   m.CorrectedErrors,
+		UncorrectedErrors: m.UncorrectedErrors,
+		Summaries:         m.Summaries,
+		Diagnose:          m.Diagnose,
+		DiagnoseDetail:    m.DiagnoseDetail,
+	}
+
+	wrapper := struct {
+		Items []*MCEBrief `name:"MCE INFO" output:"both"`
+	}{
+		Items: []*MCEBrief{brief},
+	}
+
+	utils.PrinterInstance.Print(wrapper, "brief")
+}
+
+// DetailPrintln prints the machine check settings, summaries and recent events.
+func (m *MCE) DetailPrintln() {
+	wrapper := struct {
+		Items []*MCE `name:"MCE INFO" output:"both"`
+	}{
+		Items: []*MCE{m},
+	}
+
+	utils.PrinterInstance.Print(wrapper, "detail")
+}
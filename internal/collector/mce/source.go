@@ -0,0 +1,193 @@
+package mce
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zenithax-cc/baize/internal/collector/rasdaemon"
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+const (
+	mcelogFile = "/var/log/mcelog"
+
+	sourceKernel    = "kernel log"
+	sourceMcelog    = "mcelog"
+	sourceRasdaemon = "rasdaemon"
+
+	// kernelMCEPrefix marks machine check records printed by the kernel.
+	kernelMCEPrefix = "mce: [Hardware Error]:"
+)
+
+var (
+	// e.g. "CPU 2: Machine Check: 0 Bank 7: cc00008000010090"; older kernels
+	// print "Machine Check Exception".
+	kernelBankRegex = regexp.MustCompile(`CPU (\d+): Machine Check(?: Exception)?: [0-9a-f]+ Bank (\d+): ([0-9a-f]+)`)
+	// e.g. "TSC 0 ADDR 1234abc0 MISC 90000080 SYND 0 IPID 0"
+	kernelAddrRegex = regexp.MustCompile(`\bADDR ([0-9a-f]+)`)
+	kernelMiscRegex = regexp.MustCompile(`\bMISC ([0-9a-f]+)`)
+	// e.g. "PROCESSOR 0:50654 TIME 1700000000 SOCKET 0 APIC 0 microcode 2006b06"
+	kernelProcRegex = regexp.MustCompile(`PROCESSOR (\d+):[0-9a-f]+ TIME (\d+) SOCKET (\d+)`)
+)
+
+// mcelogVendors maps the "CPUID Vendor" field of mcelog records.
+var mcelogVendors = map[string]string{
+	"Intel": vendorIntel,
+	"AMD":   vendorAMD,
+	"Hygon": vendorHygon,
+}
+
+// collectKernelLog parses the machine check records printed by the kernel.
+// A record starts with the "CPU n: Machine Check" line and ends with the
+// "PROCESSOR" line; the lines in between carry ADDR and MISC.
+func collectKernelLog() ([]*MCEError, error) {
+	entries, err := utils.ReadKernelLog()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res = make([]*MCEError, 0, 8)
+		cur *MCEError
+	)
+	for _, entry := range entries {
+		_, msg, ok := strings.Cut(entry.Message, kernelMCEPrefix)
+		if !ok {
+			continue
+		}
+
+		if m := kernelBankRegex.FindStringSubmatch(msg); m != nil {
+			cur = &MCEError{Source: sourceKernel, socket: -1}
+			cur.cpu, _ = strconv.Atoi(m[1])
+			cur.bank, _ = strconv.Atoi(m[2])
+			cur.status, _ = strconv.ParseUint(m[3], 16, 64)
+			cur.Time = "boot+" + entry.Timestamp.Truncate(time.Second).String()
+			res = append(res, cur)
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+
+		if m := kernelAddrRegex.FindStringSubmatch(msg); m != nil {
+			cur.addr, _ = strconv.ParseUint(m[1], 16, 64)
+		}
+		if m := kernelMiscRegex.FindStringSubmatch(msg); m != nil {
+			cur.misc, _ = strconv.ParseUint(m[1], 16, 64)
+		}
+		if m := kernelProcRegex.FindStringSubmatch(msg); m != nil {
+			v, _ := strconv.Atoi(m[1])
+			cur.vendor = kernelVendors[v]
+			cur.unixTime, _ = strconv.ParseInt(m[2], 10, 64)
+			cur.socket, _ = strconv.Atoi(m[3])
+			cur = nil
+		}
+	}
+
+	return res, nil
+}
+
+// collectMcelog parses the ASCII log written by the mcelog daemon. Records
+// start with "Hardware event." and carry whitespace-separated key/value pairs
+// such as "CPU 0 BANK 7", "ADDR 0x1234", "STATUS 8c00004000010090" and
+// "SOCKETID 0".
+func collectMcelog() ([]*MCEError, error) {
+	if !utils.FileExists(mcelogFile) {
+		return nil, nil
+	}
+
+	lines, err := utils.ReadLines(mcelogFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res   = make([]*MCEError, 0, 8)
+		cur   *MCEError
+		valid bool
+	)
+	flush := func() {
+		if cur != nil && valid {
+			res = append(res, cur)
+		}
+		cur, valid = nil, false
+	}
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, "Hardware event.") {
+			flush()
+			cur = &MCEError{Source: sourceMcelog, cpu: -1, socket: -1}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		for i := 0; i+1 < len(fields); i++ {
+			key, value := fields[i], fields[i+1]
+			switch key {
+			case "CPU":
+				if n, err := strconv.Atoi(value); err == nil {
+					cur.cpu = n
+				}
+			case "BANK":
+				cur.bank, _ = strconv.Atoi(value)
+			case "STATUS":
+				if v, err := parseHex(value); err == nil {
+					cur.status, valid = v, true
+				}
+			case "ADDR":
+				cur.addr, _ = parseHex(value)
+			case "MISC":
+				cur.misc, _ = parseHex(value)
+			case "TIME":
+				cur.unixTime, _ = strconv.ParseInt(value, 10, 64)
+			case "SOCKETID":
+				cur.socket, _ = strconv.Atoi(value)
+			case "Vendor":
+				cur.vendor = mcelogVendors[value]
+			}
+		}
+	}
+	flush()
+
+	return res, nil
+}
+
+// collectRasdaemon converts the mce_record rows stored by rasdaemon.
+func collectRasdaemon() ([]*MCEError, error) {
+	records, err := rasdaemon.MCERecords()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*MCEError, 0, len(records))
+	for _, r := range records {
+		e := &MCEError{
+			Source:   sourceRasdaemon,
+			cpu:      r.CPU,
+			socket:   r.SocketID,
+			bank:     r.Bank,
+			status:   r.Status,
+			addr:     r.Addr,
+			misc:     r.Misc,
+			vendor:   kernelVendors[r.CPUVendor],
+			unixTime: int64(r.Walltime),
+			Time:     r.Timestamp,
+			bankName: r.BankName,
+			Location: r.MCLocation,
+		}
+		res = append(res, e)
+	}
+
+	return res, nil
+}
+
+// parseHex parses a hexadecimal value with or without the 0x prefix.
+func parseHex(s string) (uint64, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	return strconv.ParseUint(s, 16, 64)
+}
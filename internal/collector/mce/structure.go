@@ -0,0 +1,111 @@
+// Package mce provides data structures for machine check exception (MCE)
+// information collected from sysfs, the kernel log, mcelog and rasdaemon.
+package mce
+
+// MCE is the top-level container for machine check configuration and the
+// decoded machine check events found on the host.
+type MCE struct {
+	// MachineCheck holds the kernel machine check settings from sysfs.
+	MachineCheck *MachineCheck `json:"machine_check,omitempty" name:"Machine Check" output:"detail"`
+	// Sources lists the record stores that were read (e.g. "kernel log, rasdaemon").
+	Sources string `json:"sources,omitempty" name:"Sources" output:"both"`
+	// TotalErrors is the number of distinct machine check events found.
+	TotalErrors string `json:"total_errors,omitempty" name:"Total Errors" output:"both"`
+	// CorrectedErrors is the number of corrected events.
+	CorrectedErrors string `json:"corrected_errors,omitempty" name:"Corrected Errors" output:"both"`
+	// DeferredErrors is the number of deferred (AMD) events.
+	DeferredErrors string `json:"deferred_errors,omitempty" name:"Deferred Errors" output:"both"`
+	// UncorrectedErrors is the number of uncorrected events, including fatal ones.
+	UncorrectedErrors string `json:"uncorrected_errors,omitempty" name:"Uncorrected Errors" output:"both"`
+	// Summaries aggregates events by location and error type.
+	Summaries []*ErrorSummary `json:"summaries,omitempty" name:"Error Summary" output:"both"`
+	// Errors holds the most recent decoded events, newest last.
+	Errors []*MCEError `json:"errors,omitempty" name:"Error" output:"detail"`
+	// Diagnose is a human-readable overall health verdict.
+	Diagnose string `json:"diagnose,omitempty" name:"Diagnose" output:"both" color:"Diagnose"`
+	// DiagnoseDetail provides additional context when Diagnose is not "OK".
+	DiagnoseDetail string `json:"diagnose_detail,omitempty" name:"Diagnose Detail" output:"both" color:"Diagnose"`
+}
+
+// MachineCheck holds the settings of the kernel machine check subsystem
+// exposed under /sys/devices/system/machinecheck.
+type MachineCheck struct {
+	// Banks is the number of MCA banks per CPU.
+	Banks string `json:"banks,omitempty" name:"Banks" output:"detail"`
+	// DisabledBanks lists banks whose control register is cleared.
+	DisabledBanks string `json:"disabled_banks,omitempty" name:"Disabled Banks" output:"detail"`
+	// CheckInterval is the corrected error polling interval.
+	CheckInterval string `json:"check_interval,omitempty" name:"Check Interval" output:"detail"`
+	// MonarchTimeout is the rendezvous timeout for broadcast machine checks.
+	MonarchTimeout string `json:"monarch_timeout,omitempty" name:"Monarch Timeout" output:"detail"`
+	// Tolerant is the tolerance level (removed in Linux 6.1).
+	Tolerant string `json:"tolerant,omitempty" name:"Tolerant" output:"detail"`
+	// IgnoreCE reports whether corrected errors are ignored entirely.
+	IgnoreCE string `json:"ignore_ce,omitempty" name:"Ignore CE" output:"detail"`
+	// DontLogCE reports whether corrected errors are handled but not logged.
+	DontLogCE string `json:"dont_log_ce,omitempty" name:"Don't Log CE" output:"detail"`
+	// CMCIDisabled reports whether corrected machine check interrupts are disabled.
+	CMCIDisabled string `json:"cmci_disabled,omitempty" name:"CMCI Disabled" output:"detail"`
+	// PrintAll reports whether corrected errors are printed to the kernel log.
+	PrintAll string `json:"print_all,omitempty" name:"Print All" output:"detail"`
+	// Trigger is the user-space program run on machine checks.
+	Trigger string `json:"trigger,omitempty" name:"Trigger" output:"detail"`
+}
+
+// ErrorSummary counts the events that share a location and error type.
+type ErrorSummary struct {
+	// Location is the attributed DIMM, or the socket/core and bank.
+	Location string `json:"location,omitempty" name:"Location" output:"both"`
+	// ErrorType is the MCA error class (e.g. "Memory Controller").
+	ErrorType string `json:"error_type,omitempty" name:"Error Type" output:"both"`
+	// Corrected is the number of corrected events.
+	Corrected string `json:"corrected,omitempty" name:"Corrected" output:"both"`
+	// Uncorrected is the number of deferred, uncorrected and fatal events.
+	Uncorrected string `json:"uncorrected,omitempty" name:"Uncorrected" output:"both"`
+	// LastSeen is the time of the most recent event, when known.
+	LastSeen string `json:"last_seen,omitempty" name:"Last Seen" output:"both"`
+}
+
+// MCEError is one decoded machine check event.
+type MCEError struct {
+	// Source is the record store the event was read from.
+	Source string `json:"source,omitempty" name:"Source" output:"detail"`
+	// Time is the event time, or the time since boot for kernel log records
+	// without a wall-clock timestamp.
+	Time string `json:"time,omitempty" name:"Time" output:"detail"`
+	// CPU is the logical CPU that logged the event.
+	CPU string `json:"cpu,omitempty" name:"CPU" output:"detail"`
+	// Socket is the physical package of the logging CPU.
+	Socket string `json:"socket,omitempty" name:"Socket" output:"detail"`
+	// Core is the core ID of the logging CPU within its package.
+	Core string `json:"core,omitempty" name:"Core" output:"detail"`
+	// Bank is the MCA bank number, with its name when known.
+	Bank string `json:"bank,omitempty" name:"Bank" output:"detail"`
+	// Status is the raw MCi_STATUS register.
+	Status string `json:"status,omitempty" name:"MCi_STATUS" output:"detail"`
+	// Address is MCi_ADDR when valid.
+	Address string `json:"address,omitempty" name:"MCi_ADDR" output:"detail"`
+	// Misc is MCi_MISC when valid.
+	Misc string `json:"misc,omitempty" name:"MCi_MISC" output:"detail"`
+	// Severity is "Corrected", "Deferred", "Uncorrected (SRAR|SRAO|UCNA)" or "Fatal".
+	Severity string `json:"severity,omitempty" name:"Severity" output:"detail"`
+	// ErrorType is the MCA error code class.
+	ErrorType string `json:"error_type,omitempty" name:"Error Type" output:"detail"`
+	// Description is the decoded MCA error code (cache level, transaction, ...).
+	Description string `json:"description,omitempty" name:"Description" output:"detail"`
+	// Flags lists the set MCi_STATUS flag bits.
+	Flags string `json:"flags,omitempty" name:"Flags" output:"detail"`
+	// Location is the attributed DIMM or memory channel for memory errors.
+	Location string `json:"location,omitempty" name:"Location" output:"detail"`
+
+	// Fields used for attribution and de-duplication.
+	cpu      int
+	socket   int
+	bank     int
+	bankName string
+	status   uint64
+	addr     uint64
+	misc     uint64
+	vendor   string
+	unixTime int64
+}
@@ -0,0 +1,151 @@
+package mce
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+const (
+	machinecheckDir = "/sys/devices/system/machinecheck"
+	sysfsCPU        = "/sys/devices/system/cpu"
+)
+
+// collectMachineCheck reads the machine check settings of CPU 0; the kernel
+// keeps them identical on all CPUs. The directory only exists on x86.
+func (m *MCE) collectMachineCheck() error {
+	dir := filepath.Join(machinecheckDir, "machinecheck0")
+	if !utils.PathExists(dir) {
+		return nil
+	}
+
+	banks, err := filepath.Glob(filepath.Join(dir, "bank*"))
+	if err != nil {
+		return err
+	}
+	sortBanks(banks)
+
+	mc := &MachineCheck{Banks: strconv.Itoa(len(banks))}
+
+	var disabled []string
+	for _, bank := range banks {
+		v, err := utils.ReadOneLineFile(bank)
+		if err != nil {
+			continue
+		}
+		if ctl, err := strconv.ParseUint(strings.TrimPrefix(v, "0x"), 16, 64); err == nil && ctl == 0 {
+			disabled = append(disabled, strings.TrimPrefix(filepath.Base(bank), "bank"))
+		}
+	}
+	mc.DisabledBanks = strings.Join(disabled, ", ")
+
+	read := func(name string) string {
+		v, _ := utils.ReadOneLineFile(filepath.Join(dir, name))
+		return v
+	}
+	yesNo := func(name string) string {
+		switch read(name) {
+		case "0":
+			return "No"
+		case "1":
+			return "Yes"
+		}
+		return ""
+	}
+
+	if v := read("check_interval"); v != "" {
+		mc.CheckInterval = v + " s"
+	}
+	if v := read("monarch_timeout"); v != "" {
+		mc.MonarchTimeout = v + " us"
+	}
+	mc.Tolerant = read("tolerant")
+	mc.IgnoreCE = yesNo("ignore_ce")
+	mc.DontLogCE = yesNo("dont_log_ce")
+	mc.CMCIDisabled = yesNo("cmci_disabled")
+	mc.PrintAll = yesNo("print_all")
+	mc.Trigger = read("trigger")
+
+	m.MachineCheck = mc
+
+	return nil
+}
+
+// sortBanks orders bank paths numerically (bank2 before bank10).
+func sortBanks(paths []string) {
+	index := func(p string) int {
+		n, _ := strconv.Atoi(strings.TrimPrefix(filepath.Base(p), "bank"))
+		return n
+	}
+	sort.Slice(paths, func(i, j int) bool { return index(paths[i]) < index(paths[j]) })
+}
+
+// coreID returns the core_id of a logical CPU, or "" when unknown.
+func coreID(cpu int) string {
+	v, err := utils.ReadOneLineFile(filepath.Join(sysfsCPU, "cpu"+strconv.Itoa(cpu), "topology", "core_id"))
+	if err != nil {
+		return ""
+	}
+	return v
+}
+
+// packageID returns the physical_package_id of a logical CPU, or -1.
+func packageID(cpu int) int {
+	v, err := utils.ReadSysfsInt(filepath.Join(sysfsCPU, "cpu"+strconv.Itoa(cpu), "topology", "physical_package_id"))
+	if err != nil {
+		return -1
+	}
+	return v
+}
+
+// edacDIMM is a DIMM registered with EDAC and the socket/channel it sits on.
+type edacDIMM struct {
+	label   string
+	socket  int
+	channel int
+}
+
+// readEdacDIMMs returns the EDAC DIMMs whose labels follow the
+// "CPU_SrcID#0_Ha#0_Chan#1_DIMM#0" convention of the Intel EDAC drivers, or
+// the mcN/csrowN layout of amd64_edac where the memory controller is the node.
+func readEdacDIMMs() []edacDIMM {
+	dirs, err := filepath.Glob("/sys/devices/system/edac/mc/mc*/dimm*")
+	if err != nil {
+		return nil
+	}
+
+	res := make([]edacDIMM, 0, len(dirs))
+	for _, dir := range dirs {
+		label, err := utils.ReadOneLineFile(filepath.Join(dir, "dimm_label"))
+		if err != nil || label == "" {
+			continue
+		}
+
+		d := edacDIMM{label: label, socket: -1, channel: -1}
+		for _, part := range strings.Split(label, "_") {
+			key, value, ok := strings.Cut(part, "#")
+			if !ok {
+				continue
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				continue
+			}
+			switch strings.TrimSpace(key) {
+			case "SrcID":
+				d.socket = n
+			case "Chan":
+				d.channel = n
+			}
+		}
+
+		if d.socket >= 0 && d.channel >= 0 {
+			res = append(res, d)
+		}
+	}
+
+	return res
+}
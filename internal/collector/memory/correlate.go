@@ -0,0 +1,544 @@
+package memory
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/baize/internal/collector/smbios"
+)
+
+// DIMM mapping methods reported in Memory.DIMMMapping.
+const (
+	mappingLabel  = "EDAC Label"
+	mappingLayout = "Locator Layout"
+)
+
+// dimmPosition locates a DIMM by socket, channel within the socket and slot
+// within the channel, all zero-based. -1 means unknown.
+type dimmPosition struct {
+	socket  int
+	channel int
+	slot    int
+}
+
+// edacTopology describes the channel numbering derived from EDAC.
+type edacTopology struct {
+	// channels is the number of channels per socket and perMC the number of
+	// channels per memory controller.
+	channels int
+	perMC    int
+}
+
+// dimmLayout decodes the SMBIOS locators of one OEM's boards into positions.
+// Silkscreen numbers are one-based unless a zero appears in the same field
+// on any DIMM of the board; base holds 0 or 1 per capture group.
+type dimmLayout struct {
+	// name is reported in Memory.DIMMMapping.
+	name string
+	// vendors are SMBIOS system manufacturer prefixes this layout applies
+	// to; an empty list applies the layout to every vendor.
+	vendors []string
+	// bank reports whether pattern applies to "BankLocator DeviceLocator"
+	// instead of DeviceLocator alone.
+	bank    bool
+	pattern *regexp.Regexp
+	decode  func(m []string, topo edacTopology, base []int) dimmPosition
+}
+
+// letterIndex converts "A".."P" to 0..15.
+func letterIndex(s string) int {
+	return int(strings.ToUpper(s)[0] - 'A')
+}
+
+// atoi converts a matched number, returning -1 on failure.
+func atoi(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// dimmLayouts lists the known locator conventions, most specific first.
+var dimmLayouts = []dimmLayout{
+	{
+		// AMI firmware BankLocator, e.g. "P0_Node0_Channel2_Dimm1"; Node is the
+		// memory controller when P is present and the socket otherwise.
+		name:    "AMI Bank Locator",
+		bank:    true,
+		pattern: regexp.MustCompile(`(?i)(?:P(\d+)_)?Node(\d+)_Channel(\d+)_Dimm(\d+)`),
+		decode: func(m []string, topo edacTopology, _ []int) dimmPosition {
+			if m[1] == "" {
+				return dimmPosition{socket: atoi(m[2]), channel: atoi(m[3]), slot: atoi(m[4])}
+			}
+			return dimmPosition{socket: atoi(m[1]), channel: atoi(m[2])*topo.perMC + atoi(m[3]), slot: atoi(m[4])}
+		},
+	},
+	{
+		// AMD reference BankLocator "P0 CHANNEL A" with DeviceLocator "DIMM 0".
+		name:    "AMD Bank Locator",
+		bank:    true,
+		pattern: regexp.MustCompile(`(?i)^P(\d+) CHANNEL ([A-P])(?: DIMM ?(\d+))?$`),
+		decode: func(m []string, _ edacTopology, _ []int) dimmPosition {
+			slot := -1
+			if m[3] != "" {
+				slot = atoi(m[3])
+			}
+			return dimmPosition{socket: atoi(m[1]), channel: letterIndex(m[2]), slot: slot}
+		},
+	},
+	{
+		// Supermicro "P1-DIMMA1", "P2-DIMMF2": letter is the channel.
+		name:    "Supermicro",
+		vendors: []string{"Supermicro"},
+		pattern: regexp.MustCompile(`(?i)^P(\d+)-DIMM([A-P])(\d+)$`),
+		decode: func(m []string, _ edacTopology, base []int) dimmPosition {
+			return dimmPosition{socket: atoi(m[1]) - base[1], channel: letterIndex(m[2]), slot: atoi(m[3]) - base[3]}
+		},
+	},
+	{
+		// Inspur "CPU0_C0D0": zero-based socket, channel and slot.
+		name:    "Inspur",
+		vendors: []string{"Inspur"},
+		pattern: regexp.MustCompile(`(?i)^CPU(\d+)_C(\d+)D(\d+)$`),
+		decode: func(m []string, _ edacTopology, _ []int) dimmPosition {
+			return dimmPosition{socket: atoi(m[1]), channel: atoi(m[2]), slot: atoi(m[3])}
+		},
+	},
+	{
+		// Huawei / xFusion "DIMM000", "DIMM120": socket, channel and slot digits.
+		name:    "Huawei",
+		vendors: []string{"Huawei", "xFusion"},
+		pattern: regexp.MustCompile(`(?i)^DIMM(\d)(\d)(\d)\b`),
+		decode: func(m []string, _ edacTopology, _ []int) dimmPosition {
+			return dimmPosition{socket: atoi(m[1]), channel: atoi(m[2]), slot: atoi(m[3])}
+		},
+	},
+	{
+		// H3C, New H3C and other "CPU1_DIMMA1" / "CPU1_DIMMA0" boards.
+		name:    "CPU DIMM Letter",
+		pattern: regexp.MustCompile(`(?i)^CPU(\d+)[_-]DIMM[_-]?([A-P])(\d+)$`),
+		decode: func(m []string, _ edacTopology, base []int) dimmPosition {
+			return dimmPosition{socket: atoi(m[1]) - base[1], channel: letterIndex(m[2]), slot: atoi(m[3]) - base[3]}
+		},
+	},
+	{
+		// Dell "A1".."A16" (socket A), "B1".. (socket B). The first DIMM of
+		// every channel comes first (white tabs), then the second (black tabs).
+		name:    "Dell",
+		vendors: []string{"Dell"},
+		pattern: regexp.MustCompile(`^([A-H])(\d+)$`),
+		decode: func(m []string, topo edacTopology, _ []int) dimmPosition {
+			n := atoi(m[2]) - 1
+			if topo.channels <= 0 {
+				return dimmPosition{socket: letterIndex(m[1]), channel: -1, slot: -1}
+			}
+			return dimmPosition{socket: letterIndex(m[1]), channel: n % topo.channels, slot: n / topo.channels}
+		},
+	},
+	{
+		// HPE "PROC 1 DIMM 12": only the socket is encoded reliably; DIMMs
+		// within the socket are matched in table order.
+		name:    "HPE",
+		vendors: []string{"HP", "Hewlett"},
+		pattern: regexp.MustCompile(`(?i)^PROC (\d+) DIMM (\d+)$`),
+		decode: func(m []string, _ edacTopology, _ []int) dimmPosition {
+			return dimmPosition{socket: atoi(m[1]) - 1, channel: -1, slot: -1}
+		},
+	},
+}
+
+// correlateDIMMs maps every EDAC DIMM to the SMBIOS DIMM it sits in and copies
+// the error counters onto the SMBIOS entry, so that errors can be reported
+// with the module's silkscreen locator, serial and part number. It tries, in
+// order: EDAC labels that name the SMBIOS locator and the OEM locator layouts.
+// Without a match the counts stay on the EDAC entries, since pairing by table
+// order could name the wrong module for replacement.
+func (m *Memory) correlateDIMMs() {
+	if len(m.EdacMemoryEntries) == 0 || len(m.PhysicalMemoryEntries) == 0 {
+		return
+	}
+
+	// Some boards repeat DeviceLocator per channel ("DIMM 0"); qualify those
+	// with the BankLocator so that messages name a unique slot.
+	seen := make(map[string]int, len(m.PhysicalMemoryEntries))
+	for _, d := range m.PhysicalMemoryEntries {
+		seen[d.DeviceLocator]++
+	}
+	for _, d := range m.PhysicalMemoryEntries {
+		d.locator = d.DeviceLocator
+		if seen[d.DeviceLocator] > 1 && d.BankLocator != "" {
+			d.locator = d.BankLocator + " " + d.DeviceLocator
+		}
+	}
+
+	if m.matchByLabel() {
+		m.DIMMMapping = mappingLabel
+	} else if name, ok := m.matchByLayout(systemVendor()); ok {
+		m.DIMMMapping = fmt.Sprintf("%s (%s)", mappingLayout, name)
+	} else {
+		return
+	}
+
+	for _, d := range m.PhysicalMemoryEntries {
+		d.aggregateErrors()
+	}
+}
+
+// matchByLabel handles firmware that sets the EDAC label to the silkscreen
+// locator (ACPI _STR or a ras-mc-ctl labels file). All EDAC DIMMs must match.
+func (m *Memory) matchByLabel() bool {
+	byLocator := make(map[string]*SmbiosMemoryEntry, len(m.PhysicalMemoryEntries))
+	for _, d := range m.PhysicalMemoryEntries {
+		byLocator[normalizeLocator(d.DeviceLocator)] = d
+	}
+
+	pairs := make(map[*EdacMemoryEntry]*SmbiosMemoryEntry, len(m.EdacMemoryEntries))
+	for _, e := range m.EdacMemoryEntries {
+		if e.Label == "" {
+			return false
+		}
+		d, ok := byLocator[normalizeLocator(e.Label)]
+		if !ok {
+			return false
+		}
+		pairs[e] = d
+	}
+
+	for e, d := range pairs {
+		link(e, d)
+	}
+	return true
+}
+
+// matchByLayout decodes the SMBIOS locators with the first layout that
+// understands all of them and matches positions against the EDAC topology.
+// All EDAC DIMMs must be matched for the layout to be accepted.
+func (m *Memory) matchByLayout(vendor string) (string, bool) {
+	edacPos, topo := m.edacPositions()
+	if len(edacPos) == 0 {
+		return "", false
+	}
+
+	for _, layout := range dimmLayouts {
+		if !layout.appliesTo(vendor) {
+			continue
+		}
+
+		slotPos, ok := layout.positions(m.slots, topo)
+		if !ok {
+			continue
+		}
+		smbiosPos := make([]dimmPosition, 0, len(m.PhysicalMemoryEntries))
+		for i, slot := range m.slots {
+			if slot.dimm != nil {
+				smbiosPos = append(smbiosPos, slotPos[i])
+			}
+		}
+
+		if pairs, ok := pairPositions(m.EdacMemoryEntries, edacPos, m.PhysicalMemoryEntries, smbiosPos); ok {
+			for e, d := range pairs {
+				link(e, d)
+			}
+			return layout.name, true
+		}
+	}
+
+	return "", false
+}
+
+// edacPositions returns the position of every EDAC DIMM (indexed like
+// m.EdacMemoryEntries) and the channel numbering. Intel labels give SrcID,
+// MC/Ha, Chan and DIMM; amd64_edac gives csrow and channel (UMC) per memory
+// controller, which is an AMD node: one per socket since Zen 2, one per die
+// on Naples.
+func (m *Memory) edacPositions() ([]dimmPosition, edacTopology) {
+	chPerMC, chPerNode := 0, 0
+	for _, e := range m.EdacMemoryEntries {
+		switch {
+		case e.ChannelID != "" && e.SocketID != "":
+			chPerMC = max(chPerMC, atoi(e.ChannelID)+1)
+		case strings.HasPrefix(e.MemoryLocation, "csrow") && len(e.layers) == 2:
+			chPerNode = max(chPerNode, e.layers[1]+1)
+		}
+	}
+
+	nodesPerSocket := 0
+	if chPerNode > 0 {
+		nodesPerSocket = amdNodesPerSocket(m.EdacMemoryEntries)
+		if chPerMC == 0 {
+			chPerMC = chPerNode
+		}
+	}
+
+	res := make([]dimmPosition, 0, len(m.EdacMemoryEntries))
+	topo := edacTopology{perMC: chPerMC}
+	for _, e := range m.EdacMemoryEntries {
+		var p dimmPosition
+		switch {
+		case e.SocketID != "" && e.ChannelID != "":
+			mc := max(atoi(e.MemoryControllerID), 0)
+			p = dimmPosition{socket: atoi(e.SocketID), channel: mc*chPerMC + atoi(e.ChannelID), slot: atoi(e.DIMMID)}
+		case strings.HasPrefix(e.MemoryLocation, "csrow") && len(e.layers) == 2 && e.mc >= 0 && nodesPerSocket > 0:
+			// Nodes are numbered socket by socket; two chip selects per DIMM.
+			node := e.mc % nodesPerSocket
+			p = dimmPosition{socket: e.mc / nodesPerSocket, channel: node*chPerNode + e.layers[1], slot: e.layers[0] / 2}
+		default:
+			return nil, topo
+		}
+		if p.socket < 0 || p.channel < 0 {
+			return nil, topo
+		}
+		topo.channels = max(topo.channels, p.channel+1)
+		res = append(res, p)
+	}
+
+	return res, topo
+}
+
+// amdNodesPerSocket returns how many AMD nodes, i.e. amd64_edac memory
+// controllers, each socket has, or 0 when it cannot be told. Every node has
+// a data fabric function 3 at 00:18.3 + node; nodes without DIMMs have no
+// controller, so the EDAC controllers alone may undercount.
+func amdNodesPerSocket(entries []*EdacMemoryEntry) int {
+	nodes, _ := filepath.Glob(filepath.Join(sysfsPCI, "0000:00:1[89a-f].3"))
+	n := len(nodes)
+	for _, e := range entries {
+		n = max(n, e.mc+1)
+	}
+
+	sockets := installedSockets()
+	if n == 0 || sockets == 0 || n%sockets != 0 {
+		return 0
+	}
+	return n / sockets
+}
+
+// appliesTo reports whether the layout may be used for the system vendor.
+// All layouts apply when the vendor is unknown.
+func (l dimmLayout) appliesTo(vendor string) bool {
+	if len(l.vendors) == 0 || vendor == "" {
+		return true
+	}
+	for _, v := range l.vendors {
+		if strings.HasPrefix(strings.ToLower(vendor), strings.ToLower(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// positions decodes every SMBIOS memory slot, populated or not, so that the
+// numbering base is seen on the whole board; it fails when one does not match.
+func (l dimmLayout) positions(slots []*memorySlot, topo edacTopology) ([]dimmPosition, bool) {
+	if len(slots) == 0 {
+		return nil, false
+	}
+
+	matches := make([][]string, 0, len(slots))
+	var base []int
+	for _, slot := range slots {
+		s := strings.TrimSpace(slot.deviceLocator)
+		if l.bank {
+			s = strings.TrimSpace(slot.bankLocator + " " + s)
+		}
+		m := l.pattern.FindStringSubmatch(s)
+		if m == nil {
+			return nil, false
+		}
+		if base == nil {
+			base = slices.Repeat([]int{1}, len(m))
+		}
+		for i, g := range m {
+			if g == "0" {
+				base[i] = 0
+			}
+		}
+		matches = append(matches, m)
+	}
+
+	res := make([]dimmPosition, 0, len(matches))
+	for _, m := range matches {
+		res = append(res, l.decode(m, topo, base))
+	}
+	return res, true
+}
+
+// pairPositions matches every EDAC position to one SMBIOS position. Unknown
+// SMBIOS channel or slot values match when the remaining candidates on the
+// socket are unambiguous in table order.
+func pairPositions(edac []*EdacMemoryEntry, edacPos []dimmPosition, dimms []*SmbiosMemoryEntry, dimmPos []dimmPosition) (map[*EdacMemoryEntry]*SmbiosMemoryEntry, bool) {
+	res := make(map[*EdacMemoryEntry]*SmbiosMemoryEntry, len(edac))
+
+	// Exact matches first.
+	bySlot := make(map[dimmPosition]*SmbiosMemoryEntry, len(dimms))
+	byChannel := make(map[dimmPosition][]*SmbiosMemoryEntry, len(dimms))
+	bySocket := make(map[int][]*SmbiosMemoryEntry, 2)
+	for i, d := range dimms {
+		p := dimmPos[i]
+		if p.channel >= 0 && p.slot >= 0 {
+			bySlot[p] = d
+		}
+		if p.channel >= 0 {
+			k := dimmPosition{socket: p.socket, channel: p.channel, slot: -1}
+			byChannel[k] = append(byChannel[k], d)
+		}
+		bySocket[p.socket] = append(bySocket[p.socket], d)
+	}
+
+	// Socket-only layouts pair each socket's EDAC DIMMs in order.
+	edacBySocket := make(map[int][]dimmPosition, 2)
+	for _, p := range edacPos {
+		if !slices.Contains(edacBySocket[p.socket], p) {
+			edacBySocket[p.socket] = append(edacBySocket[p.socket], p)
+		}
+	}
+	for s := range edacBySocket {
+		sort.Slice(edacBySocket[s], func(i, j int) bool { return edacBySocket[s][i].less(edacBySocket[s][j]) })
+	}
+
+	for i, e := range edac {
+		p := edacPos[i]
+		if d, ok := bySlot[p]; ok {
+			res[e] = d
+			continue
+		}
+		if c := byChannel[dimmPosition{socket: p.socket, channel: p.channel, slot: -1}]; len(c) == 1 {
+			res[e] = c[0]
+			continue
+		}
+
+		// Fall back to order within the socket when the layout encodes the
+		// socket only and the counts agree.
+		onSocket := bySocket[p.socket]
+		positions := edacBySocket[p.socket]
+		if len(onSocket) == len(positions) && len(byChannel) == 0 {
+			for j, q := range positions {
+				if q == p {
+					res[e] = onSocket[j]
+				}
+			}
+		}
+		if res[e] == nil {
+			return nil, false
+		}
+	}
+
+	return res, true
+}
+
+// less orders positions by socket, channel and slot.
+func (p dimmPosition) less(q dimmPosition) bool {
+	if p.socket != q.socket {
+		return p.socket < q.socket
+	}
+	if p.channel != q.channel {
+		return p.channel < q.channel
+	}
+	return p.slot < q.slot
+}
+
+// link records that EDAC DIMM e is part of SMBIOS DIMM d.
+func link(e *EdacMemoryEntry, d *SmbiosMemoryEntry) {
+	e.smbios = d
+	d.edac = append(d.edac, e)
+}
+
+// aggregateErrors sums the counters of the EDAC DIMMs (ranks) of d.
+func (d *SmbiosMemoryEntry) aggregateErrors() {
+	if len(d.edac) == 0 {
+		return
+	}
+
+	var ce, ue, histCE, histUE int
+	labels := make([]string, 0, len(d.edac))
+	trend := ""
+	for _, e := range d.edac {
+		ce += atoiZero(e.CorrectableErrors)
+		ue += atoiZero(e.UncorrectableErrors)
+		label := e.Label
+		if label == "" {
+			label = e.MemoryLocation
+		}
+		labels = append(labels, label)
+
+		if h := e.ErrorHistory; h != nil {
+			histCE += h.corrected
+			histUE += h.uncorrected
+			trend = worseTrend(trend, h.Trend)
+		}
+	}
+
+	d.EdacLabel = strings.Join(labels, ", ")
+	d.CorrectableErrors = strconv.Itoa(ce)
+	d.UncorrectableErrors = strconv.Itoa(ue)
+	if trend != "" {
+		d.HistoryErrors = fmt.Sprintf("%d CE / %d UE", histCE, histUE)
+		d.ErrorTrend = trend
+	}
+}
+
+// name identifies a physical DIMM in diagnose messages by locator and serial.
+func (d *SmbiosMemoryEntry) name() string {
+	locator := d.locator
+	if locator == "" {
+		locator = d.DeviceLocator
+	}
+	if d.SerialNumber == "" {
+		return locator
+	}
+	return fmt.Sprintf("%s (SN %s)", locator, d.SerialNumber)
+}
+
+// name identifies an EDAC DIMM by its physical DIMM when mapped, else by its
+// EDAC label or location.
+func (e *EdacMemoryEntry) name() string {
+	switch {
+	case e.smbios != nil:
+		return e.smbios.name()
+	case e.Label != "":
+		return e.Label
+	}
+	return e.MemoryLocation
+}
+
+// worseTrend returns the more urgent of two trends.
+func worseTrend(a, b string) string {
+	rank := map[string]int{"": 0, trendHistorical: 1, trendRecent: 2, trendBurst: 3}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+// atoiZero converts a counter, treating empty or invalid values as zero.
+func atoiZero(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// normalizeLocator lower-cases a locator and drops separators so that
+// "DIMM_A1", "DIMM A1" and "dimm-a1" compare equal.
+func normalizeLocator(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '-', '#':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(s)))
+}
+
+// systemVendor returns the SMBIOS system manufacturer.
+func systemVendor() string {
+	systems, err := smbios.GetTypeData[*smbios.Type1System](1)
+	if err != nil || len(systems) == 0 {
+		return ""
+	}
+	return systems[0].Manufacturer
+}
@@ -0,0 +1,329 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/baize/internal/collector/pci"
+	"github.com/zenithax-cc/baize/pkg/execute"
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+const (
+	cxlDevicesPath = "/sys/bus/cxl/devices"
+	cxlCLI         = "cxl"
+)
+
+// collectCXL reads CXL Type 3 memory devices (memory expanders), their
+// endpoint decoders and the regions built across them. Health and poison
+// records are not exposed in sysfs and are taken from the cxl CLI when it is
+// installed.
+func (m *Memory) collectCXL(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if !utils.PathExists(cxlDevicesPath) {
+		return nil
+	}
+
+	var errs []error
+
+	mems, _ := filepath.Glob(filepath.Join(cxlDevicesPath, "mem[0-9]*"))
+	for _, dir := range mems {
+		m.CXLDevices = append(m.CXLDevices, parseCXLMemdev(dir))
+	}
+	sortByNumber(m.CXLDevices, func(d *CXLDevice) string { return d.Name })
+
+	regions, _ := filepath.Glob(filepath.Join(cxlDevicesPath, "region[0-9]*"))
+	for _, dir := range regions {
+		r, err := parseCXLRegion(dir)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		m.CXLRegions = append(m.CXLRegions, r)
+	}
+	sortByNumber(m.CXLRegions, func(r *CXLRegion) string { return r.Name })
+
+	var total int64
+	for _, d := range m.CXLDevices {
+		total += d.ramSize + d.pmemSize
+	}
+	if total > 0 {
+		m.CXLMemorySize = utils.KGMT(float64(total), true)
+	}
+
+	if len(m.CXLDevices) > 0 {
+		if err := m.collectCXLHealth(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// cxlSystemRAM returns the volatile CXL capacity the kernel onlines as system
+// RAM: the committed ram regions, or the devices' ram partitions when the
+// firmware set them up without regions.
+func (m *Memory) cxlSystemRAM() int64 {
+	var size int64
+	for _, r := range m.CXLRegions {
+		if r.Mode == "ram" && r.State == "Committed" {
+			size += r.size
+		}
+	}
+	if size > 0 || len(m.CXLRegions) > 0 {
+		return size
+	}
+
+	for _, d := range m.CXLDevices {
+		size += d.ramSize
+	}
+	return size
+}
+
+// cxlIssues reports CXL devices with health warnings or poison records.
+func (m *Memory) cxlIssues() []string {
+	var msg []string
+	for _, d := range m.CXLDevices {
+		if len(d.issues) > 0 {
+			msg = append(msg, fmt.Sprintf("CXL %s reports %s", d.Name, strings.Join(d.issues, ", ")))
+		}
+		if d.poison > 0 {
+			msg = append(msg, fmt.Sprintf("CXL %s has %d poison records", d.Name, d.poison))
+		}
+	}
+	return msg
+}
+
+// parseCXLMemdev reads one memN device. Its sysfs path runs through the PCIe
+// function of the expander, e.g.
+// /sys/devices/pci0000:0c/0000:0c:01.1/0000:0d:00.0/mem0.
+func parseCXLMemdev(dir string) *CXLDevice {
+	d := &CXLDevice{Name: filepath.Base(dir)}
+
+	d.SerialNumber = readSysfs(dir, "serial")
+	d.Firmware = readSysfs(dir, "firmware_version")
+	d.NUMANode = readSysfs(dir, "numa_node")
+	d.Security = readSysfs(dir, "security/state")
+
+	d.ramSize = parseHexSize(readSysfs(dir, "ram/size"))
+	d.pmemSize = parseHexSize(readSysfs(dir, "pmem/size"))
+	if d.ramSize > 0 {
+		d.RAMSize = utils.KGMT(float64(d.ramSize), true)
+	}
+	if d.pmemSize > 0 {
+		d.PMemSize = utils.KGMT(float64(d.pmemSize), true)
+	}
+
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		addr := filepath.Base(filepath.Dir(real))
+		p := pci.New(addr)
+		if err := p.Collect(); err == nil {
+			d.PCIe = p
+		}
+		d.Decoders = cxlEndpointDecoders(d.Name)
+	}
+
+	return d
+}
+
+// cxlEndpointDecoders lists the committed endpoint decoders below a memdev.
+// Decoders are flat in /sys/bus/cxl/devices, so the owner is found from the
+// resolved path, e.g. .../mem0/endpoint5/decoder5.0.
+func cxlEndpointDecoders(memdev string) string {
+	decoders, _ := filepath.Glob(filepath.Join(cxlDevicesPath, "decoder[0-9]*.[0-9]*"))
+
+	var res []string
+	for _, dir := range decoders {
+		real, err := filepath.EvalSymlinks(dir)
+		if err != nil || !strings.Contains(real, "/"+memdev+"/") {
+			continue
+		}
+
+		size := parseHexSize(readSysfs(dir, "size"))
+		if size == 0 {
+			continue
+		}
+
+		desc := fmt.Sprintf("%s (%s, %s", filepath.Base(dir), readSysfs(dir, "mode"), utils.KGMT(float64(size), true))
+		if region := readSysfs(dir, "region"); region != "" {
+			desc += ", " + region
+		}
+		res = append(res, desc+")")
+	}
+
+	sort.Strings(res)
+	return strings.Join(res, "; ")
+}
+
+// parseCXLRegion reads a region created on a root decoder. Volatile (ram)
+// regions are onlined as system RAM and counted in MemTotal.
+func parseCXLRegion(dir string) (*CXLRegion, error) {
+	r := &CXLRegion{Name: filepath.Base(dir)}
+
+	size := readSysfs(dir, "size")
+	if size == "" {
+		return nil, fmt.Errorf("read %s size failed", r.Name)
+	}
+	r.size = parseHexSize(size)
+	r.Size = utils.KGMT(float64(r.size), true)
+
+	r.Mode = readSysfs(dir, "mode")
+	r.Resource = readSysfs(dir, "resource")
+	r.InterleaveWays = readSysfs(dir, "interleave_ways")
+	r.InterleaveGranularity = readSysfs(dir, "interleave_granularity")
+	r.UUID = readSysfs(dir, "uuid")
+	if r.UUID == "00000000-0000-0000-0000-000000000000" {
+		r.UUID = ""
+	}
+
+	r.State = "Uncommitted"
+	if readSysfs(dir, "commit") == "1" {
+		r.State = "Committed"
+	}
+
+	// targetN holds the endpoint decoder at each interleave position.
+	ways, _ := strconv.Atoi(r.InterleaveWays)
+	targets := make([]string, 0, ways)
+	for i := 0; i < ways; i++ {
+		if t := readSysfs(dir, "target"+strconv.Itoa(i)); t != "" {
+			targets = append(targets, t)
+		}
+	}
+	r.Targets = strings.Join(targets, ", ")
+
+	return r, nil
+}
+
+// cxlListEntry is the part of `cxl list -M -H --media-errors` output baize uses.
+type cxlListEntry struct {
+	Memdev string `json:"memdev"`
+	Health *struct {
+		MaintenanceNeeded    bool   `json:"maintenance_needed"`
+		PerformanceDegraded  bool   `json:"performance_degraded"`
+		HWReplacementNeeded  bool   `json:"hw_replacement_needed"`
+		MediaNormal          bool   `json:"media_normal"`
+		MediaNotReady        bool   `json:"media_not_ready"`
+		MediaPersistenceLost bool   `json:"media_persistence_lost"`
+		MediaDataLost        bool   `json:"media_data_lost"`
+		LifeUsed             string `json:"ext_life_used"`
+		LifeUsedPercent      *int   `json:"life_used_percent"`
+		Temperature          *int   `json:"temperature"`
+		DirtyShutdowns       *int   `json:"dirty_shutdowns"`
+		VolatileErrors       *int   `json:"volatile_errors"`
+		PMemErrors           *int   `json:"pmem_errors"`
+	} `json:"health"`
+	MediaErrors *struct {
+		Nr int `json:"nr_media_errors"`
+	} `json:"media_errors"`
+}
+
+// collectCXLHealth fills health and poison records from the cxl CLI. Older
+// versions lack --media-errors, so the query is retried without it.
+func (m *Memory) collectCXLHealth(ctx context.Context) error {
+	out := execute.CommandWithContext(ctx, cxlCLI, "list", "-M", "-H", "--media-errors")
+	if !out.Success() {
+		out = execute.CommandWithContext(ctx, cxlCLI, "list", "-M", "-H")
+	}
+	if out.Err != nil {
+		if errors.Is(out.Err, exec.ErrNotFound) {
+			return nil
+		}
+		return out.Err
+	}
+
+	var entries []cxlListEntry
+	if err := json.Unmarshal(out.Stdout, &entries); err != nil {
+		return fmt.Errorf("unmarshal cxl list output: %w", err)
+	}
+
+	byName := make(map[string]*CXLDevice, len(m.CXLDevices))
+	for _, d := range m.CXLDevices {
+		byName[d.Name] = d
+	}
+
+	for _, e := range entries {
+		d, ok := byName[e.Memdev]
+		if !ok {
+			continue
+		}
+
+		if e.MediaErrors != nil {
+			d.PoisonRecords = strconv.Itoa(e.MediaErrors.Nr)
+			d.poison = e.MediaErrors.Nr
+		}
+
+		h := e.Health
+		if h == nil {
+			continue
+		}
+
+		var issues []string
+		for _, f := range []struct {
+			set  bool
+			name string
+		}{
+			{h.MaintenanceNeeded, "maintenance needed"},
+			{h.PerformanceDegraded, "performance degraded"},
+			{h.HWReplacementNeeded, "replacement needed"},
+			{h.MediaNotReady, "media not ready"},
+			{h.MediaPersistenceLost, "persistence lost"},
+			{h.MediaDataLost, "data lost"},
+		} {
+			if f.set {
+				issues = append(issues, f.name)
+			}
+		}
+		if h.LifeUsed != "" && h.LifeUsed != "normal" {
+			issues = append(issues, "life used "+h.LifeUsed)
+		}
+		d.issues = issues
+
+		if len(issues) == 0 {
+			d.Health = "OK"
+		} else {
+			d.Health = strings.Join(issues, ", ")
+		}
+		if h.LifeUsedPercent != nil {
+			d.LifeUsed = fmt.Sprintf("%d%%", *h.LifeUsedPercent)
+		}
+		if h.Temperature != nil {
+			d.Temperature = fmt.Sprintf("%d °C", *h.Temperature)
+		}
+		if h.DirtyShutdowns != nil {
+			d.DirtyShutdowns = strconv.Itoa(*h.DirtyShutdowns)
+		}
+		if h.VolatileErrors != nil {
+			d.VolatileErrors = strconv.Itoa(*h.VolatileErrors)
+		}
+		if h.PMemErrors != nil {
+			d.PMemErrors = strconv.Itoa(*h.PMemErrors)
+		}
+	}
+
+	return nil
+}
+
+// parseHexSize parses a sysfs size such as "0x4000000000"; "" and errors give 0.
+func parseHexSize(s string) int64 {
+	v, err := strconv.ParseInt(s, 0, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// readSysfs reads one attribute of a sysfs device directory, or "".
+func readSysfs(dir, name string) string {
+	v, _ := utils.ReadSysfsFile(filepath.Join(dir, name))
+	return v
+}
@@ -11,7 +11,10 @@ import (
 	"github.com/zenithax-cc/baize/pkg/utils"
 )
 
-const edacPath = "/sys/devices/system/edac/mc/"
+const (
+	edacPath = "/sys/devices/system/edac/mc/"
+	sysfsPCI = "/sys/bus/pci/devices"
+)
 
 func (m *Memory) collectEdacMemory(ctx context.Context) error {
 	if err := ctx.Err(); err != nil {
@@ -47,6 +50,12 @@ func (m *Memory) collectEdacMemory(ctx context.Context) error {
 func parseDimmDir(dimmDir string) (*EdacMemoryEntry, error) {
 	dimm := &EdacMemoryEntry{
 		DIMMID: filepath.Base(dimmDir),
+		mc:     -1,
+	}
+
+	// e.g. /sys/devices/system/edac/mc/mc1/dimm3 → memory controller 1.
+	if mc, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(filepath.Dir(dimmDir)), "mc")); err == nil {
+		dimm.mc = mc
 	}
 
 	fields := []struct {
@@ -70,12 +79,32 @@ func parseDimmDir(dimmDir string) (*EdacMemoryEntry, error) {
 	}
 
 	if content, err := os.ReadFile(filepath.Join(dimmDir, "dimm_label")); err == nil {
-		parseDimmLabel(dimm, strings.TrimSpace(string(content)))
+		dimm.Label = strings.TrimSpace(string(content))
+		parseDimmLabel(dimm, dimm.Label)
 	}
 
+	dimm.layers = parseDimmLocation(dimm.MemoryLocation)
+
 	return dimm, nil
 }
 
+// parseDimmLocation returns the layer indexes of a dimm_location such as
+// "channel 1 slot 0" or "csrow 2 channel 0", outermost layer first. They
+// correspond to the top, middle and lower layers of EDAC error events.
+func parseDimmLocation(location string) []int {
+	fields := strings.Fields(location)
+
+	res := make([]int, 0, 3)
+	for i := 1; i < len(fields); i += 2 {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return nil
+		}
+		res = append(res, n)
+	}
+	return res
+}
+
 func parseDimmLabel(dimm *EdacMemoryEntry, content string) {
 	parts := strings.Split(content, "_")
 	if len(parts) < 4 {
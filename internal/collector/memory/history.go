@@ -0,0 +1,249 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zenithax-cc/baize/internal/collector/rasdaemon"
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+const (
+	// historyEvents is the number of recent events kept per DIMM.
+	historyEvents = 20
+
+	// A DIMM with at least burstThreshold errors within burstWindow is in a burst.
+	burstWindow    = 24 * time.Hour
+	burstThreshold = 10
+	// recentWindow separates recent errors from historical noise.
+	recentWindow = 30 * 24 * time.Hour
+
+	trendBurst      = "Burst"
+	trendRecent     = "Recent"
+	trendHistorical = "Historical"
+
+	sourceMCEvent = "mc_event"
+	sourceExtlog  = "extlog_event"
+)
+
+// extlogSeverities maps the CPER severity of extlog events.
+var extlogSeverities = map[int]string{
+	0: "Recoverable",
+	1: "Fatal",
+	2: "Corrected",
+	3: "Informational",
+}
+
+// collectErrorHistory attaches the memory errors recorded by rasdaemon to the
+// EDAC DIMMs they were reported on. It is a no-op when rasdaemon is not used.
+func (m *Memory) collectErrorHistory() error {
+	mcEvents, err := rasdaemon.MCEvents()
+	if errors.Is(err, rasdaemon.ErrNoDatabase) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("rasdaemon mc_event: %w", err)
+	}
+
+	errs := make([]error, 0, 1)
+	extlogEvents, err := rasdaemon.ExtlogEvents()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("rasdaemon extlog_event: %w", err))
+	}
+
+	byEntry := make(map[*EdacMemoryEntry][]*ErrorEvent)
+	unattributed := 0
+	for _, ev := range mcEvents {
+		entry := m.matchMCEvent(ev)
+		if entry == nil {
+			unattributed += max(ev.ErrCount, 1)
+			continue
+		}
+
+		byEntry[entry] = append(byEntry[entry], &ErrorEvent{
+			Time:        formatEventTime(ev.Time, ev.Timestamp),
+			Source:      sourceMCEvent,
+			Type:        ev.ErrType,
+			Count:       strconv.Itoa(max(ev.ErrCount, 1)),
+			Address:     fmt.Sprintf("0x%x", ev.Address),
+			Message:     strings.TrimSpace(strings.Join([]string{ev.ErrMsg, ev.DriverDetail}, " ")),
+			time:        ev.Time,
+			count:       max(ev.ErrCount, 1),
+			uncorrected: ev.ErrType != "Corrected" && ev.ErrType != "Info",
+		})
+	}
+
+	for _, ev := range extlogEvents {
+		entry := m.matchFRU(ev.FRUText)
+		if entry == nil {
+			unattributed += max(ev.ErrorCount, 1)
+			continue
+		}
+
+		byEntry[entry] = append(byEntry[entry], &ErrorEvent{
+			Time:        formatEventTime(ev.Time, ev.Timestamp),
+			Source:      sourceExtlog,
+			Type:        extlogSeverities[ev.Severity],
+			Count:       strconv.Itoa(max(ev.ErrorCount, 1)),
+			Address:     fmt.Sprintf("0x%x", ev.Address),
+			Message:     ev.FRUText,
+			time:        ev.Time,
+			count:       max(ev.ErrorCount, 1),
+			uncorrected: ev.Severity == 0 || ev.Severity == 1,
+		})
+	}
+
+	boot, _ := utils.BootTime()
+	now := time.Now()
+	for entry, events := range byEntry {
+		entry.ErrorHistory = summarizeHistory(events, boot, now)
+	}
+	if unattributed > 0 {
+		m.UnattributedErrors = strconv.Itoa(unattributed)
+	}
+
+	return errors.Join(errs...)
+}
+
+// matchMCEvent returns the EDAC DIMM an mc_event was reported on, by label
+// or, when the driver could not name a single DIMM, by memory controller and
+// layer indexes. Nil is returned when the event cannot be attributed to
+// exactly one DIMM.
+func (m *Memory) matchMCEvent(ev *rasdaemon.MCEvent) *EdacMemoryEntry {
+	var labels []string
+	for _, l := range strings.Split(ev.Label, " or ") {
+		if l = strings.TrimSpace(l); l != "" && !strings.HasSuffix(l, " memory") {
+			labels = append(labels, l)
+		}
+	}
+
+	if len(labels) == 1 {
+		if entry := m.uniqueEdacEntry(func(e *EdacMemoryEntry) bool { return e.Label == labels[0] }); entry != nil {
+			return entry
+		}
+	}
+
+	eventLayers := []int{ev.TopLayer, ev.MiddleLayer, ev.LowerLayer}
+	return m.uniqueEdacEntry(func(e *EdacMemoryEntry) bool {
+		if e.mc != ev.MC || len(e.layers) == 0 {
+			return false
+		}
+		matched := false
+		for i, l := range e.layers {
+			// -1 means the driver did not resolve that layer.
+			if i >= len(eventLayers) || eventLayers[i] < 0 {
+				continue
+			}
+			if eventLayers[i] != l {
+				return false
+			}
+			matched = true
+		}
+		return matched
+	})
+}
+
+// matchFRU returns the EDAC DIMM whose label or location equals the FRU text
+// reported by firmware.
+func (m *Memory) matchFRU(fru string) *EdacMemoryEntry {
+	fru = strings.TrimSpace(fru)
+	if fru == "" {
+		return nil
+	}
+
+	return m.uniqueEdacEntry(func(e *EdacMemoryEntry) bool {
+		return strings.EqualFold(e.Label, fru) || strings.EqualFold(e.MemoryLocation, fru)
+	})
+}
+
+// uniqueEdacEntry returns the only EDAC entry matching fn, or nil.
+func (m *Memory) uniqueEdacEntry(fn func(*EdacMemoryEntry) bool) *EdacMemoryEntry {
+	var res *EdacMemoryEntry
+	for _, e := range m.EdacMemoryEntries {
+		if !fn(e) {
+			continue
+		}
+		if res != nil {
+			return nil
+		}
+		res = e
+	}
+	return res
+}
+
+// summarizeHistory builds the error history of one DIMM from its events.
+func summarizeHistory(events []*ErrorEvent, boot, now time.Time) *ErrorHistory {
+	sort.SliceStable(events, func(i, j int) bool { return events[i].time.Before(events[j].time) })
+
+	h := &ErrorHistory{}
+	beforeBoot := 0
+	var first, last time.Time
+	for _, ev := range events {
+		if ev.uncorrected {
+			h.uncorrected += ev.count
+		} else {
+			h.corrected += ev.count
+		}
+
+		if ev.time.IsZero() {
+			continue
+		}
+		if !boot.IsZero() && ev.time.Before(boot) {
+			beforeBoot += ev.count
+		}
+		if now.Sub(ev.time) <= burstWindow {
+			h.last24h += ev.count
+		}
+		if first.IsZero() {
+			first = ev.time
+		}
+		last = ev.time
+	}
+
+	h.CorrectedErrors = strconv.Itoa(h.corrected)
+	h.UncorrectedErrors = strconv.Itoa(h.uncorrected)
+	h.BeforeBoot = strconv.Itoa(beforeBoot)
+	h.Last24Hours = strconv.Itoa(h.last24h)
+	if !first.IsZero() {
+		h.FirstSeen = first.Local().Format(time.DateTime)
+		h.LastSeen = last.Local().Format(time.DateTime)
+	}
+	h.Trend = classifyTrend(h.last24h, last, now)
+
+	if len(events) > historyEvents {
+		events = events[len(events)-historyEvents:]
+	}
+	h.Events = slices.Clone(events)
+
+	return h
+}
+
+// classifyTrend tells an ongoing burst from old noise: "Burst" when at least
+// burstThreshold errors were logged in the last 24 hours, "Recent" when the
+// last error is within 30 days and "Historical" otherwise.
+func classifyTrend(last24h int, last, now time.Time) string {
+	switch {
+	case last24h >= burstThreshold:
+		return trendBurst
+	case !last.IsZero() && now.Sub(last) <= recentWindow:
+		return trendRecent
+	case last.IsZero():
+		return ""
+	default:
+		return trendHistorical
+	}
+}
+
+// formatEventTime formats an event time in local time, falling back to the
+// raw timestamp when it could not be parsed.
+func formatEventTime(t time.Time, raw string) string {
+	if t.IsZero() {
+		return raw
+	}
+	return t.Local().Format(time.DateTime)
+}
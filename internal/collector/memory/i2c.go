@@ -0,0 +1,250 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// Linux i2c-dev ioctls and SMBus transaction types (linux/i2c-dev.h, linux/i2c.h).
+const (
+	ioctlI2CSlave = 0x0703
+	ioctlI2CSMBus = 0x0720
+
+	smbusWrite    = 0
+	smbusRead     = 1
+	smbusQuick    = 0
+	smbusByteData = 2
+
+	// DDR4 EE1004 page select addresses (SPA0/SPA1); a quick write to one of
+	// them selects the lower or upper 256 bytes on every SPD of the bus.
+	ee1004SetPage0 = 0x36
+	ee1004SetPage1 = 0x37
+
+	// SPD5118 hub registers: MR0/MR1 hold the device type 0x5118, MR11
+	// selects the 128-byte NVM page visible at offsets 0x80-0xff.
+	spd5118MR0    = 0x00
+	spd5118MR11   = 0x0b
+	spd5118Legacy = 0x80
+)
+
+// spdAdapters are name prefixes of the host SMBus controllers that carry the
+// DIMM SPDs. Other I2C buses are never probed.
+var spdAdapters = []string{"SMBus I801", "SMBus PIIX4"}
+
+// spdRawI2C enables reading SPDs through /dev/i2c-* where no kernel EEPROM
+// driver is bound.
+var spdRawI2C bool
+
+// SetSPDRawI2C enables or disables reading SPD EEPROMs through i2c-dev. It is
+// disabled by default: it issues SMBus writes to select EEPROM pages, which
+// some platforms forbid while the BMC polls the same bus.
+func SetSPDRawI2C(enable bool) {
+	spdRawI2C = enable
+}
+
+// i2cSMBusData mirrors struct i2c_smbus_ioctl_data.
+type i2cSMBusData struct {
+	readWrite uint8
+	command   uint8
+	size      uint32
+	data      uintptr
+}
+
+// i2cBus is an open /dev/i2c-N.
+type i2cBus struct {
+	f *os.File
+}
+
+func (b *i2cBus) ioctl(req, arg uintptr) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, b.f.Fd(), req, arg); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// setAddr selects the target device. It fails with EBUSY when a kernel
+// driver owns the address.
+func (b *i2cBus) setAddr(addr int) error {
+	return b.ioctl(ioctlI2CSlave, uintptr(addr))
+}
+
+func (b *i2cBus) smbus(rw, cmd uint8, size uint32, data *[34]byte) error {
+	args := i2cSMBusData{readWrite: rw, command: cmd, size: size}
+	if data != nil {
+		args.data = uintptr(unsafe.Pointer(data))
+	}
+	return b.ioctl(ioctlI2CSMBus, uintptr(unsafe.Pointer(&args)))
+}
+
+func (b *i2cBus) readByte(cmd uint8) (byte, error) {
+	var data [34]byte
+	if err := b.smbus(smbusRead, cmd, smbusByteData, &data); err != nil {
+		return 0, err
+	}
+	return data[0], nil
+}
+
+func (b *i2cBus) writeByte(cmd, v uint8) error {
+	data := [34]byte{v}
+	return b.smbus(smbusWrite, cmd, smbusByteData, &data)
+}
+
+// quickWrite sends an SMBus quick write to addr.
+func (b *i2cBus) quickWrite(addr int) error {
+	if err := b.setAddr(addr); err != nil {
+		return err
+	}
+	return b.smbus(smbusWrite, 0, smbusQuick, nil)
+}
+
+// readRawSPDs reads and decodes the SPDs on the host SMBus adapters, skipping
+// the devices already read through a kernel driver.
+func readRawSPDs(seen map[string]bool) ([]*SPD, error) {
+	adapters, _ := filepath.Glob(filepath.Join(i2cDevicesPath, "i2c-*"))
+
+	var (
+		res  []*SPD
+		errs []error
+	)
+	for _, adapter := range adapters {
+		name, err := os.ReadFile(filepath.Join(adapter, "name"))
+		if err != nil || !hasAnyPrefix(string(name), spdAdapters) {
+			continue
+		}
+
+		var busNum int
+		if _, err := fmt.Sscanf(filepath.Base(adapter), "i2c-%d", &busNum); err != nil {
+			continue
+		}
+
+		spds, err := readBusSPDs(busNum, seen)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("i2c-%d: %w", busNum, err))
+		}
+		res = append(res, spds...)
+	}
+
+	return res, errors.Join(errs...)
+}
+
+// readBusSPDs reads every SPD address of one bus.
+func readBusSPDs(busNum int, seen map[string]bool) ([]*SPD, error) {
+	f, err := os.OpenFile(fmt.Sprintf("/dev/i2c-%d", busNum), os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	b := &i2cBus{f: f}
+
+	var (
+		res  []*SPD
+		errs []error
+	)
+	for addr := spdAddrFirst; addr <= spdAddrLast; addr++ {
+		device := fmt.Sprintf("%d-%04x", busNum, addr)
+		if seen[device] {
+			continue
+		}
+
+		data, err := b.readSPD(addr)
+		if err != nil {
+			// Empty slots do not acknowledge their address.
+			if !errors.Is(err, syscall.ENXIO) && !errors.Is(err, syscall.EIO) && !errors.Is(err, syscall.EBUSY) {
+				errs = append(errs, fmt.Errorf("0x%02x: %w", addr, err))
+			}
+			continue
+		}
+
+		s, err := decodeSPD(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("0x%02x: %w", addr, err))
+			continue
+		}
+
+		s.Source, s.Address, s.bus, s.addr = "i2c-dev", device, busNum, addr
+		res = append(res, s)
+	}
+
+	return res, errors.Join(errs...)
+}
+
+// readSPD reads a full DDR5 (SPD5118 hub) or DDR4 (EE1004) SPD image and
+// restores page 0 afterwards.
+func (b *i2cBus) readSPD(addr int) ([]byte, error) {
+	if err := b.setAddr(addr); err != nil {
+		return nil, err
+	}
+
+	mr0, err := b.readByte(spd5118MR0)
+	if err != nil {
+		return nil, err
+	}
+	mr1, err := b.readByte(spd5118MR0 + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if mr0 == 0x51 && mr1 == 0x18 {
+		return b.readSPD5118()
+	}
+	return b.readEE1004(addr)
+}
+
+// readSPD5118 reads the 1024-byte NVM of the current SPD5118 hub in eight
+// 128-byte pages.
+func (b *i2cBus) readSPD5118() ([]byte, error) {
+	defer b.writeByte(spd5118MR11, 0)
+
+	data := make([]byte, 0, spdSizeDDR5)
+	for page := range uint8(spdSizeDDR5 / 128) {
+		if err := b.writeByte(spd5118MR11, page); err != nil {
+			return nil, err
+		}
+		for off := range 128 {
+			v, err := b.readByte(spd5118Legacy + uint8(off))
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, v)
+		}
+	}
+	return data, nil
+}
+
+// readEE1004 reads both 256-byte pages of an EE1004 at addr.
+func (b *i2cBus) readEE1004(addr int) ([]byte, error) {
+	defer b.quickWrite(ee1004SetPage0)
+
+	data := make([]byte, 0, spdSizeDDR4)
+	for _, pageAddr := range []int{ee1004SetPage0, ee1004SetPage1} {
+		if err := b.quickWrite(pageAddr); err != nil {
+			return nil, err
+		}
+		if err := b.setAddr(addr); err != nil {
+			return nil, err
+		}
+		for off := range 256 {
+			v, err := b.readByte(uint8(off))
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, v)
+		}
+	}
+	return data, nil
+}
+
+// hasAnyPrefix reports whether s starts with one of prefixes.
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
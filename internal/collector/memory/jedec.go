@@ -0,0 +1,41 @@
+package memory
+
+import "fmt"
+
+// jedecManufacturers maps JEP106 manufacturer IDs of DRAM, module and SPD
+// component vendors, keyed by bank<<8 | code. Codes include the parity bit
+// as stored in SPD.
+var jedecManufacturers = map[uint16]string{
+	1<<8 | 0x01: "AMD",
+	1<<8 | 0x2c: "Micron",
+	1<<8 | 0x89: "Intel",
+	1<<8 | 0x94: "Smart Modular",
+	1<<8 | 0x97: "Texas Instruments",
+	1<<8 | 0xad: "SK Hynix",
+	1<<8 | 0xb3: "Renesas (IDT)",
+	1<<8 | 0xce: "Samsung",
+	2<<8 | 0x4f: "Transcend",
+	2<<8 | 0x98: "Kingston",
+	3<<8 | 0x9e: "Corsair",
+	3<<8 | 0xfe: "Elpida",
+	4<<8 | 0x0b: "Nanya",
+	4<<8 | 0x43: "Ramaxel",
+	5<<8 | 0xcb: "ADATA",
+	5<<8 | 0xcd: "G.Skill",
+	6<<8 | 0x32: "Montage",
+	6<<8 | 0x9b: "Crucial",
+}
+
+// jedecManufacturer decodes a two-byte SPD manufacturer ID: the number of
+// continuation codes (bits 6:0, bit 7 is parity) followed by the code.
+func jedecManufacturer(cont, code byte) string {
+	if cont == 0 && code == 0 || cont == 0xff && code == 0xff {
+		return ""
+	}
+
+	bank := uint16(cont&0x7f) + 1
+	if name, ok := jedecManufacturers[bank<<8|uint16(code)]; ok {
+		return name
+	}
+	return fmt.Sprintf("Unknown (bank %d, 0x%02X)", bank, code)
+}
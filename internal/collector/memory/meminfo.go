@@ -53,6 +53,12 @@ func (m *Memory) collectFromMeminfo(ctx context.Context) error {
 		if ptr, exists := fieldsMap[k]; exists {
 			*ptr = convertUnit(v)
 		}
+
+		if k == "MemTotal" {
+			if kb, err := strconv.ParseInt(strings.TrimSuffix(v, " kB"), 10, 64); err == nil {
+				m.memTotal = kb * 1024
+			}
+		}
 	}
 
 	return scanner.Err()
@@ -6,9 +6,11 @@ package memory
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/zenithax-cc/baize/internal/collector/smbios"
 	"github.com/zenithax-cc/baize/pkg/utils"
 )
 
@@ -30,6 +32,15 @@ func (m *Memory) Collect(ctx context.Context) error {
 		return err
 	}
 
+	// An SMBIOS dump describes another host: report its DIMMs only, without
+	// this host's meminfo, SPD, EDAC or population diagnoses.
+	if smbios.FromDump() {
+		m.Diagnose = ""
+		err := m.collectFromSMBIOS(ctx)
+		m.UsedSlots = strconv.Itoa(len(m.PhysicalMemoryEntries))
+		return err
+	}
+
 	errs := make([]error, 0, 4)
 
 	// Collect runtime memory statistics from /proc/meminfo.
@@ -42,11 +53,31 @@ func (m *Memory) Collect(ctx context.Context) error {
 		errs = append(errs, err)
 	}
 
+	// Read and decode the DIMM SPD EEPROMs and attach them to the SMBIOS entries.
+	if err := m.collectSPD(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
 	// Collect EDAC memory error counters from /sys/bus/edac/devices.
 	if err := m.collectEdacMemory(ctx); err != nil {
 		errs = append(errs, err)
 	}
 
+	// Attach the rasdaemon error history to the EDAC entries.
+	if err := m.collectErrorHistory(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Collect NVDIMM / PMem modules, regions and namespaces from /sys/bus/nd.
+	if err := m.collectPMem(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Collect CXL memory expanders, decoders and regions from /sys/bus/cxl.
+	if err := m.collectCXL(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
 	// Associate EDAC entries with SMBIOS entries and calculate total EDAC size.
 	if err := m.associate(); err != nil {
 		errs = append(errs, err)
@@ -106,8 +137,8 @@ func (m *Memory) BriefPrintln() {
 	utils.PrinterInstance.Print(memInfo, "MEMORY INFO")
 }
 
-// associate correlates EDAC and SMBIOS data:
-// it counts the number of used DIMM slots and sums total EDAC-reported memory size.
+// associate correlates EDAC and SMBIOS data: it counts the number of used DIMM
+// slots, sums total EDAC-reported memory size and maps EDAC DIMMs to SMBIOS DIMMs.
 func (m *Memory) associate() error {
 	var (
 		errs      []error
@@ -127,6 +158,8 @@ func (m *Memory) associate() error {
 
 	m.EdacMemorySize = utils.KGMT(float64(totalSize*1024*1024), true)
 
+	m.correlateDIMMs()
+
 	return errors.Join(errs...)
 }
 
@@ -140,23 +173,68 @@ func (m *Memory) diagnose() {
 		msg = append(msg, "SMBIOS and EDAC memory slots are not equal")
 	}
 
-	// Check if the OS-visible memory size diverges from SMBIOS physical size
-	// by more than one DIMM's worth (which may indicate a failed/missing module).
-	if len(m.PhysicalMemoryEntries) > 0 {
-		sysSize, sysErr := toBytes(m.MemTotal)
-		smbiosSize, smbiosErr := toBytes(m.PhysicalMemorySize)
-		if sysErr == nil && smbiosErr == nil {
-			if smbiosSize-sysSize > smbiosSize/len(m.PhysicalMemoryEntries) {
-				msg = append(msg, "has unhealthy memory")
-			}
+	// Check if the OS-visible memory size, less volatile CXL memory onlined as
+	// system RAM, falls short of the SMBIOS DIMM total by more than one DIMM's
+	// worth (which may indicate a failed/missing module).
+	if len(m.PhysicalMemoryEntries) > 0 && m.memTotal > 0 && m.physicalSize > 0 {
+		sysSize := m.memTotal - m.cxlSystemRAM()
+		if m.physicalSize-sysSize > m.physicalSize/int64(len(m.PhysicalMemoryEntries)) {
+			msg = append(msg, "has unhealthy memory")
 		}
 	}
 
-	// Warn when DIMM count is odd, which typically indicates an asymmetric configuration.
-	if len(m.PhysicalMemoryEntries)%2 != 0 {
-		msg = append(msg, "memory count should be even")
+	// Report DIMMs with uncorrectable errors since boot, by physical module
+	// where the EDAC DIMM could be mapped.
+	for _, d := range m.PhysicalMemoryEntries {
+		if n := atoiZero(d.UncorrectableErrors); n > 0 {
+			msg = append(msg, fmt.Sprintf("DIMM %s has %d uncorrectable errors", d.name(), n))
+		}
+	}
+	for _, e := range m.EdacMemoryEntries {
+		if n := atoiZero(e.UncorrectableErrors); n > 0 && e.smbios == nil {
+			msg = append(msg, fmt.Sprintf("DIMM %s has %d uncorrectable errors", e.name(), n))
+		}
 	}
 
+	// Report DIMMs with a burst of errors or recent uncorrected errors.
+	for _, e := range m.EdacMemoryEntries {
+		h := e.ErrorHistory
+		if h == nil {
+			continue
+		}
+		name := e.name()
+		if h.Trend == trendBurst {
+			msg = append(msg, fmt.Sprintf("DIMM %s logged %d errors in the last 24 hours", name, h.last24h))
+		}
+		if h.uncorrected > 0 && h.Trend != trendHistorical {
+			msg = append(msg, fmt.Sprintf("DIMM %s has %d uncorrected errors, last at %s", name, h.uncorrected, h.LastSeen))
+		}
+	}
+
+	// Report SPDs whose CRC does not match, which indicates a corrupt EEPROM.
+	for _, d := range m.PhysicalMemoryEntries {
+		if d.SPD != nil && d.SPD.Checksum != "OK" {
+			msg = append(msg, fmt.Sprintf("DIMM %s SPD checksum mismatch", d.name()))
+		}
+	}
+
+	// Report populated DIMMs that firmware did not map into the address
+	// space, when it provides Type 20 mappings at all.
+	if m.deviceMappings {
+		for _, slot := range m.slots {
+			if slot.dimm != nil && !slot.mapped {
+				msg = append(msg, fmt.Sprintf("DIMM %s is installed but not mapped into the address space", slot.dimm.name()))
+			}
+		}
+	}
+
+	// Report PMem and CXL memory devices in poor health or with bad blocks.
+	msg = append(msg, m.pmemIssues()...)
+	msg = append(msg, m.cxlIssues()...)
+
+	// Check the population against the platform rules per socket and channel.
+	msg = append(msg, m.checkPopulation()...)
+
 	if len(msg) != 0 {
 		m.Diagnose = "Unhealthy"
 		m.DiagnoseDetail = strings.Join(msg, "; ")
@@ -0,0 +1,232 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+const ndDevicesPath = "/sys/bus/nd/devices"
+
+// ndNamespaceModes maps the kernel namespace claim names to the ndctl modes.
+var ndNamespaceModes = map[string]string{
+	"raw":    "raw",
+	"safe":   "sector",
+	"memory": "fsdax",
+	"dax":    "devdax",
+}
+
+// collectPMem reads NVDIMM / Optane PMem modules, regions and namespaces from
+// the libnvdimm bus. Systems without NVDIMMs have no nd bus.
+func (m *Memory) collectPMem(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if !utils.PathExists(ndDevicesPath) {
+		return nil
+	}
+
+	var errs []error
+
+	dimms, _ := filepath.Glob(filepath.Join(ndDevicesPath, "nmem*"))
+	for _, dir := range dimms {
+		m.PMemDevices = append(m.PMemDevices, parseNVDIMM(dir))
+	}
+
+	regions, _ := filepath.Glob(filepath.Join(ndDevicesPath, "region*"))
+	var total int64
+	for _, dir := range regions {
+		r, err := parseNDRegion(dir)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		m.PMemRegions = append(m.PMemRegions, r)
+		total += r.size
+	}
+
+	sortByNumber(m.PMemDevices, func(d *PMemDevice) string { return d.Name })
+	sortByNumber(m.PMemRegions, func(r *PMemRegion) string { return r.Name })
+
+	if total > 0 {
+		m.PersistentMemorySize = utils.KGMT(float64(total), true)
+	}
+
+	return errors.Join(errs...)
+}
+
+// pmemIssues reports modules with NFIT health flags set and regions with
+// known bad blocks.
+func (m *Memory) pmemIssues() []string {
+	var msg []string
+	for _, d := range m.PMemDevices {
+		if d.Health != "" && d.Health != "OK" {
+			msg = append(msg, fmt.Sprintf("PMem %s reports %s", d.Name, d.Health))
+		}
+	}
+	for _, r := range m.PMemRegions {
+		if n := atoiZero(r.BadBlocks); n > 0 {
+			msg = append(msg, fmt.Sprintf("PMem %s has %d bad block ranges", r.Name, n))
+		}
+	}
+	return msg
+}
+
+// parseNVDIMM reads one nmem device and its ACPI NFIT attributes. The NFIT
+// flags list failures of the last save/restore cycle and health events.
+func parseNVDIMM(dir string) *PMemDevice {
+	d := &PMemDevice{Name: filepath.Base(dir)}
+
+	d.State = readSysfs(dir, "state")
+	d.Security = readSysfs(dir, "security")
+	d.Firmware = readSysfs(dir, "firmware/version")
+	d.ID = readSysfs(dir, "nfit/id")
+	d.Handle = readSysfs(dir, "nfit/handle")
+	d.PhysicalID = readSysfs(dir, "nfit/phys_id")
+	d.SerialNumber = readSysfs(dir, "nfit/serial")
+	d.Vendor = readSysfs(dir, "nfit/vendor")
+
+	flags := strings.Fields(readSysfs(dir, "nfit/flags"))
+	// smart_notify only means the DIMM supports health event notification.
+	flags = slicesDelete(flags, "smart_notify")
+	if len(flags) == 0 {
+		d.Health = "OK"
+	} else {
+		d.Health = strings.Join(flags, ", ")
+	}
+
+	return d
+}
+
+// parseNDRegion reads a region, its interleave set members and namespaces.
+func parseNDRegion(dir string) (*PMemRegion, error) {
+	r := &PMemRegion{Name: filepath.Base(dir)}
+
+	size, err := utils.ReadSysfsInt(filepath.Join(dir, "size"))
+	if err != nil {
+		return nil, err
+	}
+	r.size = int64(size)
+	r.Size = utils.KGMT(float64(size), true)
+
+	if v, err := utils.ReadSysfsInt(filepath.Join(dir, "available_size")); err == nil {
+		r.AvailableSize = "0 B"
+		if v > 0 {
+			r.AvailableSize = utils.KGMT(float64(v), true)
+		}
+	}
+
+	if t, err := utils.ReadSysfsFile(filepath.Join(dir, "devtype")); err == nil {
+		r.Type = strings.TrimPrefix(t, "nd_")
+	}
+	r.NUMANode, _ = utils.ReadSysfsFile(filepath.Join(dir, "numa_node"))
+	r.PersistenceDomain, _ = utils.ReadSysfsFile(filepath.Join(dir, "persistence_domain"))
+
+	// mappingN is "nmemX,offset,length,position".
+	mappings, _ := filepath.Glob(filepath.Join(dir, "mapping[0-9]*"))
+	sortByNumber(mappings, func(p string) string { return p })
+	var members []string
+	for _, p := range mappings {
+		if v, err := utils.ReadSysfsFile(p); err == nil {
+			members = append(members, strings.SplitN(v, ",", 2)[0])
+		}
+	}
+	r.Mappings = strings.Join(members, ", ")
+
+	if lines, err := utils.ReadLines(filepath.Join(dir, "badblocks")); err == nil {
+		r.BadBlocks = strconv.Itoa(countNonEmpty(lines))
+	}
+
+	namespaces, _ := filepath.Glob(filepath.Join(dir, "namespace*"))
+	for _, ns := range namespaces {
+		if n := parseNDNamespace(ns); n != nil {
+			r.Namespaces = append(r.Namespaces, n)
+		}
+	}
+	sortByNumber(r.Namespaces, func(n *PMemNamespace) string { return n.Name })
+
+	return r, nil
+}
+
+// parseNDNamespace reads a namespace; unconfigured (zero-size) seeds are skipped.
+func parseNDNamespace(dir string) *PMemNamespace {
+	size, err := utils.ReadSysfsInt(filepath.Join(dir, "size"))
+	if err != nil || size == 0 {
+		return nil
+	}
+
+	n := &PMemNamespace{
+		Name: filepath.Base(dir),
+		Size: utils.KGMT(float64(size), true),
+	}
+
+	mode, _ := utils.ReadSysfsFile(filepath.Join(dir, "mode"))
+	if v, ok := ndNamespaceModes[mode]; ok {
+		mode = v
+	}
+	n.Mode = mode
+	n.UUID, _ = utils.ReadSysfsFile(filepath.Join(dir, "uuid"))
+	n.NUMANode, _ = utils.ReadSysfsFile(filepath.Join(dir, "numa_node"))
+
+	// raw and fsdax namespaces own their block device; sector (btt) and devdax
+	// namespaces expose it through their holder, e.g. btt0.1 or dax0.1.
+	dirs := []string{dir}
+	if holder, err := utils.ReadSysfsFile(filepath.Join(dir, "holder")); err == nil && holder != "" {
+		dirs = append([]string{filepath.Join(ndDevicesPath, holder)}, dirs...)
+	}
+	for _, d := range dirs {
+		if blk, _ := filepath.Glob(filepath.Join(d, "block", "*")); len(blk) > 0 {
+			n.Device = "/dev/" + filepath.Base(blk[0])
+			break
+		}
+		if dax, _ := filepath.Glob(filepath.Join(d, "dax[0-9]*.[0-9]*")); len(dax) > 0 {
+			n.Device = "/dev/" + filepath.Base(dax[0])
+			break
+		}
+	}
+
+	return n
+}
+
+// slicesDelete returns s without the elements equal to v.
+func slicesDelete(s []string, v string) []string {
+	res := s[:0]
+	for _, e := range s {
+		if e != v {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// countNonEmpty counts the non-blank lines.
+func countNonEmpty(lines []string) int {
+	n := 0
+	for _, l := range lines {
+		if strings.TrimSpace(l) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// sortByNumber sorts items by the trailing number of their name, so that
+// "region10" sorts after "region2".
+func sortByNumber[T any](items []T, name func(T) string) {
+	num := func(s string) int {
+		i := len(s)
+		for i > 0 && s[i-1] >= '0' && s[i-1] <= '9' {
+			i--
+		}
+		n, _ := strconv.Atoi(s[i:])
+		return n
+	}
+	sort.SliceStable(items, func(i, j int) bool { return num(name(items[i])) < num(name(items[j])) })
+}
@@ -0,0 +1,305 @@
+package memory
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/baize/internal/collector/cpu"
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+const sysfsCPU = "/sys/devices/system/cpu"
+
+// platformRule describes the memory subsystem of one CPU generation, which
+// spans a range of CPUID models of one vendor and family. Zero channels means
+// the count differs between SKUs and is taken from EDAC or the locators
+// instead.
+type platformRule struct {
+	vendor      string
+	family      int
+	models      [2]int // first and last model, inclusive
+	name        string
+	channels    int
+	controllers int
+	maxDPC      int
+}
+
+// platformRules lists server CPU generations by CPUID family and model range.
+var platformRules = []platformRule{
+	{"Intel", 6, [2]int{0x3f, 0x3f}, "Intel Haswell-EP", 4, 2, 3},
+	{"Intel", 6, [2]int{0x4f, 0x4f}, "Intel Broadwell-EP", 4, 2, 3},
+	{"Intel", 6, [2]int{0x55, 0x55}, "Intel Skylake-SP / Cascade Lake", 6, 2, 2},
+	{"Intel", 6, [2]int{0x6a, 0x6a}, "Intel Ice Lake-SP", 8, 4, 2},
+	{"Intel", 6, [2]int{0x8f, 0x8f}, "Intel Sapphire Rapids", 8, 4, 2},
+	{"Intel", 6, [2]int{0xcf, 0xcf}, "Intel Emerald Rapids", 8, 4, 2},
+	{"Intel", 6, [2]int{0xad, 0xad}, "Intel Granite Rapids", 0, 0, 2},
+	{"Intel", 6, [2]int{0xaf, 0xaf}, "Intel Sierra Forest", 0, 0, 2},
+	{"AMD", 0x17, [2]int{0x00, 0x0f}, "AMD EPYC Naples", 8, 1, 2},
+	{"AMD", 0x17, [2]int{0x30, 0x3f}, "AMD EPYC Rome", 8, 1, 2},
+	{"AMD", 0x19, [2]int{0x00, 0x0f}, "AMD EPYC Milan", 8, 1, 2},
+	{"AMD", 0x19, [2]int{0x10, 0x1f}, "AMD EPYC Genoa", 12, 1, 2},
+	{"AMD", 0x19, [2]int{0xa0, 0xaf}, "AMD EPYC Bergamo / Siena", 0, 1, 2},
+	{"AMD", 0x1a, [2]int{0x00, 0x0f}, "AMD EPYC Turin", 12, 1, 2},
+	{"AMD", 0x1a, [2]int{0x10, 0x1f}, "AMD EPYC Turin Dense", 12, 1, 2},
+	{"Hygon", 0x18, [2]int{0x00, 0x0f}, "Hygon Dhyana", 8, 1, 2},
+}
+
+// slotPosition is an SMBIOS slot, populated or empty, with its decoded position.
+type slotPosition struct {
+	*memorySlot
+	pos dimmPosition
+}
+
+// checkPopulation decodes every slot into socket, channel and slot, builds
+// the per-socket channel map and returns the violated population rules:
+// DIMMs balanced across sockets, identical channels, slot 0 of a channel
+// filled first, every channel filled before a second DIMM per channel, and
+// no mixed speeds, capacities or module types.
+func (m *Memory) checkPopulation() []string {
+	msg := mixedDIMMs(m.PhysicalMemoryEntries)
+
+	rule, ok := cpuPlatform()
+	if ok {
+		m.Platform = rule.name
+	}
+
+	// Prefer the channel numbering EDAC reports over the platform table.
+	_, topo := m.edacPositions()
+	if topo.channels == 0 && rule.channels > 0 {
+		topo.channels = rule.channels
+		topo.perMC = rule.channels / max(rule.controllers, 1)
+	}
+
+	slots := m.slotPositions(topo)
+	if slots == nil {
+		return msg
+	}
+
+	channels := topo.channels
+	slotsPerChannel := 0
+	for _, s := range slots {
+		channels = max(channels, s.pos.channel+1)
+		slotsPerChannel = max(slotsPerChannel, s.pos.slot+1)
+	}
+
+	sockets := installedSockets()
+	bySocket := make(map[int][]slotPosition)
+	for _, s := range slots {
+		if sockets > 0 && s.pos.socket >= sockets {
+			continue
+		}
+		bySocket[s.pos.socket] = append(bySocket[s.pos.socket], s)
+	}
+
+	socketIDs := make([]int, 0, len(bySocket))
+	for id := range bySocket {
+		socketIDs = append(socketIDs, id)
+	}
+	sort.Ints(socketIDs)
+
+	m.ChannelMap = m.ChannelMap[:0]
+	var ref *SocketPopulation
+	for _, id := range socketIDs {
+		pop, issues := checkSocket(id, bySocket[id], channels, slotsPerChannel, rule.maxDPC)
+		m.ChannelMap = append(m.ChannelMap, pop)
+		msg = append(msg, issues...)
+
+		if ref == nil {
+			ref = pop
+		} else if pop.DIMMs != ref.DIMMs || pop.Capacity != ref.Capacity {
+			msg = append(msg, fmt.Sprintf("%s has %s DIMMs (%s) but %s has %s DIMMs (%s)",
+				strings.ToLower(ref.Name), ref.DIMMs, ref.Capacity, strings.ToLower(pop.Name), pop.DIMMs, pop.Capacity))
+		}
+	}
+
+	return msg
+}
+
+// slotPositions decodes all SMBIOS slots with the first layout that fully
+// locates them, or returns nil.
+func (m *Memory) slotPositions(topo edacTopology) []slotPosition {
+	vendor := systemVendor()
+	for _, layout := range dimmLayouts {
+		if !layout.appliesTo(vendor) {
+			continue
+		}
+
+		pos, ok := layout.positions(m.slots, topo)
+		if !ok {
+			continue
+		}
+
+		res := make([]slotPosition, 0, len(pos))
+		for i, p := range pos {
+			if p.socket < 0 || p.channel < 0 || p.slot < 0 {
+				res = nil
+				break
+			}
+			res = append(res, slotPosition{memorySlot: m.slots[i], pos: p})
+		}
+		if res != nil {
+			return res
+		}
+	}
+	return nil
+}
+
+// checkSocket builds the channel map of one socket and checks its channels.
+func checkSocket(id int, slots []slotPosition, channels, slotsPerChannel, maxDPC int) (*SocketPopulation, []string) {
+	var (
+		msg     []string
+		grid    = make([][]*memorySlot, channels)
+		dimms   int
+		totalMB int
+	)
+	for c := range grid {
+		grid[c] = make([]*memorySlot, slotsPerChannel)
+	}
+	for _, s := range slots {
+		grid[s.pos.channel][s.pos.slot] = s.memorySlot
+		if s.dimm != nil {
+			dimms++
+			if size, err := toBytes(s.dimm.Size); err == nil {
+				totalMB += size >> 20
+			}
+		}
+	}
+
+	name := fmt.Sprintf("Socket %d", id)
+	var (
+		cells     []string
+		counts    = make(map[int][]string)
+		populated int
+		emptyCh   int
+		multiDPC  bool
+	)
+	for c, row := range grid {
+		cell := make([]byte, 0, len(row))
+		n := 0
+		for i, slot := range row {
+			switch {
+			case slot == nil:
+				cell = append(cell, ' ')
+			case slot.dimm == nil:
+				cell = append(cell, '-')
+			default:
+				cell = append(cell, 'X')
+				n++
+				// Slot 0 of the channel must be filled before slot 1 and so on.
+				for _, prev := range row[:i] {
+					if prev != nil && prev.dimm == nil {
+						msg = append(msg, fmt.Sprintf("DIMM %s is populated while %s is empty", slot.deviceLocator, prev.deviceLocator))
+						break
+					}
+				}
+			}
+		}
+
+		// Channels without any slot on this board are not shown.
+		if strings.TrimSpace(string(cell)) == "" {
+			continue
+		}
+		cells = append(cells, fmt.Sprintf("%c[%s]", 'A'+c, cell))
+		counts[n] = append(counts[n], string(rune('A'+c)))
+		if n > 0 {
+			populated++
+		} else {
+			emptyCh++
+		}
+		if n > 1 {
+			multiDPC = true
+		}
+		if maxDPC > 0 && n > maxDPC {
+			msg = append(msg, fmt.Sprintf("%s channel %c has %d DIMMs, more than the %d supported", strings.ToLower(name), 'A'+c, n, maxDPC))
+		}
+	}
+
+	if dimms > 0 {
+		// Every populated channel should hold the same number of DIMMs.
+		delete(counts, 0)
+		if len(counts) > 1 {
+			parts := make([]string, 0, len(counts))
+			for n, chs := range counts {
+				parts = append(parts, fmt.Sprintf("%s: %d", strings.Join(chs, "/"), n))
+			}
+			sort.Strings(parts)
+			msg = append(msg, fmt.Sprintf("%s channels hold different DIMM counts (%s)", strings.ToLower(name), strings.Join(parts, ", ")))
+		}
+		if multiDPC && emptyCh > 0 {
+			msg = append(msg, fmt.Sprintf("%s has more than one DIMM per channel while %d channels are empty", strings.ToLower(name), emptyCh))
+		}
+	}
+
+	return &SocketPopulation{
+		Name:     name,
+		DIMMs:    strconv.Itoa(dimms),
+		Capacity: sizeMiB(totalMB),
+		Channels: fmt.Sprintf("%d/%d", populated, populated+emptyCh),
+		Map:      strings.Join(cells, " "),
+	}, msg
+}
+
+// mixedDIMMs reports mixed speeds, capacities and module types.
+func mixedDIMMs(dimms []*SmbiosMemoryEntry) []string {
+	var msg []string
+
+	check := func(what string, value func(d *SmbiosMemoryEntry) string) {
+		counts := make(map[string]int)
+		for _, d := range dimms {
+			if v := value(d); v != "" && v != "Unknown" {
+				counts[v]++
+			}
+		}
+		if len(counts) < 2 {
+			return
+		}
+		parts := make([]string, 0, len(counts))
+		for v, n := range counts {
+			parts = append(parts, fmt.Sprintf("%s x%d", v, n))
+		}
+		sort.Strings(parts)
+		msg = append(msg, fmt.Sprintf("mixed DIMM %s: %s", what, strings.Join(parts, ", ")))
+	}
+
+	check("speeds", func(d *SmbiosMemoryEntry) string { return d.Speed })
+	check("capacities", func(d *SmbiosMemoryEntry) string { return d.Size })
+	check("module types", func(d *SmbiosMemoryEntry) string {
+		if d.SPD != nil {
+			return d.SPD.ModuleType
+		}
+		return ""
+	})
+
+	return msg
+}
+
+// cpuPlatform looks up the population rule of the installed CPU generation.
+func cpuPlatform() (platformRule, bool) {
+	sig, err := cpu.ReadSignature()
+	if err != nil {
+		return platformRule{}, false
+	}
+
+	for _, r := range platformRules {
+		if r.vendor == sig.Vendor && r.family == sig.Family && sig.Model >= r.models[0] && sig.Model <= r.models[1] {
+			return r, true
+		}
+	}
+	return platformRule{}, false
+}
+
+// installedSockets counts the physical packages with online CPUs.
+func installedSockets() int {
+	paths, _ := filepath.Glob(filepath.Join(sysfsCPU, "cpu[0-9]*", "topology", "physical_package_id"))
+
+	ids := make(map[int]bool)
+	for _, p := range paths {
+		if id, err := utils.ReadSysfsInt(p); err == nil {
+			ids[id] = true
+		}
+	}
+	return len(ids)
+}
@@ -3,6 +3,7 @@ package memory
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -49,12 +50,15 @@ func (m *Memory) collectFromSMBIOS(ctx context.Context) error {
 	var totalSize int
 
 	for _, t := range memoryTables {
+		slot := &memorySlot{handle: t.Handle, deviceLocator: t.DeviceLocator, bankLocator: t.BankLocator}
+		m.slots = append(m.slots, slot)
+
 		speed := speedStr(t.Speed)
 		if speed == "Unknown" {
 			continue
 		}
 
-		m.PhysicalMemoryEntries = append(m.PhysicalMemoryEntries, &SmbiosMemoryEntry{
+		slot.dimm = &SmbiosMemoryEntry{
 			Size:              t.GetSizeString(),
 			SerialNumber:      t.SerialNumber,
 			Manufacturer:      t.Manufacturer,
@@ -71,7 +75,8 @@ func (m *Memory) collectFromSMBIOS(ctx context.Context) error {
 			ConfiguredSpeed:   speedStr(t.ConfiguredSpeed),
 			ConfiguredVoltage: voltageStr(t.ConfiguredVoltage),
 			Technology:        t.Technology.String(),
-		})
+		}
+		m.PhysicalMemoryEntries = append(m.PhysicalMemoryEntries, slot.dimm)
 
 		if size, err := toBytes(t.GetSizeString()); err == nil {
 			totalSize += size
@@ -79,10 +84,71 @@ func (m *Memory) collectFromSMBIOS(ctx context.Context) error {
 	}
 
 	m.PhysicalMemorySize = utils.KGMT(float64(totalSize), true)
+	m.physicalSize = int64(totalSize)
+
+	m.collectMemoryArrays()
 
 	return nil
 }
 
+// collectMemoryArrays reads the system memory arrays (Type 16) for the slot
+// count, maximum capacity and ECC mode, the array mappings (Type 19) for the
+// mapped memory size, and the device mappings (Type 20) to find populated
+// DIMMs that firmware left out of the address space. The types are optional,
+// so missing tables are not reported.
+func (m *Memory) collectMemoryArrays() {
+	arrays, _ := smbios.GetTypeData[*smbios.Type16PhysicalMemoryArray](16)
+
+	var (
+		slots    int
+		capacity uint64
+		ecc      []string
+		handles  = make(map[uint16]bool)
+	)
+	for _, a := range arrays {
+		if a.Use != smbios.MemoryArrayUseSystemMemory {
+			continue
+		}
+		handles[a.Handle] = true
+		slots += int(a.NumberOfMemoryDevices)
+		capacity += a.GetMaximumCapacity()
+		if e := a.ErrorCorrection.String(); !slices.Contains(ecc, e) {
+			ecc = append(ecc, e)
+		}
+	}
+
+	if slots > 0 {
+		m.Maxslots = strconv.Itoa(slots)
+	}
+	if capacity > 0 {
+		m.MaxCapacity = utils.KGMT(float64(capacity), true)
+	}
+	m.ECC = strings.Join(ecc, ", ")
+
+	ranges, _ := smbios.GetTypeData[*smbios.Type19MemoryArrayMappedAddress](19)
+	var mapped uint64
+	for _, r := range ranges {
+		if len(handles) == 0 || handles[r.MemoryArrayHandle] {
+			mapped += r.GetSize()
+		}
+	}
+	if mapped > 0 {
+		m.MappedMemorySize = utils.KGMT(float64(mapped), true)
+	}
+
+	devices, _ := smbios.GetTypeData[*smbios.Type20MemoryDeviceMappedAddress](20)
+	byHandle := make(map[uint16]*memorySlot, len(m.slots))
+	for _, slot := range m.slots {
+		byHandle[slot.handle] = slot
+	}
+	for _, d := range devices {
+		if slot, ok := byHandle[d.MemoryDeviceHandle]; ok && d.GetSize() > 0 {
+			slot.mapped = true
+			m.deviceMappings = true
+		}
+	}
+}
+
 func toBytes(s string) (int, error) {
 	parts := strings.Fields(s)
 	if len(parts) != 2 {
@@ -0,0 +1,189 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+const (
+	i2cDriversPath = "/sys/bus/i2c/drivers"
+	i2cDevicesPath = "/sys/bus/i2c/devices"
+
+	// SPD EEPROMs answer at 0x50-0x57; DDR4 thermal sensors (jc42) at the
+	// matching 0x18-0x1f.
+	spdAddrFirst = 0x50
+	spdAddrLast  = 0x57
+	jc42Offset   = 0x50 - 0x18
+
+	spdMappingSerial = "Serial Number"
+)
+
+// spdDrivers are the kernel EEPROM drivers that expose SPD contents. at24
+// also binds to non-SPD EEPROMs, which are skipped on decode.
+var spdDrivers = []string{"ee1004", "spd5118", "at24"}
+
+// collectSPD reads the SPD EEPROM of every DIMM through the kernel EEPROM
+// drivers and, when enabled with SetSPDRawI2C, through i2c-dev. Decoded SPDs
+// are attached to their SMBIOS DIMMs. Missing drivers are not an error.
+func (m *Memory) collectSPD(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var (
+		spds []*SPD
+		errs []error
+		seen = make(map[string]bool)
+	)
+
+	for _, driver := range spdDrivers {
+		devices, _ := filepath.Glob(filepath.Join(i2cDriversPath, driver, "*-00*"))
+		for _, dev := range devices {
+			bus, addr, ok := parseI2CDevice(filepath.Base(dev))
+			if !ok || addr < spdAddrFirst || addr > spdAddrLast {
+				continue
+			}
+
+			data, err := os.ReadFile(filepath.Join(dev, "eeprom"))
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			s, err := decodeSPD(data)
+			if err != nil {
+				if driver != "at24" || !errors.Is(err, errSPDType) {
+					errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(dev), err))
+				}
+				continue
+			}
+
+			s.Source, s.Address, s.bus, s.addr = driver, filepath.Base(dev), bus, addr
+			s.Temperature = spdTemperature(s)
+			seen[s.Address] = true
+			spds = append(spds, s)
+		}
+	}
+
+	if spdRawI2C {
+		raw, err := readRawSPDs(seen)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		spds = append(spds, raw...)
+	}
+
+	sort.Slice(spds, func(i, j int) bool {
+		if spds[i].bus != spds[j].bus {
+			return spds[i].bus < spds[j].bus
+		}
+		return spds[i].addr < spds[j].addr
+	})
+
+	m.attachSPD(spds)
+
+	return errors.Join(errs...)
+}
+
+// attachSPD attaches each SPD to the SMBIOS DIMM with the same serial number.
+// I2C addresses do not identify a slot, so SPDs without a unique match are
+// reported as unassigned rather than paired by order, which could hand one
+// module's serial to another. Blank or placeholder SMBIOS strings of matched
+// DIMMs are replaced with the SPD values.
+func (m *Memory) attachSPD(spds []*SPD) {
+	for _, s := range spds {
+		d := m.dimmBySerial(s.SerialNumber)
+		if d == nil {
+			m.UnassignedSPD = append(m.UnassignedSPD, s)
+			continue
+		}
+		s.Mapping = spdMappingSerial
+		d.setSPD(s)
+	}
+}
+
+// dimmBySerial returns the SMBIOS DIMM without SPD whose serial number ends
+// with serial; some vendors prefix the SPD serial with manufacturer bytes.
+func (m *Memory) dimmBySerial(serial string) *SmbiosMemoryEntry {
+	if serial == "" {
+		return nil
+	}
+
+	var found *SmbiosMemoryEntry
+	for _, d := range m.PhysicalMemoryEntries {
+		sn := strings.ToUpper(strings.TrimSpace(d.SerialNumber))
+		if d.SPD != nil || utils.IsPlaceholder(sn) || !strings.HasSuffix(sn, serial) {
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = d
+	}
+	return found
+}
+
+// setSPD attaches s to d and fills placeholder SMBIOS strings from it.
+func (d *SmbiosMemoryEntry) setSPD(s *SPD) {
+	d.SPD = s
+
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&d.Manufacturer, s.Manufacturer},
+		{&d.PartNumber, s.PartNumber},
+		{&d.SerialNumber, s.SerialNumber},
+		{&d.Rank, s.Ranks},
+	} {
+		if utils.IsPlaceholder(*f.dst) && f.src != "" {
+			*f.dst = f.src
+		}
+	}
+}
+
+// parseI2CDevice parses an I2C device name such as "0-0050".
+func parseI2CDevice(name string) (int, int, bool) {
+	busStr, addrStr, ok := strings.Cut(name, "-")
+	if !ok {
+		return 0, 0, false
+	}
+
+	bus, err := strconv.Atoi(busStr)
+	if err != nil {
+		return 0, 0, false
+	}
+	addr, err := strconv.ParseUint(addrStr, 16, 16)
+	if err != nil {
+		return 0, 0, false
+	}
+	return bus, int(addr), true
+}
+
+// spdTemperature reads the module temperature from the spd5118 hwmon device
+// of a DDR5 SPD hub or the jc42 thermal sensor next to a DDR4 SPD.
+func spdTemperature(s *SPD) string {
+	dev := filepath.Join(i2cDevicesPath, s.Address)
+	if s.MemoryType == "DDR4" {
+		dev = filepath.Join(i2cDevicesPath, fmt.Sprintf("%d-%04x", s.bus, s.addr-jc42Offset))
+	}
+
+	inputs, _ := filepath.Glob(filepath.Join(dev, "hwmon", "hwmon*", "temp1_input"))
+	if len(inputs) == 0 {
+		return ""
+	}
+
+	milli, err := utils.ReadSysfsInt(inputs[0])
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%.1f °C", float64(milli)/1000)
+}
@@ -0,0 +1,344 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SPD key byte 2: DRAM device type.
+const (
+	spdTypeDDR4 = 0x0c
+	spdTypeDDR5 = 0x12
+
+	spdSizeDDR4 = 512
+	spdSizeDDR5 = 1024
+)
+
+var errSPDType = errors.New("unsupported SPD DRAM type")
+
+// spdSpeeds are the standard data rates that decoded tCKmin values snap to.
+var spdSpeeds = []int{
+	1600, 1866, 2133, 2400, 2666, 2933, 3200,
+	3600, 4000, 4400, 4800, 5200, 5600, 6000, 6400, 6800, 7200, 7600, 8000, 8400, 8800,
+}
+
+var (
+	ddr4ModuleTypes = map[byte]string{
+		0x1: "RDIMM", 0x2: "UDIMM", 0x3: "SO-DIMM", 0x4: "LRDIMM",
+		0x5: "Mini-RDIMM", 0x6: "Mini-UDIMM", 0x8: "72b-SO-RDIMM", 0x9: "72b-SO-UDIMM",
+		0xc: "16b-SO-DIMM", 0xd: "32b-SO-DIMM",
+	}
+	ddr5ModuleTypes = map[byte]string{
+		0x1: "RDIMM", 0x2: "UDIMM", 0x3: "SO-DIMM", 0x4: "LRDIMM",
+		0x5: "CUDIMM", 0x6: "CSODIMM", 0x7: "MRDIMM", 0x8: "CAMM2",
+		0xa: "DDIMM", 0xb: "Solder Down",
+	}
+
+	// ddr4DieDensities is the per-die capacity in Mbit indexed by byte 4 bits 3:0.
+	ddr4DieDensities = []int{256, 512, 1024, 2048, 4096, 8192, 16384, 32768, 12288, 24576}
+	// ddr5DieDensities is the per-die capacity in Gbit indexed by byte 4 bits 4:0.
+	ddr5DieDensities = []int{0, 4, 8, 12, 16, 24, 32, 48, 64}
+	// ddr5DiesPerPackage is indexed by byte 4 bits 7:5.
+	ddr5DiesPerPackage = []int{1, 0, 2, 4, 8, 16}
+
+	ddr5PMICTypes = map[byte]string{0x0: "PMIC5000", 0x1: "PMIC5010", 0x2: "PMIC5100"}
+	ddr5TSTypes   = map[byte]string{0x0: "TS5111", 0x1: "TS5110"}
+)
+
+// decodeSPD decodes a DDR4 or DDR5 SPD image.
+func decodeSPD(b []byte) (*SPD, error) {
+	if len(b) < 3 {
+		return nil, fmt.Errorf("SPD too short: %d bytes", len(b))
+	}
+
+	switch b[2] {
+	case spdTypeDDR4:
+		if len(b) < spdSizeDDR4 {
+			return nil, fmt.Errorf("DDR4 SPD too short: %d bytes", len(b))
+		}
+		return decodeDDR4(b), nil
+	case spdTypeDDR5:
+		if len(b) < spdSizeDDR5 {
+			return nil, fmt.Errorf("DDR5 SPD too short: %d bytes", len(b))
+		}
+		return decodeDDR5(b), nil
+	}
+
+	return nil, fmt.Errorf("%w 0x%02X", errSPDType, b[2])
+}
+
+// decodeDDR4 decodes a DDR4 SPD (JESD21-C Annex L).
+func decodeDDR4(b []byte) *SPD {
+	s := &SPD{
+		MemoryType: "DDR4",
+		ModuleType: ddr4ModuleTypes[b[3]&0x0f],
+	}
+
+	// SDRAM density and addressing.
+	var densityMb int
+	if i := int(b[4] & 0x0f); i < len(ddr4DieDensities) {
+		densityMb = ddr4DieDensities[i]
+	}
+	bankGroups := []int{0, 2, 4, 0}[b[4]>>6]
+	banks := []int{4, 8, 0, 0}[(b[4]>>4)&0x03]
+
+	// Package: monolithic, or multi-die (DDP/QDP or 3DS single load stack).
+	dies := int((b[6]>>4)&0x07) + 1
+	packageType := "Monolithic"
+	if b[6]&0x80 != 0 {
+		packageType = fmt.Sprintf("%d-die", dies)
+		if b[6]&0x03 == 0x02 {
+			packageType = fmt.Sprintf("3DS %dH", dies)
+		}
+	}
+	s.DieType = ddrDieType(densityMb, bankGroups, banks, packageType)
+
+	ranks := int((b[12]>>3)&0x07) + 1
+	width := 4 << (b[12] & 0x07)
+	busWidth := 8 << (b[13] & 0x07)
+	s.Ranks = strconv.Itoa(ranks)
+	s.Organization = fmt.Sprintf("%dRx%d", ranks, width)
+
+	// Logical ranks of 3DS modules include every die in the stack.
+	logicalRanks := ranks
+	if b[6]&0x03 == 0x02 {
+		logicalRanks *= dies
+	}
+	s.Size = sizeMiB(densityMb / 8 * busWidth / width * logicalRanks)
+
+	// Timings: medium timebase 125 ps plus a signed fine correction in ps.
+	mtb := func(coarse int, fine byte) int { return coarse*125 + int(int8(fine)) }
+	tCK := mtb(int(b[18]), b[125])
+	tAA := mtb(int(b[24]), b[123])
+	tRCD := mtb(int(b[25]), b[122])
+	tRP := mtb(int(b[26]), b[121])
+	tRAS := (int(b[27]&0x0f)<<8 | int(b[28])) * 125
+
+	// CAS latencies: bit n of bytes 20..23 is CL 7+n, or 23+n when bit 31 is set.
+	var cls []int
+	mask := uint32(b[20]) | uint32(b[21])<<8 | uint32(b[22])<<16 | uint32(b[23])<<24
+	base := 7
+	if mask&(1<<31) != 0 {
+		base = 23
+	}
+	for i := range 30 {
+		if mask&(1<<i) != 0 {
+			cls = append(cls, base+i)
+		}
+	}
+	s.fillTimings(tCK, tAA, tRCD, tRP, tRAS, cls)
+
+	if b[14]&0x80 != 0 {
+		s.ThermalSensor = "Present"
+	} else {
+		s.ThermalSensor = "Not Present"
+	}
+
+	s.fillManufacturing(b[320:], 20)
+	s.DRAMManufacturer = jedecManufacturer(b[350], b[351])
+
+	s.Checksum = spdChecksum(b[:126], uint16(b[126])|uint16(b[127])<<8)
+
+	return s
+}
+
+// decodeDDR5 decodes a DDR5 SPD (JESD400-5).
+func decodeDDR5(b []byte) *SPD {
+	s := &SPD{
+		MemoryType: "DDR5",
+		ModuleType: ddr5ModuleTypes[b[3]&0x0f],
+	}
+
+	var densityGb, dies int
+	if i := int(b[4] & 0x1f); i < len(ddr5DieDensities) {
+		densityGb = ddr5DieDensities[i]
+	}
+	if i := int(b[4] >> 5); i < len(ddr5DiesPerPackage) {
+		dies = ddr5DiesPerPackage[i]
+	}
+	bankGroups := 1 << (b[7] >> 5)
+	banks := 1 << (b[7] & 0x07)
+
+	packageType := "Monolithic"
+	if dies > 1 {
+		packageType = fmt.Sprintf("3DS %dH", dies)
+	}
+	s.DieType = ddrDieType(densityGb*1024, bankGroups, banks, packageType)
+
+	ranks := int((b[234]>>3)&0x07) + 1
+	width := 4 << (b[6] >> 5)
+	channels := int((b[235]>>5)&0x03) + 1
+	busWidth := 8 << (b[235] & 0x07)
+	s.Ranks = strconv.Itoa(ranks)
+	s.Organization = fmt.Sprintf("%dRx%d", ranks, width)
+	s.Size = sizeMiB(densityGb * 1024 / 8 * channels * busWidth / width * max(dies, 1) * ranks)
+
+	// Timings are stored in ps.
+	ps := func(i int) int { return int(b[i]) | int(b[i+1])<<8 }
+
+	// CAS latencies: bit n of bytes 24..28 is CL 20+2n.
+	var cls []int
+	for i := range 40 {
+		if b[24+i/8]&(1<<(i%8)) != 0 {
+			cls = append(cls, 20+2*i)
+		}
+	}
+	s.fillTimings(ps(20), ps(30), ps(32), ps(34), ps(36), cls)
+
+	// Module components: PMICs and thermal sensors, each a 2-byte
+	// manufacturer ID followed by the device type at +2. Bit 7 of each
+	// device type byte reports whether the device is installed.
+	var pmics []string
+	for _, off := range []int{196, 200, 204} {
+		if b[off+2]&0x80 == 0 {
+			continue
+		}
+		pmics = append(pmics, ddr5Component(b[off], b[off+1], b[off+2], ddr5PMICTypes))
+	}
+	s.PMIC = strings.Join(pmics, ", ")
+
+	if b[210]&0xc0 != 0 {
+		s.ThermalSensor = ddr5Component(b[208], b[209], b[210], ddr5TSTypes)
+		if b[210]&0xc0 == 0xc0 {
+			s.ThermalSensor += " x2"
+		}
+	} else {
+		s.ThermalSensor = "Not Present"
+	}
+
+	s.fillManufacturing(b[512:], 30)
+	s.DRAMManufacturer = jedecManufacturer(b[552], b[553])
+
+	s.Checksum = spdChecksum(b[:510], uint16(b[510])|uint16(b[511])<<8)
+
+	return s
+}
+
+// fillManufacturing decodes the module manufacturing block shared by DDR4
+// (byte 320) and DDR5 (byte 512): manufacturer ID, location, BCD year and
+// week, 4-byte serial number and an ASCII part number of partLen bytes.
+func (s *SPD) fillManufacturing(m []byte, partLen int) {
+	s.Manufacturer = jedecManufacturer(m[0], m[1])
+
+	year, week := bcd(m[3]), bcd(m[4])
+	if year >= 0 && week > 0 && week <= 53 {
+		s.ManufactureDate = fmt.Sprintf("%d-W%02d", 2000+year, week)
+	}
+
+	if serial := fmt.Sprintf("%02X%02X%02X%02X", m[5], m[6], m[7], m[8]); serial != "00000000" && serial != "FFFFFFFF" {
+		s.SerialNumber = serial
+	}
+
+	s.PartNumber = strings.TrimFunc(string(m[9:9+partLen]), func(r rune) bool {
+		return r <= ' ' || r > '~'
+	})
+}
+
+// fillTimings derives the maximum data rate and the primary timings in clock
+// cycles at that rate from values in ps.
+func (s *SPD) fillTimings(tCK, tAA, tRCD, tRP, tRAS int, cls []int) {
+	if tCK <= 0 {
+		return
+	}
+
+	speed := snapSpeed(2000000 / tCK)
+	s.MaxSpeed = fmt.Sprintf("%d MT/s", speed)
+
+	if len(cls) > 0 {
+		parts := make([]string, 0, len(cls))
+		for _, cl := range cls {
+			parts = append(parts, strconv.Itoa(cl))
+		}
+		s.CASLatencies = strings.Join(parts, " ")
+	}
+
+	// The CAS latency at the maximum rate is the lowest supported value that
+	// satisfies tAAmin.
+	cl := nCK(tAA, tCK)
+	for _, c := range cls {
+		if c >= cl {
+			cl = c
+			break
+		}
+	}
+	s.Timings = fmt.Sprintf("%d-%d-%d-%d", cl, nCK(tRCD, tCK), nCK(tRP, tCK), nCK(tRAS, tCK))
+}
+
+// nCK converts a minimum time to clock cycles using the JEDEC rounding
+// algorithm, which tolerates a 2.5% guard band.
+func nCK(t, tCK int) int {
+	return (t*1000/tCK + 974) / 1000
+}
+
+// snapSpeed rounds a computed data rate to the nearest standard rate within 1%.
+func snapSpeed(mts int) int {
+	for _, s := range spdSpeeds {
+		if d := s - mts; d*100 <= s && d*100 >= -s {
+			return s
+		}
+	}
+	return mts
+}
+
+// ddrDieType formats the die density, bank organization and package.
+func ddrDieType(densityMb, bankGroups, banks int, packageType string) string {
+	density := fmt.Sprintf("%d Mb", densityMb)
+	if densityMb >= 1024 {
+		density = fmt.Sprintf("%d Gb", densityMb/1024)
+	}
+	if bankGroups > 0 {
+		return fmt.Sprintf("%s, %d BG x %d banks, %s", density, bankGroups, banks, packageType)
+	}
+	return fmt.Sprintf("%s, %d banks, %s", density, banks, packageType)
+}
+
+// ddr5Component formats a module component as "<type> (<manufacturer>)".
+func ddr5Component(cont, code, devType byte, types map[byte]string) string {
+	name, ok := types[devType&0x0f]
+	if !ok {
+		name = fmt.Sprintf("Type 0x%02X", devType&0x0f)
+	}
+	if vendor := jedecManufacturer(cont, code); vendor != "" {
+		return fmt.Sprintf("%s (%s)", name, vendor)
+	}
+	return name
+}
+
+// sizeMiB formats a capacity given in MiB.
+func sizeMiB(mib int) string {
+	if mib >= 1024 {
+		return fmt.Sprintf("%d GB", mib/1024)
+	}
+	return fmt.Sprintf("%d MB", mib)
+}
+
+// spdChecksum verifies the CRC-16 (polynomial 0x1021) of an SPD block.
+func spdChecksum(b []byte, want uint16) string {
+	var crc uint16
+	for _, c := range b {
+		crc ^= uint16(c) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	if crc != want {
+		return fmt.Sprintf("Mismatch (stored 0x%04X, computed 0x%04X)", want, crc)
+	}
+	return "OK"
+}
+
+// bcd decodes a binary-coded decimal byte, returning -1 if it is invalid.
+func bcd(b byte) int {
+	hi, lo := b>>4, b&0x0f
+	if hi > 9 || lo > 9 {
+		return -1
+	}
+	return int(hi)*10 + int(lo)
+}
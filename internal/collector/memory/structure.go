@@ -1,8 +1,17 @@
 package memory
 
+import (
+	"time"
+
+	"github.com/zenithax-cc/baize/internal/collector/pci"
+)
+
 type Memory struct {
 	PhysicalMemorySize    string               `json:"physical_memory_size,omitempty" name:"Physical Memory" output:"both" color:"defaultGreen"`
 	Maxslots              string               `json:"max_slots,omitempty" name:"Slot Max" output:"both"`
+	MaxCapacity           string               `json:"max_capacity,omitempty" name:"Max Capacity" output:"both"`
+	ECC                   string               `json:"ecc,omitempty" name:"ECC" output:"both"`
+	MappedMemorySize      string               `json:"mapped_memory_size,omitempty" name:"Mapped Memory" output:"detail"`
 	UsedSlots             string               `json:"used_slots,omitempty" name:"Slot Used" output:"both"`
 	MemTotal              string               `json:"memory_total,omitempty" name:"System Memory" output:"both"`
 	MemFree               string               `json:"memory_free,omitempty" name:"Memory Free" output:"both"`
@@ -29,6 +38,142 @@ type Memory struct {
 	EdacMemorySize        string               `json:"edac_memory_size,omitempty"`
 	PhysicalMemoryEntries []*SmbiosMemoryEntry `json:"physical_memory_entries,omitempty" name:"memories" output:"detail"`
 	EdacMemoryEntries     []*EdacMemoryEntry   `json:"edac_memory_entries,omitempty"`
+	// UnattributedErrors counts rasdaemon memory errors that matched no single DIMM.
+	UnattributedErrors string `json:"unattributed_errors,omitempty"`
+	// DIMMMapping is how EDAC DIMMs were matched to SMBIOS DIMMs; see correlateDIMMs.
+	DIMMMapping string `json:"dimm_mapping,omitempty" name:"DIMM Mapping" output:"detail"`
+	// UnassignedSPD holds decoded SPDs whose serial number matched no SMBIOS DIMM.
+	UnassignedSPD []*SPD `json:"unassigned_spd,omitempty" name:"Unassigned SPD" output:"detail"`
+	// Platform is the CPU generation the population rules were taken from.
+	Platform string `json:"platform,omitempty" name:"Platform" output:"detail"`
+	// ChannelMap shows which slots of each socket's channels are populated.
+	ChannelMap []*SocketPopulation `json:"channel_map,omitempty" name:"Channel Map" output:"detail"`
+	// PersistentMemorySize is the total size of the NVDIMM / PMem regions and
+	// CXLMemorySize the capacity of the CXL memory expanders. Volatile CXL
+	// memory is onlined as system RAM and included in MemTotal.
+	PersistentMemorySize string        `json:"persistent_memory_size,omitempty" name:"Persistent Memory" output:"both"`
+	CXLMemorySize        string        `json:"cxl_memory_size,omitempty" name:"CXL Memory" output:"both"`
+	PMemDevices          []*PMemDevice `json:"pmem_devices,omitempty" name:"PMem Devices" output:"detail"`
+	PMemRegions          []*PMemRegion `json:"pmem_regions,omitempty" name:"PMem Regions" output:"detail"`
+	CXLDevices           []*CXLDevice  `json:"cxl_devices,omitempty" name:"CXL Devices" output:"detail"`
+	CXLRegions           []*CXLRegion  `json:"cxl_regions,omitempty" name:"CXL Regions" output:"detail"`
+
+	// slots holds every SMBIOS Type 17 device, populated or not, and
+	// deviceMappings whether the firmware provides Type 20 structures.
+	slots          []*memorySlot
+	deviceMappings bool
+	// memTotal and physicalSize are MemTotal and the SMBIOS DIMM total in bytes.
+	memTotal     int64
+	physicalSize int64
+}
+
+// PMemDevice is an NVDIMM or Optane PMem module on the libnvdimm bus.
+type PMemDevice struct {
+	Name         string `json:"name,omitempty"`
+	ID           string `json:"id,omitempty" name:"ID" output:"detail"`
+	Vendor       string `json:"vendor,omitempty"`
+	SerialNumber string `json:"serial_number,omitempty" name:"SN" output:"detail"`
+	Handle       string `json:"handle,omitempty"`
+	PhysicalID   string `json:"physical_id,omitempty" name:"Physical ID" output:"detail"`
+	Firmware     string `json:"firmware,omitempty" name:"Firmware" output:"detail"`
+	State        string `json:"state,omitempty" name:"State" output:"detail"`
+	Security     string `json:"security,omitempty" name:"Security" output:"detail"`
+	// Health is "OK" or the ACPI NFIT flags set for the module, e.g.
+	// "save_fail, restore_fail".
+	Health string `json:"health,omitempty" name:"Health" output:"detail" color:"Diagnose"`
+}
+
+// PMemRegion is an interleave set of PMem modules.
+type PMemRegion struct {
+	Name              string `json:"name,omitempty"`
+	Type              string `json:"type,omitempty" name:"Type" output:"detail"`
+	Size              string `json:"size,omitempty" name:"Size" output:"detail"`
+	AvailableSize     string `json:"available_size,omitempty" name:"Available Size" output:"detail"`
+	NUMANode          string `json:"numa_node,omitempty" name:"NUMA Node" output:"detail"`
+	PersistenceDomain string `json:"persistence_domain,omitempty" name:"Persistence Domain" output:"detail"`
+	// Mappings lists the member modules in interleave order.
+	Mappings   string           `json:"mappings,omitempty" name:"Mappings" output:"detail"`
+	BadBlocks  string           `json:"bad_blocks,omitempty" name:"Bad Blocks" output:"detail"`
+	Namespaces []*PMemNamespace `json:"namespaces,omitempty" name:"Namespaces" output:"detail"`
+
+	size int64
+}
+
+// PMemNamespace is a configured namespace of a PMem region.
+type PMemNamespace struct {
+	Name string `json:"name,omitempty"`
+	// Mode is the ndctl mode: "fsdax", "devdax", "sector" or "raw".
+	Mode     string `json:"mode,omitempty" name:"Mode" output:"detail"`
+	Size     string `json:"size,omitempty" name:"Size" output:"detail"`
+	Device   string `json:"device,omitempty" name:"Device" output:"detail"`
+	UUID     string `json:"uuid,omitempty"`
+	NUMANode string `json:"numa_node,omitempty"`
+}
+
+// CXLDevice is a CXL Type 3 memory device (memory expander).
+type CXLDevice struct {
+	Name         string `json:"name,omitempty"`
+	SerialNumber string `json:"serial_number,omitempty" name:"SN" output:"detail"`
+	Firmware     string `json:"firmware,omitempty" name:"Firmware" output:"detail"`
+	RAMSize      string `json:"ram_size,omitempty" name:"RAM Size" output:"detail"`
+	PMemSize     string `json:"pmem_size,omitempty" name:"PMem Size" output:"detail"`
+	NUMANode     string `json:"numa_node,omitempty" name:"NUMA Node" output:"detail"`
+	Security     string `json:"security,omitempty"`
+	// Decoders lists the committed endpoint decoders with mode, size and region.
+	Decoders string `json:"decoders,omitempty" name:"Decoders" output:"detail"`
+	// Health and the fields below come from `cxl list -H` when available.
+	Health         string `json:"health,omitempty" name:"Health" output:"detail" color:"Diagnose"`
+	LifeUsed       string `json:"life_used,omitempty" name:"Life Used" output:"detail"`
+	Temperature    string `json:"temperature,omitempty" name:"Temperature" output:"detail"`
+	DirtyShutdowns string `json:"dirty_shutdowns,omitempty"`
+	VolatileErrors string `json:"volatile_errors,omitempty"`
+	PMemErrors     string `json:"pmem_errors,omitempty"`
+	// PoisonRecords is the number of media error (poison) records.
+	PoisonRecords string   `json:"poison_records,omitempty" name:"Poison Records" output:"detail"`
+	PCIe          *pci.PCI `json:"pcie,omitzero"`
+
+	ramSize  int64
+	pmemSize int64
+	poison   int
+	issues   []string
+}
+
+// CXLRegion is a host physical address range interleaved across CXL devices.
+type CXLRegion struct {
+	Name string `json:"name,omitempty"`
+	// Mode is "ram" (onlined as system RAM) or "pmem".
+	Mode                  string `json:"mode,omitempty" name:"Mode" output:"detail"`
+	Size                  string `json:"size,omitempty" name:"Size" output:"detail"`
+	Resource              string `json:"resource,omitempty" name:"Resource" output:"detail"`
+	InterleaveWays        string `json:"interleave_ways,omitempty" name:"Interleave Ways" output:"detail"`
+	InterleaveGranularity string `json:"interleave_granularity,omitempty"`
+	State                 string `json:"state,omitempty" name:"State" output:"detail"`
+	Targets               string `json:"targets,omitempty" name:"Targets" output:"detail"`
+	UUID                  string `json:"uuid,omitempty"`
+
+	size int64
+}
+
+// memorySlot is one SMBIOS memory device; dimm is nil for an empty slot.
+// mapped reports whether a Type 20 structure maps the device.
+type memorySlot struct {
+	handle        uint16
+	deviceLocator string
+	bankLocator   string
+	dimm          *SmbiosMemoryEntry
+	mapped        bool
+}
+
+// SocketPopulation summarizes the DIMM population of one socket.
+type SocketPopulation struct {
+	Name     string `json:"name,omitempty"`
+	DIMMs    string `json:"dimms,omitempty" name:"DIMMs" output:"detail"`
+	Capacity string `json:"capacity,omitempty" name:"Capacity" output:"detail"`
+	// Channels is populated over available channels, e.g. "8/8".
+	Channels string `json:"channels,omitempty" name:"Channels" output:"detail"`
+	// Map lists each channel's slots, slot 0 first: "X" populated, "-" empty,
+	// e.g. "A[X-] B[X-] C[--]".
+	Map string `json:"map,omitempty" name:"Map" output:"detail"`
 }
 
 type SmbiosMemoryEntry struct {
@@ -49,6 +194,54 @@ type SmbiosMemoryEntry struct {
 	ConfiguredSpeed   string `json:"configured_speed,omitempty"`
 	ConfiguredVoltage string `json:"configured_voltage,omitempty"`
 	Technology        string `json:"technology,omitempty"`
+	// EdacLabel and the error fields are filled from the EDAC DIMMs (ranks)
+	// mapped to this module, so errors can be reported by locator and serial.
+	EdacLabel           string `json:"edac_label,omitempty" name:"EDAC Label" output:"detail"`
+	CorrectableErrors   string `json:"correctable_errors,omitempty" name:"Correctable Errors" output:"detail"`
+	UncorrectableErrors string `json:"uncorrectable_errors,omitempty" name:"Uncorrectable Errors" output:"detail"`
+	HistoryErrors       string `json:"history_errors,omitempty" name:"History Errors" output:"detail"`
+	ErrorTrend          string `json:"error_trend,omitempty" name:"Error Trend" output:"detail"`
+	// SPD is decoded from the module EEPROM, which is authoritative where
+	// SMBIOS strings are blank or placeholders.
+	SPD *SPD `json:"spd,omitempty" name:"SPD"`
+
+	// edac lists the mapped EDAC DIMMs and locator the unique slot name.
+	edac    []*EdacMemoryEntry
+	locator string
+}
+
+// SPD is the decoded Serial Presence Detect EEPROM of a DDR4 or DDR5 module.
+type SPD struct {
+	MemoryType       string `json:"memory_type,omitempty" name:"Memory Type" output:"detail"`
+	ModuleType       string `json:"module_type,omitempty" name:"Module Type" output:"detail"`
+	Size             string `json:"size,omitempty" name:"Size" output:"detail"`
+	Manufacturer     string `json:"manufacturer,omitempty" name:"Manufacturer" output:"detail"`
+	PartNumber       string `json:"part_number,omitempty" name:"Part Number" output:"detail"`
+	SerialNumber     string `json:"serial_number,omitempty" name:"SN" output:"detail"`
+	ManufactureDate  string `json:"manufacture_date,omitempty" name:"Manufacture Date" output:"detail"`
+	DRAMManufacturer string `json:"dram_manufacturer,omitempty" name:"DRAM Manufacturer" output:"detail"`
+	// DieType is the die density, bank organization and package, e.g.
+	// "16 Gb, 8 BG x 4 banks, Monolithic".
+	DieType      string `json:"die_type,omitempty" name:"Die Type" output:"detail"`
+	Ranks        string `json:"ranks,omitempty"`
+	Organization string `json:"organization,omitempty" name:"Organization" output:"detail"`
+	MaxSpeed     string `json:"max_speed,omitempty" name:"Max Speed" output:"detail"`
+	CASLatencies string `json:"cas_latencies,omitempty"`
+	// Timings are CL-tRCD-tRP-tRAS in clock cycles at MaxSpeed.
+	Timings       string `json:"timings,omitempty" name:"Timings" output:"detail"`
+	ThermalSensor string `json:"thermal_sensor,omitempty" name:"Thermal Sensor" output:"detail"`
+	Temperature   string `json:"temperature,omitempty" name:"Temperature" output:"detail"`
+	PMIC          string `json:"pmic,omitempty" name:"PMIC" output:"detail"`
+	Checksum      string `json:"checksum,omitempty" name:"Checksum" output:"detail" color:"Diagnose"`
+	// Source is the driver the EEPROM was read through and Address its I2C
+	// device, e.g. "ee1004" and "0-0050".
+	Source  string `json:"source,omitempty"`
+	Address string `json:"address,omitempty" name:"I2C Address" output:"detail"`
+	// Mapping is how the SPD was matched to its SMBIOS DIMM.
+	Mapping string `json:"mapping,omitempty"`
+
+	bus  int
+	addr int
 }
 
 type EdacMemoryEntry struct {
@@ -65,4 +258,51 @@ type EdacMemoryEntry struct {
 	MemoryControllerID  string `json:"memory_controller_id,omitempty"`
 	ChannelID           string `json:"channel_id,omitempty"`
 	DIMMID              string `json:"dimm_id,omitempty"`
+	// Label is the EDAC dimm_label, e.g. "CPU_SrcID#0_MC#0_Chan#1_DIMM#0".
+	Label string `json:"label,omitempty"`
+	// ErrorHistory holds the time-stamped errors recorded by rasdaemon,
+	// including those from before the last reboot.
+	ErrorHistory *ErrorHistory `json:"error_history,omitempty"`
+
+	// mc is the EDAC memory controller index and layers the location indexes.
+	mc     int
+	layers []int
+	// smbios is the physical DIMM this entry was mapped to, if any.
+	smbios *SmbiosMemoryEntry
+}
+
+// ErrorHistory summarizes the persistent error history of one DIMM.
+type ErrorHistory struct {
+	// CorrectedErrors and UncorrectedErrors are totals over the whole history.
+	CorrectedErrors   string `json:"corrected_errors,omitempty"`
+	UncorrectedErrors string `json:"uncorrected_errors,omitempty"`
+	// BeforeBoot is the number of errors logged before the current boot.
+	BeforeBoot string `json:"before_boot,omitempty"`
+	// Last24Hours is the number of errors logged in the last 24 hours.
+	Last24Hours string `json:"last_24_hours,omitempty"`
+	FirstSeen   string `json:"first_seen,omitempty"`
+	LastSeen    string `json:"last_seen,omitempty"`
+	// Trend is "Burst", "Recent" or "Historical"; see classifyTrend.
+	Trend string `json:"trend,omitempty"`
+	// Events holds the most recent events, oldest first.
+	Events []*ErrorEvent `json:"events,omitempty"`
+
+	corrected   int
+	uncorrected int
+	last24h     int
+}
+
+// ErrorEvent is one memory error event recorded by rasdaemon.
+type ErrorEvent struct {
+	Time string `json:"time,omitempty"`
+	// Source is the rasdaemon table: "mc_event" (EDAC) or "extlog_event" (firmware).
+	Source  string `json:"source,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Count   string `json:"count,omitempty"`
+	Address string `json:"address,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	time        time.Time
+	count       int
+	uncorrected bool
 }
@@ -76,15 +76,25 @@ func (n *Network) BriefPrintln() {
 func (n *Network) printInterfaces(outputType string) {
 	// Build brief view structs for each NetInterface.
 	type NICBrief struct {
-		Name   string `name:"Interface" output:"both" color:"DefaultGreen"`
-		MAC    string `name:"MAC Address" output:"both"`
-		Driver string `name:"Driver" output:"both"`
-		Speed  string `name:"Speed" output:"both"`
-		Duplex string `name:"Duplex" output:"detail"`
-		MTU    string `name:"MTU" output:"detail"`
-		Link   string `name:"Link Detected" output:"both" color:"trueGreen"`
-		Status string `name:"Status" output:"both"`
-		IPv4   string `name:"IPv4" output:"both" color:"DefaultGreen"`
+		Name           string `name:"Interface" output:"both" color:"DefaultGreen"`
+		Slot           string `name:"Slot" output:"both"`
+		MAC            string `name:"MAC Address" output:"both"`
+		Driver         string `name:"Driver" output:"both"`
+		VFs            string `name:"SR-IOV VFs" output:"both"`
+		Speed          string `name:"Speed" output:"both"`
+		Duplex         string `name:"Duplex" output:"detail"`
+		MTU            string `name:"MTU" output:"detail"`
+		Link           string `name:"Link Detected" output:"both" color:"trueGreen"`
+		Status         string `name:"Status" output:"both"`
+		IPv4           string `name:"IPv4" output:"both" color:"DefaultGreen"`
+		Diagnose       string `name:"Diagnose" output:"both" color:"Diagnose"`
+		DiagnoseDetail string `name:"Diagnose Detail" output:"both"`
+	}
+
+	// Physical interface behind each NIC, keyed by device name.
+	phys := make(map[string]*PhyInterface, len(n.PhyInterfaces))
+	for i := range n.PhyInterfaces {
+		phys[n.PhyInterfaces[i].DeviceName] = &n.PhyInterfaces[i]
 	}
 
 	briefs := make([]*NICBrief, 0, len(n.NetInterfaces))
@@ -100,6 +110,19 @@ func (n *Network) printInterfaces(outputType string) {
 			Link:   ni.LinkDetected,
 			Status: ni.Status,
 		}
+		if phy, ok := phys[ni.DeviceName]; ok {
+			if phy.PCI.Slot != nil {
+				b.Slot = phy.PCI.Slot.Designation
+			}
+			if sriov := phy.PCI.SRIOV; sriov != nil {
+				b.VFs = sriov.EnabledVFs + "/" + sriov.TotalVFs
+				if sriov.AvailableVFs != "" {
+					b.VFs += " (" + sriov.AvailableVFs + " available)"
+				}
+			}
+			b.Diagnose = phy.Diagnose
+			b.DiagnoseDetail = phy.DiagnoseDetail
+		}
 		if len(ni.IPv4) > 0 {
 			b.IPv4 = ni.IPv4[0].Address
 			if ni.IPv4[0].PrefixLen != "" {
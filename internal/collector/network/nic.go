@@ -25,7 +25,8 @@ func collectNic() ([]PhyInterface, error) {
 	}
 
 	results := make([]PhyInterface, len(nics))
-	errsCh := make(chan error, len(nics))
+	// Each NIC may report PCI, LLDP and VF configuration errors.
+	errsCh := make(chan error, 3*len(nics))
 
 	var wg sync.WaitGroup
 	for i, nic := range nics {
@@ -76,15 +77,25 @@ func collectNic() ([]PhyInterface, error) {
 				}()
 
 				innerWg.Wait()
+
+				// VF MAC/VLAN/trust settings are held by the physical function.
+				if pcie.SRIOV != nil && len(pcie.SRIOV.VFs) > 0 {
+					if err := collectVFConfig(devName, pcie.SRIOV); err != nil {
+						errsCh <- err
+					}
+				}
 			}
 
-			results[idx] = PhyInterface{
+			phy := PhyInterface{
 				DeviceName: devName,
 				PCI:        pcie,
 				LLDP:       lldpData,
 				RingBuffer: ringBuf,
 				Channel:    channel,
 			}
+			// Surface a degraded link or AER errors of the NIC.
+			phy.PCI.MergeDiagnose(&phy.Diagnose, &phy.DiagnoseDetail)
+			results[idx] = phy
 		}(i, nic)
 	}
 
@@ -0,0 +1,89 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/zenithax-cc/baize/internal/collector/pci"
+	"github.com/zenithax-cc/baize/pkg/execute"
+)
+
+const ipCmd = "/usr/sbin/ip"
+
+// ipLinkVF is one entry of the vfinfo_list that "ip -j link show" prints for
+// a physical function. Older iproute2 prints vlan/qos directly instead of
+// vlan_list.
+type ipLinkVF struct {
+	VF       int    `json:"vf"`
+	Address  string `json:"address"`
+	VlanList []struct {
+		Vlan int `json:"vlan"`
+		Qos  int `json:"qos"`
+	} `json:"vlan_list"`
+	Vlan      *int   `json:"vlan"`
+	Qos       *int   `json:"qos"`
+	SpoofChk  *bool  `json:"spoofchk"`
+	Trust     *bool  `json:"trust"`
+	LinkState string `json:"link_state"`
+}
+
+// collectVFConfig fills the MAC, VLAN, spoof checking, trust and link state
+// the physical function holds for each of its virtual functions.
+func collectVFConfig(pf string, sriov *pci.SRIOV) error {
+	output := execute.Command(ipCmd, "-j", "link", "show", "dev", pf)
+	if output.AsError() != nil {
+		return output.Err
+	}
+
+	var links []struct {
+		VFInfoList []ipLinkVF `json:"vfinfo_list"`
+	}
+	if err := json.Unmarshal(output.Stdout, &links); err != nil {
+		return fmt.Errorf("parsing ip link output of %s: %w", pf, err)
+	}
+	if len(links) == 0 {
+		return nil
+	}
+
+	byIndex := make(map[string]*pci.VirtualFunction, len(sriov.VFs))
+	for _, vf := range sriov.VFs {
+		byIndex[vf.Index] = vf
+	}
+
+	for _, info := range links[0].VFInfoList {
+		vf, ok := byIndex[strconv.Itoa(info.VF)]
+		if !ok {
+			continue
+		}
+
+		vf.MAC = info.Address
+		switch {
+		case len(info.VlanList) > 0:
+			vf.VLAN = strconv.Itoa(info.VlanList[0].Vlan)
+			vf.QoS = strconv.Itoa(info.VlanList[0].Qos)
+		case info.Vlan != nil:
+			vf.VLAN = strconv.Itoa(*info.Vlan)
+			if info.Qos != nil {
+				vf.QoS = strconv.Itoa(*info.Qos)
+			}
+		}
+		vf.SpoofCheck = onOff(info.SpoofChk)
+		vf.Trust = onOff(info.Trust)
+		vf.LinkState = info.LinkState
+	}
+
+	return nil
+}
+
+// onOff renders an optional ip-link flag the way "ip link" prints it.
+func onOff(b *bool) string {
+	switch {
+	case b == nil:
+		return ""
+	case *b:
+		return "on"
+	default:
+		return "off"
+	}
+}
@@ -45,6 +45,9 @@ type PhyInterface struct {
 	Channel    Channel    `json:"channel,omitzero"`
 	LLDP       LLDP       `json:"lldp,omitzero"`
 	PCI        pci.PCI    `json:"pci,omitzero"`
+
+	Diagnose       string `json:"diagnose,omitzero"`
+	DiagnoseDetail string `json:"diagnose_detail,omitzero"`
 }
 
 // RingBuffer represents NIC ring buffer configuration.
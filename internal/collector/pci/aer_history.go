@@ -0,0 +1,76 @@
+package pci
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zenithax-cc/baize/internal/collector/rasdaemon"
+)
+
+// rasdaemon AER history lazy initialization, shared by all devices
+var (
+	aerEventsByDev map[string][]*rasdaemon.AEREvent
+	aerEventsOnce  sync.Once
+)
+
+// getAEREvents returns the rasdaemon AER events grouped by PCI address
+func getAEREvents() map[string][]*rasdaemon.AEREvent {
+	aerEventsOnce.Do(func() {
+		// A missing rasdaemon database simply means there is no history
+		events, err := rasdaemon.AEREvents()
+		if err != nil {
+			return
+		}
+
+		aerEventsByDev = make(map[string][]*rasdaemon.AEREvent)
+		for _, ev := range events {
+			addr := strings.ToLower(strings.TrimSpace(ev.DevName))
+			aerEventsByDev[addr] = append(aerEventsByDev[addr], ev)
+		}
+	})
+	return aerEventsByDev
+}
+
+// parseAERHistory attaches the AER events recorded by rasdaemon, including
+// those from before the last reboot
+func (p *PCI) parseAERHistory() {
+	events := getAEREvents()[strings.ToLower(p.PCIAddr)]
+	if len(events) == 0 {
+		return
+	}
+
+	var corrected, nonFatal, fatal int
+	times := make([]time.Time, 0, len(events))
+	for _, ev := range events {
+		times = append(times, ev.Time)
+		switch {
+		case strings.Contains(ev.ErrType, "Fatal") && !strings.Contains(ev.ErrType, "Non-Fatal"):
+			fatal++
+		case strings.HasPrefix(ev.ErrType, "Uncorrected"):
+			nonFatal++
+		default:
+			corrected++
+		}
+	}
+
+	first, last := events[0], events[len(events)-1]
+	p.AERHistory = &AERHistory{
+		Corrected: strconv.Itoa(corrected),
+		NonFatal:  strconv.Itoa(nonFatal),
+		Fatal:     strconv.Itoa(fatal),
+		FirstSeen: formatAERTime(first),
+		LastSeen:  formatAERTime(last),
+		LastDay:   strconv.Itoa(countLastDay(times)),
+		LastError: strings.TrimSpace(last.ErrType + ": " + last.ErrMsg),
+	}
+}
+
+// formatAERTime formats the event time in local time
+func formatAERTime(ev *rasdaemon.AEREvent) string {
+	if ev.Time.IsZero() {
+		return ev.Timestamp
+	}
+	return ev.Time.Local().Format(time.DateTime)
+}
@@ -0,0 +1,171 @@
+package pci
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DeviceClasses represents the type of PCI device class.
+type DeviceClasses int
+
+const (
+	ClassRAID    DeviceClasses = 1
+	ClassNvme    DeviceClasses = 2
+	ClassNetwork DeviceClasses = 3
+	ClassDisplay DeviceClasses = 4
+	ClassAccel   DeviceClasses = 5
+)
+
+func (classes DeviceClasses) String() string {
+	switch classes {
+	case ClassRAID:
+		return "RAID Controller"
+	case ClassNvme:
+		return "NVMe Controller"
+	case ClassNetwork:
+		return "Network Controller"
+	case ClassDisplay:
+		return "Display Controller"
+	case ClassAccel:
+		return "Processing Accelerator"
+	default:
+		return fmt.Sprintf("Unknown(%d)", classes)
+	}
+}
+
+// ClassFilter matches the class code of a device. SubClass and ProgIF are
+// only compared when their Match flags are set, so a filter can select a
+// whole class, a subclass or a single programming interface.
+type ClassFilter struct {
+	Class         uint8
+	SubClass      uint8
+	MatchSubClass bool
+	ProgIF        uint8
+	MatchProgIF   bool
+}
+
+// ByClass matches every device of the base class
+func ByClass(class uint8) ClassFilter {
+	return ClassFilter{Class: class}
+}
+
+// BySubClass matches every device of the base class and subclass
+func BySubClass(class, subClass uint8) ClassFilter {
+	return ClassFilter{Class: class, SubClass: subClass, MatchSubClass: true}
+}
+
+// ByProgIF matches devices with exactly this class, subclass and programming interface
+func ByProgIF(class, subClass, progIF uint8) ClassFilter {
+	return ClassFilter{Class: class, SubClass: subClass, MatchSubClass: true, ProgIF: progIF, MatchProgIF: true}
+}
+
+// Match reports whether a 24-bit class code, as found in sysfs, passes the filter
+func (f ClassFilter) Match(code uint32) bool {
+	if uint8(code>>16) != f.Class {
+		return false
+	}
+	if f.MatchSubClass && uint8(code>>8) != f.SubClass {
+		return false
+	}
+	if f.MatchProgIF && uint8(code) != f.ProgIF {
+		return false
+	}
+	return true
+}
+
+// deviceFilters maps device types to PCI class code filters.
+// Reference: https://pci-ids.ucw.cz/read/PD
+var deviceFilters = map[DeviceClasses][]ClassFilter{
+	ClassRAID: {BySubClass(0x01, 0x04), BySubClass(0x01, 0x07)}, // RAID & SAS
+	ClassNvme: {BySubClass(0x01, 0x08)},                         // NVMe
+	ClassNetwork: { // Ethernet, Token Ring, FDDI, ATM, ISDN, WorldFip, PICMG, InfiniBand, Fabric
+		BySubClass(0x02, 0x00), BySubClass(0x02, 0x01), BySubClass(0x02, 0x02),
+		BySubClass(0x02, 0x03), BySubClass(0x02, 0x04), BySubClass(0x02, 0x05),
+		BySubClass(0x02, 0x06), BySubClass(0x02, 0x07), BySubClass(0x02, 0x08),
+	},
+	ClassDisplay: {BySubClass(0x03, 0x00), BySubClass(0x03, 0x01), BySubClass(0x03, 0x02)}, // VGA,XGA,3D
+	ClassAccel:   {ByClass(0x12), BySubClass(0x0b, 0x40)},                                  // Processing accelerators, co-processors
+}
+
+// FindByClass returns the addresses of the devices in sysfs whose class code
+// passes any of the filters, in address order
+func FindByClass(filters ...ClassFilter) ([]string, error) {
+	entries, err := os.ReadDir(sysfsPci)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", sysfsPci, err)
+	}
+
+	var res []string
+	for _, entry := range entries {
+		code, err := readClassCode(filepath.Join(sysfsPci, entry.Name()))
+		if err != nil {
+			continue
+		}
+		for _, f := range filters {
+			if f.Match(code) {
+				res = append(res, entry.Name())
+				break
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// readClassCode parses the class file of a device, e.g. "0x010802"
+func readClassCode(devicePath string) (uint32, error) {
+	content, err := readFileContent(filepath.Join(devicePath, "class"))
+	if err != nil {
+		return 0, err
+	}
+
+	var code uint32
+	if _, err := fmt.Sscanf(strings.TrimPrefix(content, "0x"), "%06x", &code); err != nil {
+		return 0, fmt.Errorf("parsing class %q: %w", content, err)
+	}
+	return code, nil
+}
+
+// getPCIBus returns the PCI bus addresses of devices matching the given type.
+// Virtual functions are left out; they are reported under their physical
+// function.
+func getPCIBus(class DeviceClasses) ([]string, error) {
+	filters, ok := deviceFilters[class]
+	if !ok {
+		return nil, fmt.Errorf("unsupported device type: %s", class)
+	}
+
+	addrs, err := FindByClass(filters...)
+	if err != nil {
+		return nil, err
+	}
+	return physicalFunctions(addrs), nil
+}
+
+// GetSerialRAIDPCIBus returns the PCI bus addresses of RAID and SAS controllers.
+func GetSerialRAIDPCIBus() ([]string, error) {
+	return getPCIBus(ClassRAID)
+}
+
+// GetNVMePCIBus returns the PCI bus addresses of NVMe controllers.
+func GetNVMePCIBus() ([]string, error) {
+	return getPCIBus(ClassNvme)
+}
+
+// GetNetworkPCIBus returns the PCI bus addresses of network controllers.
+func GetNetworkPCIBus() ([]string, error) {
+	return getPCIBus(ClassNetwork)
+}
+
+// GetDisplayPCIBus returns the PCI bus addresses of display controllers.
+func GetDisplayPCIBus() ([]string, error) {
+	return getPCIBus(ClassDisplay)
+}
+
+// GetAcceleratorPCIBus returns the PCI bus addresses of processing
+// accelerators and co-processors, e.g. AI, crypto and compression offload.
+func GetAcceleratorPCIBus() ([]string, error) {
+	return getPCIBus(ClassAccel)
+}
@@ -0,0 +1,73 @@
+//go:build ignore
+
+// gen_pci_ids compresses a pci.ids database into pci.ids.gz, the snapshot
+// embedded in the binary. The source is $PCI_IDS, or the hwdata copy when
+// unset. go generate expands variables itself, so the default cannot be
+// left to the shell.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const defaultSource = "/usr/share/hwdata/pci.ids"
+
+func main() {
+	src := os.Getenv("PCI_IDS")
+	if src == "" {
+		src = defaultSource
+	}
+
+	if err := generate(src, "pci.ids.gz"); err != nil {
+		fmt.Fprintln(os.Stderr, "gen_pci_ids:", err)
+		os.Exit(1)
+	}
+}
+
+func generate(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	version := ""
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := sc.Text()
+		if !strings.HasPrefix(line, "#") {
+			continue
+		}
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line[1:]), "Version:"); ok {
+			version = strings.TrimSpace(v)
+			break
+		}
+	}
+	if version == "" {
+		return fmt.Errorf("%s: no Version header, not a pci.ids file", src)
+	}
+
+	var buf bytes.Buffer
+	// No name or modification time in the header, so the output only
+	// changes with the database.
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(dst, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("pci.ids.gz: version %s from %s\n", version, src)
+	return nil
+}
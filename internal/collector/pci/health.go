@@ -0,0 +1,285 @@
+package pci
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Diagnose results
+const (
+	diagnoseHealthy   = "Healthy"
+	diagnoseUnhealthy = "Unhealthy"
+)
+
+// Corrected errors above this count, since boot or within a day of rasdaemon
+// history, point at a marginal link rather than the occasional glitch
+const aerCorrectedBurst = 100
+
+// Display devices drop their link speed when idle to save power
+const classDisplay = "03"
+
+// parseAER reads the AER counters the kernel keeps since boot. Root ports
+// also report the totals of the errors they received from below.
+func (p *PCI) parseAER(devicePath string) error {
+	aer := &AER{}
+	found := false
+
+	devFiles := []struct {
+		name   string
+		total  string
+		target *string
+	}{
+		{"aer_dev_correctable", "TOTAL_ERR_COR", &aer.Corrected},
+		{"aer_dev_nonfatal", "TOTAL_ERR_NONFATAL", &aer.NonFatal},
+		{"aer_dev_fatal", "TOTAL_ERR_FATAL", &aer.Fatal},
+	}
+
+	var details []string
+	for _, f := range devFiles {
+		counters, err := readAERCounters(filepath.Join(devicePath, f.name))
+		if err != nil {
+			continue
+		}
+		found = true
+		*f.target = strconv.Itoa(counters[f.total])
+		details = append(details, counters.nonZero(f.total)...)
+	}
+	aer.Errors = strings.Join(details, ", ")
+
+	rootFiles := []struct {
+		name   string
+		target *string
+	}{
+		{"aer_rootport_total_err_cor", &aer.RootPortCorrected},
+		{"aer_rootport_total_err_nonfatal", &aer.RootPortNonFatal},
+		{"aer_rootport_total_err_fatal", &aer.RootPortFatal},
+	}
+	for _, f := range rootFiles {
+		if content, err := readFileContent(filepath.Join(devicePath, f.name)); err == nil {
+			found = true
+			*f.target = content
+		}
+	}
+
+	if !found {
+		// AER is not supported by the device or the kernel
+		return nil
+	}
+	p.AER = aer
+	return nil
+}
+
+// aerCounters holds the per-error-type counters of an aer_dev_* file
+type aerCounters map[string]int
+
+// readAERCounters parses lines such as "BadTLP 3" and "TOTAL_ERR_COR 3"
+func readAERCounters(path string) (aerCounters, error) {
+	content, err := readFileContent(path)
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make(aerCounters)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if n, err := strconv.Atoi(fields[1]); err == nil {
+			counters[fields[0]] = n
+		}
+	}
+	return counters, scanner.Err()
+}
+
+// nonZero returns the error types that occurred, e.g. "BadTLP=3", in file order
+func (c aerCounters) nonZero(total string) []string {
+	var res []string
+	for _, name := range aerErrorNames {
+		if n := c[name]; n > 0 && name != total {
+			res = append(res, fmt.Sprintf("%s=%d", name, n))
+		}
+	}
+	return res
+}
+
+// aerErrorNames lists the counters of aer_dev_* in kernel order
+var aerErrorNames = []string{
+	// correctable
+	"RxErr", "BadTLP", "BadDLLP", "Rollover", "Timeout", "NonFatalErr", "CorrIntErr", "HeaderOF",
+	// uncorrectable
+	"Undefined", "DLP", "SDES", "TLP", "FCP", "CmpltTO", "CmpltAbrt", "UnxCmplt", "RxOF",
+	"MalfTLP", "ECRC", "UnsupReq", "ACSViol", "UncorrIntErr", "BlockedTLP", "AtomicOpBlocked", "TLPBlockedErr",
+	"PoisonTLPBlocked", "DMWrReqBlocked", "IDECheck", "MisIDETLP", "PCRC_CHECK", "TLPXlatBlocked",
+}
+
+// parseUpstream records the link capability of the port the device hangs
+// off, so a card in a slower or narrower port is not reported as degraded
+func (p *PCI) parseUpstream(devicePath string) {
+	addrs := upstreamAddrs(devicePath)
+	if len(addrs) < 2 {
+		return
+	}
+
+	upstream := filepath.Join(sysfsPci, addrs[1])
+	p.Link.UpstreamPort = addrs[1]
+	if content, err := readFileContent(filepath.Join(upstream, "max_link_speed")); err == nil {
+		p.Link.UpstreamMaxSpeed = content
+	}
+	if content, err := readFileContent(filepath.Join(upstream, "max_link_width")); err == nil {
+		p.Link.UpstreamMaxWidth = content
+	}
+
+	// Link errors are often logged by the receiving port rather than the card
+	if counters, err := readAERCounters(filepath.Join(upstream, "aer_dev_correctable")); err == nil {
+		p.Link.UpstreamCorrected = strconv.Itoa(counters["TOTAL_ERR_COR"])
+	}
+}
+
+// diagnose flags a link trained below what both ends support and AER
+// errors on the device or its upstream port
+func (p *PCI) diagnose() {
+	var details []string
+
+	if msg := linkDegradation(p.Link, p.ClassID == classDisplay); msg != "" {
+		details = append(details, msg)
+	}
+
+	if aer := p.AER; aer != nil {
+		before := len(details)
+		if n := atoi(aer.Fatal); n > 0 {
+			details = append(details, fmt.Sprintf("%d fatal AER errors since boot", n))
+		}
+		if n := atoi(aer.NonFatal); n > 0 {
+			details = append(details, fmt.Sprintf("%d non-fatal AER errors since boot", n))
+		}
+		if n := atoi(aer.Corrected); n >= aerCorrectedBurst {
+			details = append(details, fmt.Sprintf("%d corrected AER errors since boot", n))
+		}
+		if aer.Errors != "" && len(details) > before {
+			details = append(details, "AER "+aer.Errors)
+		}
+	}
+
+	if n := atoi(p.Link.UpstreamCorrected); n >= aerCorrectedBurst {
+		details = append(details, fmt.Sprintf("%d corrected AER errors on upstream port %s since boot", n, p.Link.UpstreamPort))
+	}
+
+	if h := p.AERHistory; h != nil && atoi(h.LastDay) >= aerCorrectedBurst {
+		details = append(details, fmt.Sprintf("%s AER errors in rasdaemon over the last 24h", h.LastDay))
+	}
+
+	if len(details) == 0 {
+		p.Diagnose = diagnoseHealthy
+		return
+	}
+	p.Diagnose = diagnoseUnhealthy
+	p.DiagnoseDetail = strings.Join(details, "; ")
+}
+
+// linkDegradation describes a link trained below the capability of the
+// device, its upstream port and the electrical width of its slot, e.g.
+// "link degraded: Gen3 x4, capable Gen4 x16". Speed is ignored when the
+// device is expected to downshift at idle.
+func linkDegradation(link PCILink, ignoreSpeed bool) string {
+	currSpeed, currWidth := parseLinkSpeed(link.CurrSpeed), atoi(link.CurrWidth)
+	if currSpeed == 0 || currWidth == 0 {
+		// Link down or not a PCIe device
+		return ""
+	}
+
+	maxSpeed, maxWidth := parseLinkSpeed(link.MaxSpeed), atoi(link.MaxWidth)
+	if s := parseLinkSpeed(link.UpstreamMaxSpeed); s > 0 && (maxSpeed == 0 || s < maxSpeed) {
+		maxSpeed = s
+	}
+	for _, w := range []int{atoi(link.UpstreamMaxWidth), atoi(link.SlotWidth)} {
+		if w > 0 && (maxWidth == 0 || w < maxWidth) {
+			maxWidth = w
+		}
+	}
+
+	slow := !ignoreSpeed && maxSpeed > 0 && currSpeed < maxSpeed
+	narrow := maxWidth > 0 && currWidth < maxWidth
+	if !slow && !narrow {
+		return ""
+	}
+
+	return fmt.Sprintf("link degraded: %s x%d, capable %s x%d",
+		pcieGen(currSpeed), currWidth, pcieGen(maxSpeed), maxWidth)
+}
+
+// parseLinkSpeed returns the transfer rate in GT/s from strings such as
+// "16.0 GT/s PCIe" or "2.5 GT/s", or 0 when unknown
+func parseLinkSpeed(s string) float64 {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0
+	}
+	v, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// pcieGen names the PCIe generation of a transfer rate
+func pcieGen(gts float64) string {
+	gens := []struct {
+		rate float64
+		name string
+	}{
+		{2.5, "Gen1"}, {5, "Gen2"}, {8, "Gen3"}, {16, "Gen4"}, {32, "Gen5"}, {64, "Gen6"},
+	}
+	for _, g := range gens {
+		if gts == g.rate {
+			return g.name
+		}
+	}
+	return strconv.FormatFloat(gts, 'f', -1, 64) + " GT/s"
+}
+
+// countLastDay counts the events of the last 24 hours
+func countLastDay(times []time.Time) int {
+	since := time.Now().Add(-24 * time.Hour)
+	n := 0
+	for _, t := range times {
+		if t.After(since) {
+			n++
+		}
+	}
+	return n
+}
+
+// atoi returns 0 for empty or malformed counters
+func atoi(s string) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(s))
+	return n
+}
+
+// MergeDiagnose folds the link and AER diagnosis of the device into the
+// diagnosis of the component built on it, e.g. a RAID controller or a NIC
+func (p *PCI) MergeDiagnose(diagnose, detail *string) {
+	if p == nil || p.Diagnose == "" {
+		return
+	}
+
+	if p.Diagnose == diagnoseHealthy {
+		if *diagnose == "" {
+			*diagnose = diagnoseHealthy
+		}
+		return
+	}
+
+	*diagnose = diagnoseUnhealthy
+	msg := "PCIe " + p.PCIAddr + ": " + p.DiagnoseDetail
+	if *detail == "" {
+		*detail = msg
+	} else {
+		*detail += "; " + msg
+	}
+}
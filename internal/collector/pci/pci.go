@@ -134,6 +134,26 @@ func (p *PCI) Collect() error {
 		collectionErrors = append(collectionErrors, fmt.Sprintf("other: %v", err))
 	}
 
+	// 5. Parse AER counters (non-fatal)
+	if err := p.parseAER(devicePath); err != nil {
+		collectionErrors = append(collectionErrors, fmt.Sprintf("aer: %v", err))
+	}
+
+	// 6. Attach rasdaemon AER history (optional)
+	p.parseAERHistory()
+
+	// 7. Locate the physical slot from SMBIOS (optional)
+	p.parseSlot(devicePath)
+
+	// 8. Check the link against the upstream port and the error counters
+	p.parseUpstream(devicePath)
+	p.diagnose()
+
+	// 9. List the SR-IOV virtual functions (non-fatal)
+	if err := p.parseSRIOV(devicePath); err != nil {
+		collectionErrors = append(collectionErrors, fmt.Sprintf("sriov: %v", err))
+	}
+
 	// Non-fatal errors are silently ignored as missing info is normal
 	_ = collectionErrors
 
@@ -186,6 +206,8 @@ func (p *PCI) resolveNames() error {
 	p.Device = ids.GetDeviceName(vendorDeviceID)
 	p.SubVendor = ids.GetVendorName(p.SubVendorID)
 	p.SubDevice = ids.GetSubsystemName(vendorDeviceID, subVendorDeviceID)
+	p.Class = ids.GetClassName(p.ClassID)
+	p.SubClass = ids.GetSubClassName(p.ClassID, p.SubClassID)
 
 	return nil
 }
@@ -12,8 +12,6 @@ import (
 	"strings"
 	"sync"
 	"time"
-
-	"github.com/zenithax-cc/baize/pkg/utils"
 )
 
 // Type Definitions
@@ -150,13 +148,7 @@ var (
 // Returns error if initialization fails.
 func NewPCIIDs() (*PCIIDs, error) {
 	instanceMu.Do(func() {
-		file, err := getPCIIDsFile()
-		if err != nil {
-			initErr = fmt.Errorf("locating pci.ids: %w", err)
-			return
-		}
-
-		content, err := getPCIIDsContent(file)
+		content, err := activePCIIDsSource().open()
 		if err != nil {
 			initErr = fmt.Errorf("reading pci.ids: %w", err)
 			return
@@ -184,23 +176,10 @@ func MustNewPCIIDs() *PCIIDs {
 	return pci
 }
 
-// File Location and Download
-
-// getPCIIDsFile finds the pci.ids file in standard paths or downloads it.
-// Returns the path to the file or an error if not found/downloadable.
-func getPCIIDsFile() (string, error) {
-	// Try standard system paths first - most efficient option
-	for _, file := range pciidsPath {
-		if utils.FileExists(file) {
-			return file, nil
-		}
-	}
-
-	// Fall back to downloading from upstream
-	return downloadPCIIDs()
-}
+// File Download
 
-// downloadPCIIDs fetches pci.ids from the upstream URL.
+// downloadPCIIDs fetches pci.ids from the upstream URL into a temporary file.
+// Only DownloadPCIIDs calls it, at the user's explicit request.
 // Implements security best practices:
 // - Request timeout to prevent hanging
 // - Response size limit to prevent memory exhaustion
@@ -564,3 +543,15 @@ func (p *PCIIDs) GetClassName(id string) string {
 	}
 	return "Unknown"
 }
+
+// GetSubClassName returns the subclass name or "Unknown" if not found.
+func (p *PCIIDs) GetSubClassName(classID, subClassID string) string {
+	if class := p.FindClassByID(classID); class != nil {
+		for _, sub := range class.SubClass {
+			if strings.EqualFold(sub.ID, subClassID) {
+				return sub.Name
+			}
+		}
+	}
+	return "Unknown"
+}
@@ -0,0 +1,212 @@
+package pci
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+//go:generate go run gen_pci_ids.go
+
+// embeddedPCIIDs is the compressed pci.ids snapshot built into the binary,
+// so names resolve on hosts without a system copy and without network.
+//
+//go:embed pci.ids.gz
+var embeddedPCIIDs []byte
+
+// EmbeddedPCIIDs is the Path of the snapshot built into the binary.
+const EmbeddedPCIIDs = "embedded"
+
+// pciidsCachePath is where "baize pciids update" stores a refreshed database.
+var pciidsCachePath = "/var/cache/baize/pci.ids.gz"
+
+// PCIIDsSource describes a pci.ids database baize can load.
+type PCIIDsSource struct {
+	Path    string // file path, or EmbeddedPCIIDs
+	Version string // "Version:" header, e.g. "2025.06.01"; empty if missing
+	Active  bool   // the database NewPCIIDs loads
+}
+
+// open returns a reader of the uncompressed database.
+func (s *PCIIDsSource) open() (io.ReadCloser, error) {
+	if s.Path != EmbeddedPCIIDs {
+		return getPCIIDsContent(s.Path)
+	}
+
+	gzReader, err := gzip.NewReader(bytes.NewReader(embeddedPCIIDs))
+	if err != nil {
+		return nil, fmt.Errorf("creating gzip reader for embedded pci.ids: %w", err)
+	}
+	return gzReader, nil
+}
+
+// readVersion returns the "Version:" line of the comment header.
+func (s *PCIIDsSource) readVersion() (string, error) {
+	r, err := s.open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "#") {
+			// End of the header
+			break
+		}
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line[1:]), "Version:"); ok {
+			return strings.TrimSpace(v), nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+// PCIIDsSources lists the system copies, the update cache and the embedded
+// snapshot, marking the one NewPCIIDs loads: the newest by version, and on
+// a tie the first in this order.
+func PCIIDsSources() []*PCIIDsSource {
+	var res []*PCIIDsSource
+	for _, path := range append(slices.Clone(pciidsPath), pciidsCachePath) {
+		if !utils.FileExists(path) {
+			continue
+		}
+		s := &PCIIDsSource{Path: path}
+		s.Version, _ = s.readVersion()
+		res = append(res, s)
+	}
+
+	embedded := &PCIIDsSource{Path: EmbeddedPCIIDs}
+	embedded.Version, _ = embedded.readVersion()
+	res = append(res, embedded)
+
+	active := res[0]
+	for _, s := range res[1:] {
+		if s.Version > active.Version {
+			active = s
+		}
+	}
+	active.Active = true
+
+	return res
+}
+
+// activePCIIDsSource returns the database NewPCIIDs loads.
+func activePCIIDsSource() *PCIIDsSource {
+	for _, s := range PCIIDsSources() {
+		if s.Active {
+			return s
+		}
+	}
+	return &PCIIDsSource{Path: EmbeddedPCIIDs}
+}
+
+// UpdatePCIIDs validates a pci.ids file, plain or gzip compressed, and
+// stores it compressed in the cache, where it takes priority over older
+// databases. It never touches the network.
+func UpdatePCIIDs(from string) (*PCIIDsSource, error) {
+	src := &PCIIDsSource{Path: from}
+	version, err := src.readVersion()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", from, err)
+	}
+	if version == "" {
+		return nil, fmt.Errorf("%s: no Version header, not a pci.ids file", from)
+	}
+
+	content, err := src.open()
+	if err != nil {
+		return nil, err
+	}
+	ids, err := parsePCIIDsContent(content)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", from, err)
+	}
+	if len(ids.Vendor) == 0 || len(ids.Class) == 0 {
+		return nil, fmt.Errorf("%s: no vendors or classes, not a pci.ids file", from)
+	}
+
+	if err := writePCIIDsCache(src); err != nil {
+		return nil, err
+	}
+
+	return &PCIIDsSource{Path: pciidsCachePath, Version: version}, nil
+}
+
+// writePCIIDsCache compresses the source into the cache through a
+// temporary file, so a failed update leaves the previous cache intact.
+func writePCIIDsCache(src *PCIIDsSource) (err error) {
+	dir := filepath.Dir(pciidsCachePath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+
+	tmpFile, err := os.CreateTemp(dir, "pci.ids.*")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer func() {
+		if err != nil {
+			tmpFile.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	r, err := src.open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	gzWriter, err := gzip.NewWriterLevel(tmpFile, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(gzWriter, r); err != nil {
+		return fmt.Errorf("compressing %s: %w", src.Path, err)
+	}
+	if err := gzWriter.Close(); err != nil {
+		return fmt.Errorf("compressing %s: %w", src.Path, err)
+	}
+	if err := tmpFile.Chmod(0o644); err != nil {
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, pciidsCachePath); err != nil {
+		return fmt.Errorf("replacing %s: %w", pciidsCachePath, err)
+	}
+	return nil
+}
+
+// DownloadPCIIDs fetches the upstream pci.ids and stores it in the cache.
+// This is the only network access and only runs when asked for.
+func DownloadPCIIDs() (*PCIIDsSource, error) {
+	tmpPath, err := downloadPCIIDs()
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmpPath)
+
+	src, err := UpdatePCIIDs(tmpPath)
+	if err != nil {
+		return nil, errors.Join(ErrDownloadFailed, err)
+	}
+	return src, nil
+}
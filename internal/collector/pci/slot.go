@@ -0,0 +1,218 @@
+package pci
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/zenithax-cc/baize/internal/collector/smbios"
+)
+
+// slotEntry is a physical slot or onboard device from SMBIOS with its PCI address
+type slotEntry struct {
+	slot    Slot
+	addr    string
+	lanes   int // electrical width of the slot
+	onboard bool
+}
+
+// SMBIOS slot lazy initialization, shared by all devices
+var (
+	slotEntries []*slotEntry
+	slotsOnce   sync.Once
+)
+
+// getSlotEntries returns the SMBIOS Type 9 slots followed by the Type 41
+// onboard devices that carry a PCI address
+func getSlotEntries() []*slotEntry {
+	slotsOnce.Do(func() {
+		// Missing tables simply mean devices cannot be placed
+		slots, _ := smbios.GetTypeData[*smbios.Type9SystemSlots](smbios.SystemSlots)
+		for _, s := range slots {
+			e := &slotEntry{
+				slot: Slot{
+					Designation: s.SlotDesignation,
+					Type:        s.SlotType.String(),
+					Width:       s.SlotDataBusWidth.String(),
+					Length:      s.SlotLength.String(),
+					Usage:       s.CurrentUsage.String(),
+					BusAddress:  s.GetPCIAddress(),
+				},
+				addr:  s.GetPCIAddress(),
+				lanes: s.SlotDataBusWidth.Lanes(),
+			}
+			if s.SlotPhysicalWidth != 0 {
+				e.slot.PhysicalWidth = s.SlotPhysicalWidth.String()
+			}
+			slotEntries = append(slotEntries, e)
+		}
+
+		onboard, _ := smbios.GetTypeData[*smbios.Type41OnboardDevicesExtended](smbios.OnBoardDevicesExtendedInformation)
+		for _, d := range onboard {
+			if !d.HasPCIAddress() {
+				continue
+			}
+			slotEntries = append(slotEntries, &slotEntry{
+				slot: Slot{
+					Designation: d.ReferenceDesignation,
+					Type:        "Onboard " + d.DeviceType.String(),
+					BusAddress:  d.GetPCIAddress(),
+				},
+				addr:    d.GetPCIAddress(),
+				onboard: true,
+			})
+		}
+	})
+	return slotEntries
+}
+
+// upstreamAddrs returns the address of the device followed by those of the
+// bridges above it, nearest first, taken from its sysfs path
+func upstreamAddrs(devicePath string) []string {
+	real, err := filepath.EvalSymlinks(devicePath)
+	if err != nil {
+		return []string{filepath.Base(devicePath)}
+	}
+
+	var addrs []string
+	for _, part := range strings.Split(real, string(filepath.Separator)) {
+		if pciAddrRegex.MatchString(part) {
+			addrs = append(addrs, strings.ToLower(part))
+		}
+	}
+	slices.Reverse(addrs)
+	return addrs
+}
+
+// sameDevice reports whether two addresses differ only in the function
+func sameDevice(a, b string) bool {
+	ia, ib := strings.LastIndexByte(a, '.'), strings.LastIndexByte(b, '.')
+	return ia > 0 && ia == ib && a[:ia] == b[:ib]
+}
+
+// findSlot returns the slot holding the device and the depth it matched at:
+// 0 for the device itself, 1 for the port directly above it and so on.
+// Firmware records either the device itself, any function of it, or the port
+// the slot hangs off, so the device address is matched ignoring the function
+// and the bridges above it exactly; the nearest match wins.
+func findSlot(devicePath string) (*slotEntry, int) {
+	entries := getSlotEntries()
+	if len(entries) == 0 {
+		return nil, -1
+	}
+
+	for i, addr := range upstreamAddrs(devicePath) {
+		for _, e := range entries {
+			if e.addr == "" {
+				continue
+			}
+			if e.addr == addr || i == 0 && sameDevice(e.addr, addr) {
+				return e, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+// parseSlot attaches the physical slot or onboard position from SMBIOS and
+// flags cards that are wider than the slot's wiring
+func (p *PCI) parseSlot(devicePath string) {
+	e, depth := findSlot(devicePath)
+	if e == nil {
+		return
+	}
+
+	slot := e.slot
+	slot.Diagnose = slotWidthDiagnose(e.lanes, p.Link)
+	p.Slot = &slot
+
+	// The slot wiring bounds the link only when the card sits directly in
+	// it, not when the slot holds a switch further up
+	if e.lanes > 0 && depth <= 1 {
+		p.Link.SlotWidth = strconv.Itoa(e.lanes)
+	}
+}
+
+// slotWidthDiagnose describes a card that can use more lanes than the slot
+// is wired for, e.g. a x16 card in a x8-wired slot
+func slotWidthDiagnose(slotLanes int, link PCILink) string {
+	maxWidth, err := strconv.Atoi(link.MaxWidth)
+	if err != nil || slotLanes == 0 || maxWidth <= slotLanes {
+		return ""
+	}
+
+	msg := fmt.Sprintf("x%d card in x%d slot", maxWidth, slotLanes)
+	if link.CurrWidth != "" {
+		msg += ", link x" + link.CurrWidth
+	}
+	return msg
+}
+
+// Slots returns the physical slots from SMBIOS Type 9 with the devices that
+// sit in them, so a card can be found by its slot label. Slots decoded from an
+// SMBIOS dump are returned without devices, as they belong to another host.
+func Slots() ([]*Slot, error) {
+	var res []*Slot
+	bySlot := make(map[*slotEntry]*Slot)
+	for _, e := range getSlotEntries() {
+		if e.onboard {
+			continue
+		}
+		slot := e.slot
+		bySlot[e] = &slot
+		res = append(res, &slot)
+	}
+	if len(res) == 0 {
+		return nil, nil
+	}
+	if smbios.FromDump() {
+		return res, nil
+	}
+
+	devices, err := os.ReadDir(sysfsPci)
+	if err != nil {
+		return res, err
+	}
+
+	for _, dev := range devices {
+		// Virtual functions only repeat their physical function
+		if IsVirtualFn(dev.Name()) {
+			continue
+		}
+		e, _ := findSlot(filepath.Join(sysfsPci, dev.Name()))
+		slot, ok := bySlot[e]
+		if !ok {
+			continue
+		}
+
+		p := New(dev.Name())
+		if err := p.Collect(); err != nil {
+			continue
+		}
+		// Bridges inside the card, e.g. a PCIe switch, only repeat the card
+		if p.ClassID == "06" {
+			continue
+		}
+
+		slot.Devices = append(slot.Devices, p.PCIAddr)
+		if slot.Card != "" {
+			continue
+		}
+		slot.Card = strings.TrimSpace(p.Vendor + " " + p.Device)
+		if slot.Card == "" {
+			slot.Card = p.VendorID + ":" + p.DeviceID
+		}
+		if p.Link.CurrWidth != "" {
+			slot.Link = fmt.Sprintf("x%s (card x%s)", p.Link.CurrWidth, p.Link.MaxWidth)
+		}
+		if p.Slot != nil {
+			slot.Diagnose = p.Slot.Diagnose
+		}
+	}
+
+	return res, nil
+}
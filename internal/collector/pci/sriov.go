@@ -0,0 +1,82 @@
+package pci
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// parseSRIOV reports the virtual functions of a physical function that
+// supports SR-IOV, i.e. exposes sriov_totalvfs
+func (p *PCI) parseSRIOV(devicePath string) error {
+	total, err := readFileContent(filepath.Join(devicePath, "sriov_totalvfs"))
+	if err != nil {
+		// Not an SR-IOV physical function
+		return nil
+	}
+
+	sriov := &SRIOV{TotalVFs: total}
+	if enabled, err := readFileContent(filepath.Join(devicePath, "sriov_numvfs")); err == nil {
+		sriov.EnabledVFs = enabled
+		sriov.AvailableVFs = strconv.Itoa(atoi(total) - atoi(enabled))
+	}
+
+	links, err := filepath.Glob(filepath.Join(devicePath, "virtfn*"))
+	if err != nil {
+		return err
+	}
+	for _, link := range links {
+		index := strings.TrimPrefix(filepath.Base(link), "virtfn")
+		if _, err := strconv.Atoi(index); err != nil {
+			continue
+		}
+
+		target, err := os.Readlink(link)
+		if err != nil {
+			continue
+		}
+
+		vfPath := filepath.Join(sysfsPci, filepath.Base(target))
+		vf := &VirtualFunction{
+			Index:   index,
+			PCIAddr: filepath.Base(target),
+		}
+		if driver, err := os.Readlink(filepath.Join(vfPath, "driver")); err == nil {
+			vf.Driver = filepath.Base(driver)
+		}
+		if numa, err := readFileContent(filepath.Join(vfPath, "numa_node")); err == nil {
+			vf.Numa = numa
+		}
+		if netdevs, err := os.ReadDir(filepath.Join(vfPath, "net")); err == nil && len(netdevs) > 0 {
+			vf.Interface = netdevs[0].Name()
+		}
+		sriov.VFs = append(sriov.VFs, vf)
+	}
+
+	sort.Slice(sriov.VFs, func(i, j int) bool {
+		return atoi(sriov.VFs[i].Index) < atoi(sriov.VFs[j].Index)
+	})
+
+	p.SRIOV = sriov
+	return nil
+}
+
+// IsVirtualFn reports whether the device is an SR-IOV virtual function,
+// which is reported under its physical function
+func IsVirtualFn(addr string) bool {
+	_, err := os.Lstat(filepath.Join(sysfsPci, addr, "physfn"))
+	return err == nil
+}
+
+// physicalFunctions drops the virtual functions from a device list
+func physicalFunctions(addrs []string) []string {
+	res := addrs[:0]
+	for _, addr := range addrs {
+		if !IsVirtualFn(addr) {
+			res = append(res, addr)
+		}
+	}
+	return res
+}
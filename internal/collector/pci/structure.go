@@ -2,25 +2,92 @@ package pci
 
 // PCI 表示PCI设备信息
 type PCI struct {
-	PCIID       string    `json:"pci_id,omitzero"`            // PCI设备ID
-	PCIAddr     string    `json:"pci_address,omitzero"`       // PCI设备地址
-	Vendor      string    `json:"vendor,omitzero"`            // 厂商名称
-	VendorID    string    `json:"vendor_id,omitzero"`         // 厂商ID
-	Device      string    `json:"device,omitzero"`            // 设备名称
-	DeviceID    string    `json:"device_id,omitzero"`         // 设备ID
-	SubVendor   string    `json:"sub_vendor,omitzero"`        // 子厂商名称
-	SubVendorID string    `json:"sub_vendor_id,omitzero"`     // 子厂商ID
-	SubDevice   string    `json:"sub_device,omitzero"`        // 子设备名称
-	SubDeviceID string    `json:"sub_device_id,omitzero"`     // 子设备ID
-	Class       string    `json:"class,omitzero"`             // 设备类型
-	ClassID     string    `json:"class_id,omitzero"`          // 设备类型ID
-	SubClass    string    `json:"sub_class,omitzero"`         // 子设备类型
-	SubClassID  string    `json:"sub_class_id,omitzero"`      //	子设备类型ID
-	ProgIfID    string    `json:"prog_interface_id,omitzero"` // 编程接口ID
-	Numa        string    `json:"numa,omitzero"`              // NUMA节点
-	Revision    string    `json:"revision,omitzero"`          // 修订版本
-	Driver      PCIDriver `json:"driver,omitzero"`            // 驱动信息
-	Link        PCILink   `json:"link,omitzero"`              // 链接信息
+	PCIID       string      `json:"pci_id,omitzero"`              // PCI设备ID
+	PCIAddr     string      `json:"pci_address,omitzero"`         // PCI设备地址
+	Vendor      string      `json:"vendor,omitzero"`              // 厂商名称
+	VendorID    string      `json:"vendor_id,omitzero"`           // 厂商ID
+	Device      string      `json:"device,omitzero"`              // 设备名称
+	DeviceID    string      `json:"device_id,omitzero"`           // 设备ID
+	SubVendor   string      `json:"sub_vendor,omitzero"`          // 子厂商名称
+	SubVendorID string      `json:"sub_vendor_id,omitzero"`       // 子厂商ID
+	SubDevice   string      `json:"sub_device,omitzero"`          // 子设备名称
+	SubDeviceID string      `json:"sub_device_id,omitzero"`       // 子设备ID
+	Class       string      `json:"class,omitzero"`               // 设备类型
+	ClassID     string      `json:"class_id,omitzero"`            // 设备类型ID
+	SubClass    string      `json:"sub_class,omitzero"`           // 子设备类型
+	SubClassID  string      `json:"sub_class_id,omitzero"`        //	子设备类型ID
+	ProgIfID    string      `json:"prog_interface_id,omitzero"`   // 编程接口ID
+	Numa        string      `json:"numa,omitzero"`                // NUMA节点
+	Revision    string      `json:"revision,omitzero"`            // 修订版本
+	Driver      PCIDriver   `json:"driver,omitzero"`              // 驱动信息
+	Link        PCILink     `json:"link,omitzero"`                // 链接信息
+	AER         *AER        `json:"aer,omitzero"`                 // 本次启动以来的 AER 错误计数
+	AERHistory  *AERHistory `json:"aer_history,omitzero"`         // rasdaemon 记录的 AER 历史错误（含重启前）
+	Slot        *Slot       `json:"slot,omitzero" name:"Slot"`    // 所在物理插槽或板载位置（SMBIOS Type 9/41）
+	SRIOV       *SRIOV      `json:"sriov,omitzero" name:"SR-IOV"` // SR-IOV 虚拟功能（仅物理功能）
+
+	Diagnose       string `json:"diagnose,omitzero"`        // 链路降级、AER 错误等诊断结果
+	DiagnoseDetail string `json:"diagnose_detail,omitzero"` // 诊断详情
+}
+
+// Slot 表示 SMBIOS 记录的物理插槽（Type 9）或板载设备位置（Type 41）
+type Slot struct {
+	Designation   string   `json:"designation,omitzero" name:"Designation" output:"both"`            // 插槽丝印名称，如 "SLOT 3"
+	Type          string   `json:"type,omitzero" name:"Type" output:"detail"`                        // 插槽类型
+	Width         string   `json:"width,omitzero" name:"Width" output:"detail"`                      // 插槽电气宽度
+	PhysicalWidth string   `json:"physical_width,omitzero" name:"Physical Width" output:"detail"`    // 插槽物理宽度
+	Length        string   `json:"length,omitzero"`                                                  // 插槽长度
+	Usage         string   `json:"usage,omitzero" name:"Usage" output:"detail"`                      // 占用状态
+	BusAddress    string   `json:"bus_address,omitzero" name:"Bus Address" output:"detail"`          // SMBIOS 登记的 PCI 地址
+	Card          string   `json:"card,omitzero" name:"Card" output:"both"`                          // 插槽内的卡（仅插槽清单）
+	Devices       []string `json:"devices,omitzero"`                                                 // 插槽内各设备的 PCI 地址（仅插槽清单）
+	Link          string   `json:"link,omitzero" name:"Link" output:"detail"`                        // 当前链路宽度及卡支持的宽度（仅插槽清单）
+	Diagnose      string   `json:"diagnose,omitzero" name:"Diagnose" output:"both" color:"Diagnose"` // 卡宽于插槽布线等问题
+}
+
+// SRIOV 表示物理功能（PF）的 SR-IOV 虚拟功能（VF）信息
+type SRIOV struct {
+	TotalVFs     string             `json:"total_vfs,omitzero" name:"Total VFs" output:"both"`         // 支持的 VF 总数
+	EnabledVFs   string             `json:"enabled_vfs,omitzero" name:"Enabled VFs" output:"both"`     // 已启用的 VF 数
+	AvailableVFs string             `json:"available_vfs,omitzero" name:"Available VFs" output:"both"` // 尚可启用的 VF 数
+	VFs          []*VirtualFunction `json:"vfs,omitzero" name:"VF" output:"detail"`                    // 已启用的 VF
+}
+
+// VirtualFunction 表示一个 SR-IOV 虚拟功能
+type VirtualFunction struct {
+	Index      string `json:"index" name:"Index" output:"detail"`                      // VF 序号（virtfnN）
+	PCIAddr    string `json:"pci_address,omitzero" name:"PCI Address" output:"detail"` // VF 的 PCI 地址
+	Driver     string `json:"driver,omitzero" name:"Driver" output:"detail"`           // 绑定的驱动，如 vfio-pci、iavf、mlx5_core
+	Numa       string `json:"numa,omitzero" name:"NUMA" output:"detail"`               // NUMA节点
+	Interface  string `json:"interface,omitzero" name:"Interface" output:"detail"`     // 宿主机上的 VF 网口名称（网卡 VF 且驱动在宿主机时）
+	MAC        string `json:"mac,omitzero" name:"MAC" output:"detail"`                 // PF 为该 VF 配置的 MAC（网卡 VF）
+	VLAN       string `json:"vlan,omitzero" name:"VLAN" output:"detail"`               // VLAN ID（网卡 VF）
+	QoS        string `json:"qos,omitzero" name:"QoS" output:"detail"`                 // VLAN 优先级（网卡 VF）
+	SpoofCheck string `json:"spoof_check,omitzero" name:"Spoof Check" output:"detail"` // MAC 防欺骗检查（网卡 VF）
+	Trust      string `json:"trust,omitzero" name:"Trust" output:"detail"`             // 信任模式（网卡 VF）
+	LinkState  string `json:"link_state,omitzero" name:"Link State" output:"detail"`   // 链路状态策略 auto/enable/disable（网卡 VF）
+}
+
+// AER 表示内核 sysfs 中本次启动以来的 AER 错误计数
+type AER struct {
+	Corrected         string `json:"corrected,omitzero"`           // 可纠正错误次数
+	NonFatal          string `json:"non_fatal,omitzero"`           // 不可纠正非致命错误次数
+	Fatal             string `json:"fatal,omitzero"`               // 不可纠正致命错误次数
+	Errors            string `json:"errors,omitzero"`              // 发生过的错误类型及次数，如 "BadTLP=3"
+	RootPortCorrected string `json:"root_port_corrected,omitzero"` // 根端口收到的下游可纠正错误总数
+	RootPortNonFatal  string `json:"root_port_non_fatal,omitzero"` // 根端口收到的下游非致命错误总数
+	RootPortFatal     string `json:"root_port_fatal,omitzero"`     // 根端口收到的下游致命错误总数
+}
+
+// AERHistory 表示 rasdaemon 记录的 PCIe AER 历史错误统计
+type AERHistory struct {
+	Corrected string `json:"corrected,omitzero"`  // 可纠正错误次数
+	NonFatal  string `json:"non_fatal,omitzero"`  // 不可纠正非致命错误次数
+	Fatal     string `json:"fatal,omitzero"`      // 不可纠正致命错误次数
+	FirstSeen string `json:"first_seen,omitzero"` // 首次出现时间
+	LastSeen  string `json:"last_seen,omitzero"`  // 最近出现时间
+	LastDay   string `json:"last_day,omitzero"`   // 最近 24 小时内的错误次数
+	LastError string `json:"last_error,omitzero"` // 最近一次错误描述
 }
 
 // PCIDriver 表示PCI设备的驱动信息
@@ -37,4 +104,38 @@ type PCILink struct {
 	MaxWidth  string `json:"max_link_width,omitzero"`     // 最大链接宽度
 	CurrSpeed string `json:"current_link_speed,omitzero"` // 当前链接速度
 	CurrWidth string `json:"current_link_width,omitzero"` // 当前链接宽度
+
+	UpstreamPort      string `json:"upstream_port,omitzero"`           // 上游端口（根端口或交换芯片下行端口）地址
+	UpstreamMaxSpeed  string `json:"upstream_max_link_speed,omitzero"` // 上游端口最大链接速度
+	UpstreamMaxWidth  string `json:"upstream_max_link_width,omitzero"` // 上游端口最大链接宽度
+	UpstreamCorrected string `json:"upstream_corrected,omitzero"`      // 上游端口本次启动以来的可纠正错误次数
+	SlotWidth         string `json:"slot_width,omitzero"`              // 所在插槽的电气宽度（SMBIOS Type 9）
+}
+
+// Topology 表示从 sysfs 构建的 PCIe 层级结构
+type Topology struct {
+	RootComplexes []*TopologyNode `json:"root_complexes,omitzero"` // 根复合体（PCI 域与根总线）
+}
+
+// TopologyNode 表示 PCIe 层级中的一个节点：根复合体、根端口、交换芯片端口、桥或端点功能
+type TopologyNode struct {
+	Address        string          `json:"address"`                    // PCI 地址，根复合体为 pciDDDD:BB
+	Type           string          `json:"type,omitzero"`              // 节点类型，如 Root Port、Switch Upstream Port、Endpoint
+	Vendor         string          `json:"vendor,omitzero"`            // 厂商名称
+	Device         string          `json:"device,omitzero"`            // 设备名称
+	VendorID       string          `json:"vendor_id,omitzero"`         // 厂商ID
+	DeviceID       string          `json:"device_id,omitzero"`         // 设备ID
+	Class          string          `json:"class,omitzero"`             // 子设备类型名称
+	ClassID        string          `json:"class_id,omitzero"`          // 设备类型及子类型ID，如 0604
+	Driver         string          `json:"driver,omitzero"`            // 驱动名称
+	Numa           string          `json:"numa,omitzero"`              // NUMA节点
+	Link           string          `json:"link,omitzero"`              // 当前链路，如 Gen4 x16
+	MaxLink        string          `json:"max_link,omitzero"`          // 设备支持的最大链路
+	Slot           string          `json:"slot,omitzero"`              // 所在物理插槽或板载位置
+	VFs            string          `json:"vfs,omitzero"`               // 已启用/支持的 SR-IOV VF 数，如 8/64（仅物理功能）
+	AvailableVFs   string          `json:"available_vfs,omitzero"`     // 尚可启用的 VF 数（仅物理功能）
+	PhysFn         string          `json:"physical_function,omitzero"` // 所属物理功能的 PCI 地址（仅虚拟功能）
+	Diagnose       string          `json:"diagnose,omitzero"`          // 链路降级、AER 错误等诊断结果
+	DiagnoseDetail string          `json:"diagnose_detail,omitzero"`   // 诊断详情
+	Children       []*TopologyNode `json:"children,omitzero"`          // 下游节点
 }
@@ -0,0 +1,358 @@
+package pci
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zenithax-cc/baize/pkg/utils"
+	"golang.org/x/sync/errgroup"
+)
+
+// Node types of the PCIe hierarchy
+const (
+	nodeRootComplex      = "Root Complex"
+	nodeRootPort         = "Root Port"
+	nodeSwitchUpstream   = "Switch Upstream Port"
+	nodeSwitchDownstream = "Switch Downstream Port"
+	nodeBridge           = "PCI Bridge"
+	nodeHostBridge       = "Host Bridge"
+	nodeEndpoint         = "Endpoint"
+	nodeRCEndpoint       = "RC Integrated Endpoint"
+	nodeRCEventColl      = "RC Event Collector"
+	nodeVirtualFn        = "Virtual Function"
+)
+
+// pciePortTypes maps the Device/Port Type of the PCIe capability to node types
+var pciePortTypes = map[uint8]string{
+	0x0: nodeEndpoint,
+	0x1: nodeEndpoint, // legacy endpoint
+	0x4: nodeRootPort,
+	0x5: nodeSwitchUpstream,
+	0x6: nodeSwitchDownstream,
+	0x7: nodeBridge, // PCIe to PCI/PCI-X bridge
+	0x8: nodeBridge, // PCI/PCI-X to PCIe bridge
+	0x9: nodeRCEndpoint,
+	0xa: nodeRCEventColl,
+}
+
+// Concurrent device collection limit
+const topologyConcurrency = 8
+
+// NewTopology creates the PCIe topology collector
+func NewTopology() *Topology {
+	return &Topology{}
+}
+
+// Name returns the collector identifier used for module routing
+func (t *Topology) Name() string {
+	return "pci"
+}
+
+// Collect builds the PCIe hierarchy from the sysfs device paths, so bridges
+// nest their secondary buses and VMD domains nest under their controller
+func (t *Topology) Collect(ctx context.Context) error {
+	entries, err := os.ReadDir(sysfsPci)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", sysfsPci, err)
+	}
+
+	nodes := make([]*TopologyNode, len(entries))
+	paths := make([]string, len(entries))
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(topologyConcurrency)
+	for i, entry := range entries {
+		eg.Go(func() error {
+			if err := egCtx.Err(); err != nil {
+				return err
+			}
+
+			devicePath := filepath.Join(sysfsPci, entry.Name())
+			real, err := filepath.EvalSymlinks(devicePath)
+			if err != nil {
+				return nil
+			}
+
+			p := New(entry.Name())
+			if err := p.Collect(); err != nil {
+				return nil
+			}
+
+			nodes[i] = newTopologyNode(p, devicePath)
+			paths[i] = real
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	byPath := make(map[string]*TopologyNode, len(nodes))
+	for i, n := range nodes {
+		if n != nil {
+			byPath[paths[i]] = n
+		}
+	}
+
+	roots := make(map[string]*TopologyNode)
+	for i, n := range nodes {
+		if n == nil {
+			continue
+		}
+
+		parentPath := filepath.Dir(paths[i])
+		if parent, ok := byPath[parentPath]; ok {
+			parent.Children = append(parent.Children, n)
+			continue
+		}
+
+		root, ok := roots[parentPath]
+		if !ok {
+			root = &TopologyNode{
+				Address: filepath.Base(parentPath),
+				Type:    nodeRootComplex,
+			}
+			roots[parentPath] = root
+
+			// A root bus below a device, e.g. an Intel VMD domain
+			if owner, ok := byPath[filepath.Dir(parentPath)]; ok {
+				owner.Children = append(owner.Children, root)
+			} else {
+				t.RootComplexes = append(t.RootComplexes, root)
+			}
+		}
+		if root.Numa == "" {
+			root.Numa = n.Numa
+		}
+		root.Children = append(root.Children, n)
+	}
+
+	sort.Slice(t.RootComplexes, func(i, j int) bool {
+		return t.RootComplexes[i].Address < t.RootComplexes[j].Address
+	})
+	for _, root := range t.RootComplexes {
+		root.sortChildren()
+		root.classifyPorts()
+	}
+
+	return nil
+}
+
+// newTopologyNode summarises a collected device for the tree
+func newTopologyNode(p *PCI, devicePath string) *TopologyNode {
+	n := &TopologyNode{
+		Address:        p.PCIAddr,
+		Vendor:         p.Vendor,
+		Device:         p.Device,
+		VendorID:       p.VendorID,
+		DeviceID:       p.DeviceID,
+		ClassID:        p.ClassID + p.SubClassID,
+		Driver:         p.Driver.DriverName,
+		Numa:           p.Numa,
+		Diagnose:       p.Diagnose,
+		DiagnoseDetail: p.DiagnoseDetail,
+	}
+	if p.SubClass != "Unknown" {
+		n.Class = p.SubClass
+	}
+	if p.Slot != nil {
+		n.Slot = p.Slot.Designation
+	}
+	if link := formatLink(p.Link.CurrSpeed, p.Link.CurrWidth); link != "" {
+		n.Link = link
+		n.MaxLink = formatLink(p.Link.MaxSpeed, p.Link.MaxWidth)
+	}
+	if portType, ok := pciePortType(devicePath); ok {
+		n.Type = pciePortTypes[portType]
+	}
+	if p.SRIOV != nil {
+		n.VFs = p.SRIOV.EnabledVFs + "/" + p.SRIOV.TotalVFs
+		n.AvailableVFs = p.SRIOV.AvailableVFs
+	}
+	if physfn, err := os.Readlink(filepath.Join(devicePath, "physfn")); err == nil {
+		n.Type = nodeVirtualFn
+		n.PhysFn = filepath.Base(physfn)
+	}
+	return n
+}
+
+// formatLink renders a link as e.g. "Gen4 x16", or "" when it is down or
+// the device is not PCIe
+func formatLink(speed, width string) string {
+	s, w := parseLinkSpeed(speed), atoi(width)
+	if s == 0 || w == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s x%d", pcieGen(s), w)
+}
+
+// pciePortType reads the Device/Port Type from the PCIe capability. Only
+// root can read past the first 64 bytes of config space.
+func pciePortType(devicePath string) (uint8, bool) {
+	cfg, err := os.ReadFile(filepath.Join(devicePath, "config"))
+	if err != nil || len(cfg) < 0x100 {
+		return 0, false
+	}
+
+	// Status register: capabilities list present
+	if cfg[0x06]&0x10 == 0 {
+		return 0, false
+	}
+
+	ptr := int(cfg[0x34]) &^ 3
+	for range 48 {
+		if ptr < 0x40 || ptr+4 > len(cfg) {
+			break
+		}
+		if cfg[ptr] == 0x10 {
+			return cfg[ptr+2] >> 4 & 0x0f, true
+		}
+		ptr = int(cfg[ptr+1]) &^ 3
+	}
+	return 0, false
+}
+
+// classifyPorts names the nodes whose config space could not be read from
+// their class and their place in the tree
+func (n *TopologyNode) classifyPorts() {
+	for _, c := range n.Children {
+		if c.Type == "" {
+			isBridge := c.ClassID == "0604" || c.ClassID == "0609"
+			switch {
+			case c.ClassID == "0600":
+				c.Type = nodeHostBridge
+			case !isBridge && n.Type == nodeRootComplex:
+				c.Type = nodeRCEndpoint
+			case !isBridge:
+				c.Type = nodeEndpoint
+			case n.Type == nodeRootComplex:
+				c.Type = nodeRootPort
+			case n.Type == nodeSwitchUpstream:
+				c.Type = nodeSwitchDownstream
+			case c.hasBridgeChildren():
+				c.Type = nodeSwitchUpstream
+			default:
+				c.Type = nodeBridge
+			}
+		}
+		c.classifyPorts()
+	}
+}
+
+func (n *TopologyNode) hasBridgeChildren() bool {
+	for _, c := range n.Children {
+		if c.ClassID == "0604" || c.ClassID == "0609" {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *TopologyNode) sortChildren() {
+	sort.Slice(n.Children, func(i, j int) bool {
+		return n.Children[i].Address < n.Children[j].Address
+	})
+	for _, c := range n.Children {
+		c.sortChildren()
+	}
+}
+
+// JSON serializes the topology as nested JSON and writes it to stdout
+func (t *Topology) JSON() error {
+	return utils.JSONPrintln(t)
+}
+
+// DetailPrintln prints the tree with IDs, class, NUMA node and maximum link
+func (t *Topology) DetailPrintln() {
+	t.printTree(true)
+}
+
+// BriefPrintln prints the tree like lspci -tv, with baize's diagnoses
+func (t *Topology) BriefPrintln() {
+	t.printTree(false)
+}
+
+func (t *Topology) printTree(detail bool) {
+	if len(t.RootComplexes) == 0 {
+		return
+	}
+
+	fmt.Printf("%s[%s]%s\n\n", utils.BoldCyan, "PCIE TOPOLOGY", utils.Reset)
+	for _, root := range t.RootComplexes {
+		root.print("", "", detail)
+	}
+	fmt.Println()
+}
+
+// print writes the node line and its subtree; prefix is the indentation of
+// the node line, childPrefix that of the lines below it
+func (n *TopologyNode) print(prefix, childPrefix string, detail bool) {
+	fmt.Println(prefix + n.label(detail))
+	if n.Diagnose != "" && n.Diagnose != diagnoseHealthy {
+		fmt.Printf("%s    %s%s: %s%s\n", childPrefix, utils.BoldRed, n.Diagnose, n.DiagnoseDetail, utils.Reset)
+	}
+
+	for i, c := range n.Children {
+		if i == len(n.Children)-1 {
+			c.print(childPrefix+"└── ", childPrefix+"    ", detail)
+		} else {
+			c.print(childPrefix+"├── ", childPrefix+"│   ", detail)
+		}
+	}
+}
+
+// label renders e.g. "0000:3b:00.0 Endpoint: Broadcom / LSI MegaRAID 12GSAS/PCIe Secure SAS39xx [megaraid_sas] Gen4 x8 SLOT 3"
+func (n *TopologyNode) label(detail bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s%s %s", utils.BoldWhite, n.Address, utils.Reset, n.Type)
+
+	if name := n.name(); name != "" {
+		b.WriteString(": " + name)
+	}
+	if detail && n.VendorID != "" {
+		fmt.Fprintf(&b, " [%s:%s]", n.VendorID, n.DeviceID)
+	}
+	if detail && n.Class != "" {
+		fmt.Fprintf(&b, " (%s)", n.Class)
+	}
+	if n.Driver != "" {
+		fmt.Fprintf(&b, " %s[%s]%s", utils.Cyan, n.Driver, utils.Reset)
+	}
+	if n.Link != "" {
+		b.WriteString(" " + n.Link)
+		if detail && n.MaxLink != "" && n.MaxLink != n.Link {
+			b.WriteString(" (max " + n.MaxLink + ")")
+		}
+	}
+	if n.Slot != "" {
+		fmt.Fprintf(&b, " %s%s%s", utils.Green, n.Slot, utils.Reset)
+	}
+	if n.VFs != "" {
+		b.WriteString(" SR-IOV " + n.VFs + " VFs")
+		if n.AvailableVFs != "" {
+			b.WriteString(", " + n.AvailableVFs + " available")
+		}
+	}
+	if detail && n.PhysFn != "" {
+		b.WriteString(" PF " + n.PhysFn)
+	}
+	if detail && n.Numa != "" && n.Numa != "-1" {
+		b.WriteString(" NUMA " + n.Numa)
+	}
+	return b.String()
+}
+
+// name joins vendor and device, leaving out names pci.ids does not know
+func (n *TopologyNode) name() string {
+	var parts []string
+	for _, s := range []string{n.Vendor, n.Device} {
+		if s != "" && s != "Unknown" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
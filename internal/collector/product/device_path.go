@@ -0,0 +1,133 @@
+package product
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// UEFI device path node types.
+const (
+	dpHardware  = 0x01
+	dpACPI      = 0x02
+	dpMessaging = 0x03
+	dpMedia     = 0x04
+	dpBBS       = 0x05
+	dpEnd       = 0x7f
+)
+
+// formatDevicePath renders a UEFI device path in the text form of the UEFI
+// specification, e.g. "PciRoot(0x0)/Pci(0x1f,0x2)/Sata(0x0,0xffff,0x0)/HD(1,GPT,...)/\EFI\BOOT\BOOTX64.EFI".
+// Instances are separated by ",".
+func formatDevicePath(b []byte) string {
+	var (
+		res  strings.Builder
+		node []string
+	)
+	for len(b) >= 4 {
+		typ, sub := b[0], b[1]
+		n := int(binary.LittleEndian.Uint16(b[2:]))
+		if n < 4 || n > len(b) {
+			node = append(node, fmt.Sprintf("Invalid(%d)", n))
+			break
+		}
+		data := b[4:n]
+		b = b[n:]
+
+		if typ == dpEnd {
+			res.WriteString(strings.Join(node, "/"))
+			node = nil
+			if sub == 0xff {
+				return res.String()
+			}
+			res.WriteString(",")
+			continue
+		}
+		node = append(node, formatDevicePathNode(typ, sub, data))
+	}
+	res.WriteString(strings.Join(node, "/"))
+	return res.String()
+}
+
+func formatDevicePathNode(typ, sub uint8, d []byte) string {
+	le16 := func(off int) uint16 { return binary.LittleEndian.Uint16(d[off:]) }
+	le32 := func(off int) uint32 { return binary.LittleEndian.Uint32(d[off:]) }
+	le64 := func(off int) uint64 { return binary.LittleEndian.Uint64(d[off:]) }
+
+	switch {
+	case typ == dpHardware && sub == 0x01 && len(d) >= 2:
+		return fmt.Sprintf("Pci(0x%x,0x%x)", d[1], d[0])
+	case typ == dpHardware && sub == 0x04 && len(d) >= 16:
+		return fmt.Sprintf("VenHw(%s)", efiGUID(d))
+
+	case typ == dpACPI && sub == 0x01 && len(d) >= 8:
+		hid, uid := le32(0), le32(4)
+		switch hid {
+		case 0x0a0341d0:
+			return fmt.Sprintf("PciRoot(0x%x)", uid)
+		case 0x0a0841d0:
+			return fmt.Sprintf("PcieRoot(0x%x)", uid)
+		}
+		return fmt.Sprintf("Acpi(PNP%04X,0x%x)", hid>>16, uid)
+
+	case typ == dpMessaging && sub == 0x02 && len(d) >= 4:
+		return fmt.Sprintf("Scsi(0x%x,0x%x)", le16(0), le16(2))
+	case typ == dpMessaging && sub == 0x05 && len(d) >= 2:
+		return fmt.Sprintf("USB(0x%x,0x%x)", d[0], d[1])
+	case typ == dpMessaging && sub == 0x0a && len(d) >= 16:
+		return fmt.Sprintf("VenMsg(%s)", efiGUID(d))
+	case typ == dpMessaging && sub == 0x0b && len(d) >= 33:
+		return fmt.Sprintf("MAC(%s,0x%x)", hex.EncodeToString(d[:6]), d[32])
+	case typ == dpMessaging && sub == 0x0c && len(d) >= 15:
+		return fmt.Sprintf("IPv4(%s)", net.IP(d[4:8]))
+	case typ == dpMessaging && sub == 0x0d && len(d) >= 36:
+		return fmt.Sprintf("IPv6(%s)", net.IP(d[16:32]))
+	case typ == dpMessaging && sub == 0x12 && len(d) >= 6:
+		return fmt.Sprintf("Sata(0x%x,0x%x,0x%x)", le16(0), le16(2), le16(4))
+	case typ == dpMessaging && sub == 0x17 && len(d) >= 12:
+		return fmt.Sprintf("NVMe(0x%x,%s)", le32(0), strings.ToUpper(hex.EncodeToString(d[4:12])))
+	case typ == dpMessaging && sub == 0x18:
+		return fmt.Sprintf("Uri(%s)", string(d))
+	case typ == dpMessaging && sub == 0x1f:
+		return "Dns()"
+
+	case typ == dpMedia && sub == 0x01 && len(d) >= 38:
+		part, start, size := le32(0), le64(4), le64(12)
+		switch d[37] {
+		case 0x02:
+			return fmt.Sprintf("HD(%d,GPT,%s,0x%x,0x%x)", part, efiGUID(d[20:36]), start, size)
+		case 0x01:
+			return fmt.Sprintf("HD(%d,MBR,0x%08x,0x%x,0x%x)", part, le32(20), start, size)
+		}
+		return fmt.Sprintf("HD(%d,%d,0,0x%x,0x%x)", part, d[37], start, size)
+	case typ == dpMedia && sub == 0x02 && len(d) >= 4:
+		return fmt.Sprintf("CDROM(0x%x)", le32(0))
+	case typ == dpMedia && sub == 0x03 && len(d) >= 16:
+		return fmt.Sprintf("VenMedia(%s)", efiGUID(d))
+	case typ == dpMedia && sub == 0x04:
+		s, _ := ucs2String(d)
+		return s
+	case typ == dpMedia && sub == 0x06 && len(d) >= 16:
+		return fmt.Sprintf("FvFile(%s)", efiGUID(d))
+	case typ == dpMedia && sub == 0x07 && len(d) >= 16:
+		return fmt.Sprintf("Fv(%s)", efiGUID(d))
+
+	case typ == dpBBS && sub == 0x01 && len(d) >= 4:
+		desc := strings.TrimRight(string(d[4:]), "\x00")
+		return fmt.Sprintf("BBS(0x%x,%s,0x%x)", le16(0), desc, le16(2))
+	}
+
+	return fmt.Sprintf("Path(%d,%d,%s)", typ, sub, strings.ToUpper(hex.EncodeToString(d)))
+}
+
+// efiGUID formats a 16-byte EFI_GUID, whose first three fields are
+// little-endian.
+func efiGUID(b []byte) string {
+	return fmt.Sprintf("%08x-%04x-%04x-%x-%x",
+		binary.LittleEndian.Uint32(b[0:4]),
+		binary.LittleEndian.Uint16(b[4:6]),
+		binary.LittleEndian.Uint16(b[6:8]),
+		b[8:10], b[10:16])
+}
@@ -0,0 +1,221 @@
+package product
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	efiDir     = "/sys/firmware/efi"
+	efivarsDir = "/sys/firmware/efi/efivars"
+
+	// EFI_GLOBAL_VARIABLE and EFI_IMAGE_SECURITY_DATABASE_GUID
+	efiGlobalGUID   = "8be4df61-93ca-11d2-aa0d-00e098032b8c"
+	efiImageSecGUID = "d719b2cb-3d3a-4596-a3bc-dad00e67656f"
+
+	loadOptionActive = 0x00000001
+
+	diagnoseHealthy   = "Healthy"
+	diagnoseUnhealthy = "Unhealthy"
+)
+
+// readEFIVar returns the data of an efivarfs variable without the leading
+// 4-byte attributes.
+func readEFIVar(name, guid string) ([]byte, error) {
+	b, err := os.ReadFile(filepath.Join(efivarsDir, name+"-"+guid))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < 4 {
+		return nil, fmt.Errorf("efi variable %s too short: %d bytes", name, len(b))
+	}
+	return b[4:], nil
+}
+
+// collectFirmware reports the boot mode, the Secure Boot state and key
+// databases, and the UEFI boot entries.
+func (p *Product) collectFirmware() error {
+	fw := &p.Firmware
+	if _, err := os.Stat(efiDir); err != nil {
+		fw.BootMode = "Legacy BIOS"
+		fw.SecureBoot = "Unsupported"
+		fw.diagnose()
+		return nil
+	}
+	fw.BootMode = "UEFI"
+
+	if _, err := os.Stat(efivarsDir); err != nil {
+		fw.diagnose()
+		return fmt.Errorf("efivarfs not mounted at %s: %w", efivarsDir, err)
+	}
+
+	fw.SecureBoot = efiBoolVar("SecureBoot", "Enabled", "Disabled")
+	fw.SetupMode = efiBoolVar("SetupMode", "Setup Mode", "User Mode")
+	fw.PK = efiSignatureDB("PK", efiGlobalGUID)
+	fw.KEK = efiSignatureDB("KEK", efiGlobalGUID)
+	fw.DB = efiSignatureDB("db", efiImageSecGUID)
+	fw.DBX = efiSignatureDB("dbx", efiImageSecGUID)
+
+	err := fw.collectBootEntries()
+	fw.diagnose()
+
+	return err
+}
+
+// efiBoolVar reads a one-byte global variable such as SecureBoot.
+func efiBoolVar(name, on, off string) string {
+	b, err := readEFIVar(name, efiGlobalGUID)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return "Unsupported"
+	case err != nil || len(b) == 0:
+		return "Unknown"
+	case b[0] == 1:
+		return on
+	default:
+		return off
+	}
+}
+
+// efiSignatureDB reports whether a key database is enrolled and how many
+// signatures its EFI_SIGNATURE_LISTs hold.
+func efiSignatureDB(name, guid string) string {
+	b, err := readEFIVar(name, guid)
+	if err != nil || len(b) == 0 {
+		return "Not Present"
+	}
+
+	const listHeader = 28 // SignatureType GUID, ListSize, HeaderSize, SignatureSize
+	count := 0
+	for len(b) >= listHeader {
+		listSize := int(binary.LittleEndian.Uint32(b[16:20]))
+		headerSize := int(binary.LittleEndian.Uint32(b[20:24]))
+		sigSize := int(binary.LittleEndian.Uint32(b[24:28]))
+		if listSize < listHeader || listSize > len(b) || sigSize == 0 ||
+			headerSize < 0 || headerSize > listSize-listHeader {
+			break
+		}
+		count += (listSize - listHeader - headerSize) / sigSize
+		b = b[listSize:]
+	}
+
+	return fmt.Sprintf("Present (%d entries)", count)
+}
+
+// collectBootEntries decodes BootCurrent, BootOrder and the Boot#### load
+// options they reference, in boot order, followed by those not in it.
+func (fw *Firmware) collectBootEntries() error {
+	if b, err := readEFIVar("BootCurrent", efiGlobalGUID); err == nil && len(b) >= 2 {
+		fw.BootCurrent = fmt.Sprintf("Boot%04X", binary.LittleEndian.Uint16(b))
+	}
+
+	var order []uint16
+	if b, err := readEFIVar("BootOrder", efiGlobalGUID); err == nil {
+		names := make([]string, 0, len(b)/2)
+		for i := 0; i+2 <= len(b); i += 2 {
+			n := binary.LittleEndian.Uint16(b[i:])
+			order = append(order, n)
+			names = append(names, fmt.Sprintf("Boot%04X", n))
+		}
+		fw.BootOrder = strings.Join(names, ",")
+	}
+
+	// Entries outside BootOrder are still bootable through the boot menu.
+	matches, _ := filepath.Glob(filepath.Join(efivarsDir, "Boot[0-9A-F][0-9A-F][0-9A-F][0-9A-F]-"+efiGlobalGUID))
+	for _, m := range matches {
+		var n uint16
+		if _, err := fmt.Sscanf(filepath.Base(m)[4:8], "%04X", &n); err == nil && !slices.Contains(order, n) {
+			order = append(order, n)
+		}
+	}
+
+	var errs []error
+	for _, n := range order {
+		name := fmt.Sprintf("Boot%04X", n)
+		b, err := readEFIVar(name, efiGlobalGUID)
+		if err != nil {
+			// BootOrder may name entries that no longer exist
+			if !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		entry, err := parseLoadOption(b)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		entry.Name = name
+		entry.Current = name == fw.BootCurrent
+		fw.BootEntries = append(fw.BootEntries, entry)
+	}
+
+	return errors.Join(errs...)
+}
+
+// parseLoadOption decodes an EFI_LOAD_OPTION: attributes, file path list
+// length, a NUL-terminated UCS-2 description, then the device path.
+func parseLoadOption(b []byte) (*BootEntry, error) {
+	if len(b) < 6 {
+		return nil, fmt.Errorf("load option too short: %d bytes", len(b))
+	}
+	attr := binary.LittleEndian.Uint32(b)
+	pathLen := int(binary.LittleEndian.Uint16(b[4:]))
+
+	desc, rest := ucs2String(b[6:])
+	if pathLen > len(rest) {
+		return nil, fmt.Errorf("device path length %d exceeds load option", pathLen)
+	}
+
+	entry := &BootEntry{
+		Description: desc,
+		Active:      attr&loadOptionActive != 0,
+		DevicePath:  formatDevicePath(rest[:pathLen]),
+	}
+	return entry, nil
+}
+
+// ucs2String decodes a NUL-terminated UCS-2 string and returns the bytes
+// after the terminator.
+func ucs2String(b []byte) (string, []byte) {
+	var u []uint16
+	for i := 0; i+2 <= len(b); i += 2 {
+		c := binary.LittleEndian.Uint16(b[i:])
+		if c == 0 {
+			return string(utf16.Decode(u)), b[i+2:]
+		}
+		u = append(u, c)
+	}
+	return string(utf16.Decode(u)), nil
+}
+
+// diagnose checks the compliance baseline: UEFI boot with Secure Boot
+// enabled and a platform key enrolled.
+func (fw *Firmware) diagnose() {
+	var details []string
+	switch {
+	case fw.BootMode != "UEFI":
+		details = append(details, "legacy BIOS boot, Secure Boot unavailable")
+	case fw.SecureBoot == "":
+		details = append(details, "Secure Boot state unknown (efivars not mounted)")
+	case fw.SecureBoot != "Enabled":
+		details = append(details, "Secure Boot is "+strings.ToLower(fw.SecureBoot))
+	}
+	if fw.SetupMode == "Setup Mode" {
+		details = append(details, "firmware in Setup Mode, no platform key enrolled")
+	}
+
+	if len(details) == 0 {
+		fw.Diagnose = diagnoseHealthy
+		return
+	}
+	fw.Diagnose = diagnoseUnhealthy
+	fw.DiagnoseDetail = strings.Join(details, "; ")
+}
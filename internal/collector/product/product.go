@@ -4,14 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 
+	"github.com/zenithax-cc/baize/internal/collector/smbios"
 	"github.com/zenithax-cc/baize/pkg/utils"
 )
 
 type collectTask struct {
-	name string
-	fn   func() error
+	name   string
+	fn     func() error
+	smbios bool // decoded from SMBIOS, so also valid for an SMBIOS dump
 }
 
 func New() *Product {
@@ -22,10 +25,16 @@ func (p *Product) Collect(ctx context.Context) error {
 	tasks := []collectTask{
 		{name: "kernel", fn: p.collectKernel},
 		{name: "distribution", fn: p.collectDistribution},
-		{name: "bios", fn: p.collectBIOS},
-		{name: "system", fn: p.collectSystem},
-		{name: "baseboard", fn: p.collectBaseBoard},
-		{name: "chassis", fn: p.collectChassis},
+		{name: "bios", fn: p.collectBIOS, smbios: true},
+		{name: "system", fn: p.collectSystem, smbios: true},
+		{name: "baseboard", fn: p.collectBaseBoard, smbios: true},
+		{name: "chassis", fn: p.collectChassis, smbios: true},
+		{name: "slots", fn: p.collectSlots, smbios: true},
+		{name: "firmware", fn: p.collectFirmware},
+	}
+	if smbios.FromDump() {
+		// Only the SMBIOS fields describe the dumped host.
+		tasks = slices.DeleteFunc(tasks, func(t collectTask) bool { return !t.smbios })
 	}
 
 	var (
@@ -89,6 +98,8 @@ func (p *Product) BriefPrintln() {
 		HostName:     p.OS.HostName,
 		BIOSVersion:  p.BIOS.Version,
 		BIOSDate:     p.BIOS.ReleaseDate,
+		BootMode:     p.Firmware.BootMode,
+		SecureBoot:   p.Firmware.SecureBoot,
 	}
 
 	// Build a user-friendly OS display string.
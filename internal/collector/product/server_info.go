@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/zenithax-cc/baize/internal/collector/pci"
 	"github.com/zenithax-cc/baize/internal/collector/smbios"
 )
 
@@ -88,6 +89,17 @@ func (p *Product) collectChassis() error {
 	})
 }
 
+// collectSlots lists the physical slots with the cards sitting in them.
+func (p *Product) collectSlots() error {
+	slots, err := pci.Slots()
+	if err != nil {
+		return fmt.Errorf("map slots to PCI devices: %w", err)
+	}
+	p.Slots = slots
+
+	return nil
+}
+
 func formatRevision(major, minor uint8) string {
 	buf := make([]byte, 0, 8)
 	buf = strconv.AppendUint(buf, uint64(major), 10)
@@ -1,5 +1,7 @@
 package product
 
+import "github.com/zenithax-cc/baize/internal/collector/pci"
+
 type OS struct {
 	KernelName    string `json:"kernel_name,omitempty" name:"OS Type" output:"both"`
 	KernelRelease string `json:"kernel_release,omitempty" name:"Kernel Release"`
@@ -59,12 +61,41 @@ type Chassis struct {
 	SKU              string `json:"sku_number,omitempty"`
 }
 
+// Firmware describes how the server booted and its Secure Boot posture,
+// read from efivarfs.
+type Firmware struct {
+	BootMode       string       `json:"boot_mode,omitempty" name:"Boot Mode" output:"both"`
+	SecureBoot     string       `json:"secure_boot,omitempty" name:"Secure Boot" output:"both"`
+	SetupMode      string       `json:"setup_mode,omitempty" name:"Setup Mode" output:"both"`
+	PK             string       `json:"pk,omitempty" name:"PK" output:"detail"`
+	KEK            string       `json:"kek,omitempty" name:"KEK" output:"detail"`
+	DB             string       `json:"db,omitempty" name:"db" output:"detail"`
+	DBX            string       `json:"dbx,omitempty" name:"dbx" output:"detail"`
+	BootCurrent    string       `json:"boot_current,omitempty" name:"Boot Current" output:"both"`
+	BootOrder      string       `json:"boot_order,omitempty" name:"Boot Order" output:"detail"`
+	BootEntries    []*BootEntry `json:"boot_entries,omitempty" name:"Boot Entry" output:"detail"`
+	Diagnose       string       `json:"diagnose,omitempty" name:"Diagnose" output:"both" color:"Diagnose"`
+	DiagnoseDetail string       `json:"diagnose_detail,omitempty" name:"Diagnose Detail" output:"both"`
+}
+
+// BootEntry is a decoded Boot#### load option.
+type BootEntry struct {
+	Name        string `json:"name,omitempty" name:"Name" output:"detail"`
+	Description string `json:"description,omitempty" name:"Description" output:"detail"`
+	Active      bool   `json:"active" name:"Active" output:"detail"`
+	Current     bool   `json:"current" name:"Current" output:"detail"`
+	DevicePath  string `json:"device_path,omitempty" name:"Device Path" output:"detail"`
+}
+
 type Product struct {
 	OS
 	BIOS      `json:"bios" name:"BIOS"`
 	System    `json:"system" name:"System"`
 	BaseBoard `json:"base_board" name:"Baseboard"`
 	Chassis   `json:"chassis" name:"Chassis"`
+	Firmware  `json:"firmware" name:"Firmware"`
+
+	Slots []*pci.Slot `json:"slots,omitempty" name:"Slot" output:"detail"`
 }
 
 // ProductBrief is a flattened view used for brief terminal output,
@@ -81,4 +112,6 @@ type ProductBrief struct {
 	BIOSVersion  string `json:"-" name:"BIOS Version" output:"both"`
 	BIOSDate     string `json:"-" name:"BIOS Date" output:"both"`
 	HostName     string `json:"-" name:"Hostname" output:"both" color:"DefaultGreen"`
+	BootMode     string `json:"-" name:"Boot Mode" output:"both"`
+	SecureBoot   string `json:"-" name:"Secure Boot" output:"both"`
 }
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/zenithax-cc/baize/internal/collector/smbios"
 	"github.com/zenithax-cc/baize/pkg/execute"
 	"github.com/zenithax-cc/baize/pkg/utils"
 )
@@ -37,12 +38,12 @@ func collectAdaptec(ctx context.Context, i int, c *controller) error {
 		ctrl: c,
 	}
 
-	output := execute.Command("dmidecode", "-s", "system-manufacturer")
-	if output.Err != nil {
-		return output.Err
+	manufacturer, err := smbios.GetString("system-manufacturer")
+	if err != nil {
+		return err
 	}
 
-	if bytes.HasPrefix(bytes.TrimSpace(output.Stdout), []byte("HP")) {
+	if len(manufacturer) > 0 && strings.HasPrefix(strings.TrimSpace(manufacturer[0]), "HP") {
 		return collectHPE(ctx, i, c)
 	}
 
@@ -50,7 +51,7 @@ func collectAdaptec(ctx context.Context, i int, c *controller) error {
 		return fmt.Errorf("adaptec controller %s not found", c.PCIe.PCIAddr)
 	}
 
-	err := arcCtr.collect(ctx)
+	err = arcCtr.collect(ctx)
 	arcCtr.associate()
 
 	return err
@@ -113,6 +113,9 @@ func (c *Controllers) collectController(ctx context.Context) error {
 			}
 		}
 
+		// Surface a degraded link or AER errors of the controller.
+		ctr.PCIe.MergeDiagnose(&ctr.Diagnose, &ctr.DiagnoseDetail)
+
 		c.Controller = append(c.Controller, ctr)
 	}
 
@@ -161,6 +164,9 @@ func (c *Controllers) collectNVMe(ctx context.Context) error {
 			errs = append(errs, fmt.Errorf("collect NVMe %s failed: %w", n, err))
 		}
 
+		// Surface a degraded link or AER errors of the drive.
+		nv.PCIe.MergeDiagnose(&nv.Diagnose, &nv.DiagnoseDetail)
+
 		c.NVMe = append(c.NVMe, nv)
 	}
 
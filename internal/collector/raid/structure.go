@@ -10,7 +10,7 @@ import (
 // (vendor RAID cards) and directly-attached NVMe drives.
 type Controllers struct {
 	Controller []*controller `json:"controller,omitempty" name:"Controller" output:"both"`
-	NVMe       []*nvme       `json:"nvme,omitempty" name:"NVMe"`
+	NVMe       []*nvme       `json:"nvme,omitempty" name:"NVMe" output:"detail"`
 }
 
 // controller holds all information for a single RAID controller card,
@@ -0,0 +1,309 @@
+// Package rasdaemon reads the event database written by rasdaemon
+// (/var/lib/rasdaemon/ras-mc_event.db). The database is opened read-only with
+// the pure-Go reader in pkg/sqlite, so neither cgo nor the sqlite3 or
+// ras-mc-ctl tools are required. Unlike the EDAC and AER sysfs counters, the
+// database keeps time-stamped events across reboots.
+package rasdaemon
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zenithax-cc/baize/pkg/sqlite"
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+// DefaultDBPath is the location of the rasdaemon event database.
+const DefaultDBPath = "/var/lib/rasdaemon/ras-mc_event.db"
+
+// legacyDBPath is the name used by some distribution packages.
+const legacyDBPath = "/var/lib/rasdaemon/ras-mc_ctx.db"
+
+// timestampLayout is the format of the timestamp column of every table.
+const timestampLayout = "2006-01-02 15:04:05 -0700"
+
+const (
+	tableMCERecord = "mce_record"
+	tableMCEvent   = "mc_event"
+	tableAEREvent  = "aer_event"
+	tableExtlog    = "extlog_event"
+)
+
+// ErrNoDatabase is returned when the rasdaemon database does not exist.
+var ErrNoDatabase = errors.New("rasdaemon database not found")
+
+// dbPaths are tried in order; SetDBPath replaces them.
+var dbPaths = []string{DefaultDBPath, legacyDBPath}
+
+// SetDBPath overrides the location of the rasdaemon database, e.g. for offline
+// analysis of a copied database.
+func SetDBPath(path string) {
+	dbPaths = []string{path}
+}
+
+// MCERecord is one row of the mce_record table.
+type MCERecord struct {
+	ID           int64
+	Timestamp    string
+	MCGCap       uint64
+	MCGStatus    uint64
+	Status       uint64
+	Addr         uint64
+	Misc         uint64
+	IP           uint64
+	TSC          uint64
+	Walltime     uint64
+	CPU          int
+	CPUID        uint64
+	APICID       int
+	SocketID     int
+	CS           int
+	Bank         int
+	CPUVendor    int
+	BankName     string
+	ErrorMsg     string
+	MCGStatusMsg string
+	MCIStatusMsg string
+	MCAStatusMsg string
+	UserAction   string
+	MCLocation   string
+}
+
+// MCEvent is one row of the mc_event table, an EDAC memory controller error.
+type MCEvent struct {
+	ID        int64
+	Timestamp string
+	// Time is Timestamp parsed; zero when it cannot be parsed.
+	Time     time.Time
+	ErrCount int
+	// ErrType is "Corrected", "Uncorrected", "Fatal", "Deferred" or "Info".
+	ErrType string
+	ErrMsg  string
+	// Label is the EDAC DIMM label; several candidates are joined by " or ".
+	Label       string
+	MC          int
+	TopLayer    int
+	MiddleLayer int
+	LowerLayer  int
+	Address     uint64
+	Grain       uint64
+	Syndrome    uint64
+	// DriverDetail is the EDAC driver's decoding (channel, rank, bank, row, ...).
+	DriverDetail string
+}
+
+// AEREvent is one row of the aer_event table, a PCIe Advanced Error
+// Reporting event.
+type AEREvent struct {
+	ID        int64
+	Timestamp string
+	Time      time.Time
+	// DevName is the PCI address of the reporting device.
+	DevName string
+	// ErrType is "Corrected", "Uncorrected (Non-Fatal)" or "Uncorrected (Fatal)".
+	ErrType string
+	ErrMsg  string
+}
+
+// ExtlogEvent is one row of the extlog_event table, a memory error reported by
+// firmware through the ACPI extended error log.
+type ExtlogEvent struct {
+	ID         int64
+	Timestamp  string
+	Time       time.Time
+	EType      int
+	ErrorCount int
+	// Severity is the CPER severity (0 recoverable, 1 fatal, 2 corrected, 3 informational).
+	Severity int
+	Address  uint64
+	// FRUText is the field replaceable unit named by firmware, usually the DIMM silkscreen.
+	FRUText string
+}
+
+// MCEvents returns the EDAC memory controller events, oldest first.
+func MCEvents() ([]*MCEvent, error) {
+	rows, err := readTable(tableMCEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*MCEvent, 0, len(rows))
+	for _, row := range rows {
+		ts := stringValue(row, "timestamp")
+		res = append(res, &MCEvent{
+			ID:           intValue(row, "id"),
+			Timestamp:    ts,
+			Time:         parseTime(ts),
+			ErrCount:     int(intValue(row, "err_count")),
+			ErrType:      stringValue(row, "err_type"),
+			ErrMsg:       stringValue(row, "err_msg"),
+			Label:        stringValue(row, "label"),
+			MC:           int(intValue(row, "mc")),
+			TopLayer:     int(intValue(row, "top_layer")),
+			MiddleLayer:  int(intValue(row, "middle_layer")),
+			LowerLayer:   int(intValue(row, "lower_layer")),
+			Address:      uint64(intValue(row, "address")),
+			Grain:        uint64(intValue(row, "grain")),
+			Syndrome:     uint64(intValue(row, "syndrome")),
+			DriverDetail: stringValue(row, "driver_detail"),
+		})
+	}
+
+	return res, nil
+}
+
+// AEREvents returns the PCIe AER events, oldest first.
+func AEREvents() ([]*AEREvent, error) {
+	rows, err := readTable(tableAEREvent)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*AEREvent, 0, len(rows))
+	for _, row := range rows {
+		ts := stringValue(row, "timestamp")
+		res = append(res, &AEREvent{
+			ID:        intValue(row, "id"),
+			Timestamp: ts,
+			Time:      parseTime(ts),
+			DevName:   stringValue(row, "dev_name"),
+			ErrType:   stringValue(row, "err_type"),
+			ErrMsg:    stringValue(row, "err_msg"),
+		})
+	}
+
+	return res, nil
+}
+
+// ExtlogEvents returns the firmware extended log memory events, oldest first.
+func ExtlogEvents() ([]*ExtlogEvent, error) {
+	rows, err := readTable(tableExtlog)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*ExtlogEvent, 0, len(rows))
+	for _, row := range rows {
+		ts := stringValue(row, "timestamp")
+		res = append(res, &ExtlogEvent{
+			ID:         intValue(row, "id"),
+			Timestamp:  ts,
+			Time:       parseTime(ts),
+			EType:      int(intValue(row, "etype")),
+			ErrorCount: int(intValue(row, "error_count")),
+			Severity:   int(intValue(row, "severity")),
+			Address:    uint64(intValue(row, "address")),
+			FRUText:    stringValue(row, "fru_text"),
+		})
+	}
+
+	return res, nil
+}
+
+// MCERecords returns every machine check recorded by rasdaemon, oldest first.
+func MCERecords() ([]*MCERecord, error) {
+	rows, err := readTable(tableMCERecord)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*MCERecord, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, &MCERecord{
+			ID:           intValue(row, "id"),
+			Timestamp:    stringValue(row, "timestamp"),
+			MCGCap:       uint64(intValue(row, "mcgcap")),
+			MCGStatus:    uint64(intValue(row, "mcgstatus")),
+			Status:       uint64(intValue(row, "status")),
+			Addr:         uint64(intValue(row, "addr")),
+			Misc:         uint64(intValue(row, "misc")),
+			IP:           uint64(intValue(row, "ip")),
+			TSC:          uint64(intValue(row, "tsc")),
+			Walltime:     uint64(intValue(row, "walltime")),
+			CPU:          int(intValue(row, "cpu")),
+			CPUID:        uint64(intValue(row, "cpuid")),
+			APICID:       int(intValue(row, "apicid")),
+			SocketID:     int(intValue(row, "socketid")),
+			CS:           int(intValue(row, "cs")),
+			Bank:         int(intValue(row, "bank")),
+			CPUVendor:    int(intValue(row, "cpuvendor")),
+			BankName:     stringValue(row, "bank_name"),
+			ErrorMsg:     stringValue(row, "error_msg"),
+			MCGStatusMsg: stringValue(row, "mcgstatus_msg"),
+			MCIStatusMsg: stringValue(row, "mcistatus_msg"),
+			MCAStatusMsg: stringValue(row, "mcastatus_msg"),
+			UserAction:   stringValue(row, "user_action"),
+			MCLocation:   stringValue(row, "mc_location"),
+		})
+	}
+
+	return res, nil
+}
+
+// readTable returns all rows of a table. A missing database yields
+// ErrNoDatabase; a database without the table (the feature was compiled out
+// of rasdaemon) yields no rows.
+func readTable(table string) ([]sqlite.Row, error) {
+	path := ""
+	for _, p := range dbPaths {
+		if utils.FileExists(p) {
+			path = p
+			break
+		}
+	}
+	if path == "" {
+		return nil, fmt.Errorf("%w: %s", ErrNoDatabase, strings.Join(dbPaths, ", "))
+	}
+
+	db, err := sqlite.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	if !db.HasTable(table) {
+		return nil, nil
+	}
+
+	return db.Rows(table)
+}
+
+// parseTime parses a timestamp column.
+func parseTime(ts string) time.Time {
+	t, err := time.Parse(timestampLayout, ts)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// intValue returns an integer column. rasdaemon stores 64-bit registers as
+// signed INTEGER, so callers convert to uint64 where appropriate.
+func intValue(row sqlite.Row, col string) int64 {
+	switch v := row[col].(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 0, 64)
+		return n
+	}
+	return 0
+}
+
+// stringValue returns a text column.
+func stringValue(row sqlite.Row, col string) string {
+	switch v := row[col].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	}
+	return ""
+}
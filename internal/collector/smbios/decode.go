@@ -19,29 +19,61 @@ var (
 type parseFunc func(*Table) (any, error)
 
 var parsers = map[TableType]parseFunc{
-	BIOS:         func(t *Table) (any, error) { return parseType0BIOS(t) },
-	System:       func(t *Table) (any, error) { return parseType1System(t) },
-	BaseBoard:    func(t *Table) (any, error) { return parseType2BaseBoard(t) },
-	Chassis:      func(t *Table) (any, error) { return parseType3Chassis(t) },
-	Processor:    func(t *Table) (any, error) { return parseType4Processor(t) },
-	MemoryDevice: func(t *Table) (any, error) { return parseType17MemoryDevice(t) },
+	BIOS:                              func(t *Table) (any, error) { return parseType0BIOS(t) },
+	System:                            func(t *Table) (any, error) { return parseType1System(t) },
+	BaseBoard:                         func(t *Table) (any, error) { return parseType2BaseBoard(t) },
+	Chassis:                           func(t *Table) (any, error) { return parseType3Chassis(t) },
+	Processor:                         func(t *Table) (any, error) { return parseType4Processor(t) },
+	PortConnector:                     func(t *Table) (any, error) { return parseType8PortConnector(t) },
+	SystemSlots:                       func(t *Table) (any, error) { return parseType9SystemSlots(t) },
+	OEMStrings:                        func(t *Table) (any, error) { return parseType11OEMStrings(t) },
+	BIOSLanguage:                      func(t *Table) (any, error) { return parseType13BIOSLanguage(t) },
+	PhysicalMemoryArray:               func(t *Table) (any, error) { return parseType16PhysicalMemoryArray(t) },
+	MemoryDevice:                      func(t *Table) (any, error) { return parseType17MemoryDevice(t) },
+	MemoryArrayMappedAddress:          func(t *Table) (any, error) { return parseType19MemoryArrayMappedAddress(t) },
+	MemoryDeviceMappedAddress:         func(t *Table) (any, error) { return parseType20MemoryDeviceMappedAddress(t) },
+	IPMIDevice:                        func(t *Table) (any, error) { return parseType38IPMIDevice(t) },
+	PowerSupply:                       func(t *Table) (any, error) { return parseType39SystemPowerSupply(t) },
+	OnBoardDevicesExtendedInformation: func(t *Table) (any, error) { return parseType41OnboardDevicesExtended(t) },
+	TPMDevice:                         func(t *Table) (any, error) { return parseType43TPMDevice(t) },
 }
 
 type Decoder struct {
 	EntryPoint EntryPoint
-	tables     map[TableType][]*Table
-	parsers    map[TableType]parseFunc
-	cache      map[TableType][]any
-	mu         sync.RWMutex
+	// all holds every structure in table order and source where they were read from.
+	all     []*Table
+	source  string
+	tables  map[TableType][]*Table
+	parsers map[TableType]parseFunc
+	cache   map[TableType][]any
+	mu      sync.RWMutex
 }
 
-var decodeOnce sync.Once
+var (
+	decoder    *Decoder
+	decoderErr error
+	decodeOnce sync.Once
+
+	// dumpFile, when set, is read instead of the running host's SMBIOS.
+	dumpFile string
+)
+
+// SetDumpFile makes the package decode an SMBIOS dump, see NewFileReader,
+// instead of the running host. It must be called before any table is read.
+func SetDumpFile(path string) {
+	dumpFile = path
+}
+
+// FromDump reports whether the tables come from a dump rather than this host,
+// in which case host-local sources must not be mixed into the result.
+func FromDump() bool {
+	return dumpFile != ""
+}
 
+// New returns the package decoder, reading the SMBIOS tables on first use.
 func New(ctx context.Context) (*Decoder, error) {
-	var d *Decoder
-	var err error
-	decodeOnce.Do(func() { d, err = getDecoder(ctx) })
-	return d, err
+	decodeOnce.Do(func() { decoder, decoderErr = getDecoder(ctx) })
+	return decoder, decoderErr
 }
 
 func getDecoder(ctx context.Context) (*Decoder, error) {
@@ -51,12 +83,14 @@ func getDecoder(ctx context.Context) (*Decoder, error) {
 		cache:   make(map[TableType][]any, len(parsers)),
 	}
 
-	ep, tables, err := smbiosReader(ctx)
+	ep, tables, source, err := smbiosReader(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrSMBIOSFailed, err)
 	}
 
 	d.EntryPoint = ep
+	d.all = tables
+	d.source = source
 	for _, t := range tables {
 		if _, ok := parsers[TableType(t.Type)]; ok {
 			d.tables[TableType(t.Type)] = append(d.tables[TableType(t.Type)], t)
@@ -100,19 +134,13 @@ func (d *Decoder) getParserData(t TableType) ([]any, error) {
 	return res, utils.CombineErrors(errs)
 }
 
-var decoder *Decoder
-
-func init() {
-	var err error
-	decoder, err = New(context.Background())
+func GetTypeData[T any](t TableType) ([]T, error) {
+	d, err := New(context.Background())
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-}
-
-func GetTypeData[T any](t TableType) ([]T, error) {
 
-	data, err := decoder.getParserData(t)
+	data, err := d.getParserData(t)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,1076 @@
+package smbios
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Dmidecode writes the SMBIOS structures in the text layout of dmidecode, so
+// that scripts parsing dmidecode output keep working. types restricts the
+// output to the given structure types; nil prints every structure.
+// Structures baize does not decode are printed as a hex dump, as dmidecode
+// does for unknown and OEM types.
+func Dmidecode(w io.Writer, types []TableType) error {
+	d, err := New(context.Background())
+	if err != nil {
+		return err
+	}
+	return d.dmidecode(w, types)
+}
+
+func (d *Decoder) dmidecode(w io.Writer, types []TableType) error {
+	dw := &dmiWriter{w: w}
+
+	dw.printf("# dmidecode-compatible output generated by baize\n")
+	switch d.source {
+	case sourceSysfs:
+		dw.printf("Getting SMBIOS data from sysfs.\n")
+	case sourceDevMem:
+		dw.printf("Scanning %s for entry point.\n", devMem)
+	default:
+		dw.printf("Reading SMBIOS/DMI data from file %s.\n", d.source)
+	}
+
+	if d.EntryPoint != nil {
+		dw.printf("SMBIOS %s present.\n", d.EntryPoint.Version())
+		if ep, ok := d.EntryPoint.(*entryPoint32); ok {
+			dw.printf("%d structures occupying %d bytes.\n", ep.NumberOfStructures, ep.TableLength)
+		}
+		addr, _ := d.EntryPoint.Table()
+		dw.printf("Table at 0x%08X.\n", addr)
+	}
+	dw.printf("\n")
+
+	for _, t := range d.all {
+		tt := TableType(t.Type)
+		if len(types) > 0 && !slices.Contains(types, tt) {
+			if tt == EndOfTable {
+				break
+			}
+			continue
+		}
+
+		dw.printf("Handle 0x%04X, DMI type %d, %d bytes\n", t.Handle, t.Type, t.Length)
+		d.dmiTable(dw, t)
+		dw.printf("\n")
+
+		if tt == EndOfTable {
+			break
+		}
+	}
+
+	return dw.err
+}
+
+// dmiTable prints one structure through its renderer, or as a hex dump when
+// it is not decoded or fails to parse.
+func (d *Decoder) dmiTable(dw *dmiWriter, t *Table) {
+	tt := TableType(t.Type)
+	if render, ok := dmiRenderers[tt]; ok {
+		if parser, ok := d.parsers[tt]; ok {
+			if v, err := parser(t); err == nil && v != nil {
+				render(dw, v)
+				return
+			}
+		}
+	}
+
+	dw.printf("%s\n", dmiTypeTitle(tt))
+	if tt == Inactive || tt == EndOfTable {
+		return
+	}
+	dw.hexDump(t)
+}
+
+// dmiWriter writes dmidecode-style attributes and keeps the first write error.
+type dmiWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (dw *dmiWriter) printf(format string, a ...any) {
+	if dw.err != nil {
+		return
+	}
+	_, dw.err = fmt.Fprintf(dw.w, format, a...)
+}
+
+// attr prints "\tName: value".
+func (dw *dmiWriter) attr(name, format string, a ...any) {
+	dw.printf("\t%s: %s\n", name, fmt.Sprintf(format, a...))
+}
+
+// list prints a multi-line attribute, or "None" when it has no items.
+func (dw *dmiWriter) list(name string, items []string) {
+	if len(items) == 0 {
+		dw.attr(name, "None")
+		return
+	}
+	dw.printf("\t%s:\n", name)
+	for _, item := range items {
+		dw.printf("\t\t%s\n", item)
+	}
+}
+
+// hexDump prints the raw structure including its header, and its strings.
+func (dw *dmiWriter) hexDump(t *Table) {
+	data := append([]byte{t.Type, t.Length, byte(t.Handle), byte(t.Handle >> 8)}, t.FormattedArea...)
+
+	dw.printf("\tHeader and Data:\n")
+	for i := 0; i < len(data); i += 16 {
+		end := min(i+16, len(data))
+		hex := make([]string, 0, 16)
+		for _, b := range data[i:end] {
+			hex = append(hex, fmt.Sprintf("%02X", b))
+		}
+		dw.printf("\t\t%s\n", strings.Join(hex, " "))
+	}
+
+	if len(t.StringArea) > 0 {
+		dw.printf("\tStrings:\n")
+		for _, s := range t.StringArea {
+			dw.printf("\t\t%s\n", s)
+		}
+	}
+}
+
+// dmiString returns s, or "Not Specified" for an absent string.
+func dmiString(s string) string {
+	if s == "" {
+		return "Not Specified"
+	}
+	return s
+}
+
+// dmiSize formats a byte count in the largest exact unit, as dmidecode does.
+func dmiSize(v uint64) string {
+	return strings.Replace(kgmt(v), " KB", " kB", 1)
+}
+
+// dmiSpeed formats a speed in the given unit; 0 is unknown.
+func dmiSpeed(v uint32, unit string) string {
+	if v == 0 {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%d %s", v, unit)
+}
+
+// dmiOptionalHandle formats a handle that may be 0xFFFF for "not provided".
+func dmiOptionalHandle(h uint16) string {
+	if h == 0xFFFF {
+		return "Not Provided"
+	}
+	return fmt.Sprintf("0x%04X", h)
+}
+
+// dmiErrorHandle formats a memory error information handle.
+func dmiErrorHandle(h uint16) string {
+	switch h {
+	case 0xFFFE:
+		return "Not Provided"
+	case 0xFFFF:
+		return "No Error"
+	}
+	return fmt.Sprintf("0x%04X", h)
+}
+
+var dmiTypeTitles = map[TableType]string{
+	BIOS:                              "BIOS Information",
+	System:                            "System Information",
+	BaseBoard:                         "Base Board Information",
+	Chassis:                           "Chassis Information",
+	Processor:                         "Processor Information",
+	Controller:                        "Memory Controller Information",
+	Module:                            "Memory Module Information",
+	Cache:                             "Cache Information",
+	PortConnector:                     "Port Connector Information",
+	SystemSlots:                       "System Slot Information",
+	OnBoardDevices:                    "On Board Device Information",
+	OEMStrings:                        "OEM Strings",
+	SystemConfigurationOptions:        "System Configuration Options",
+	BIOSLanguage:                      "BIOS Language Information",
+	GroupAssociations:                 "Group Associations",
+	SystemEventLog:                    "System Event Log",
+	PhysicalMemoryArray:               "Physical Memory Array",
+	MemoryDevice:                      "Memory Device",
+	Bit32MemoryError:                  "32-bit Memory Error Information",
+	MemoryArrayMappedAddress:          "Memory Array Mapped Address",
+	MemoryDeviceMappedAddress:         "Memory Device Mapped Address",
+	BuiltInPointingDevice:             "Built-in Pointing Device",
+	PortableBattery:                   "Portable Battery",
+	SystemReset:                       "System Reset",
+	HardwareSecurity:                  "Hardware Security",
+	SystemPowerControls:               "System Power Controls",
+	VoltageProbe:                      "Voltage Probe",
+	CoolingDevice:                     "Cooling Device",
+	TemperatureProbe:                  "Temperature Probe",
+	ElectricalCurrentProbe:            "Electrical Current Probe",
+	OutOfBandRemoteAccess:             "Out-of-band Remote Access",
+	BootIntegrityServices:             "Boot Integrity Services Entry Point",
+	SystemBoot:                        "System Boot Information",
+	Bit64MemoryError:                  "64-bit Memory Error Information",
+	ManagementDevice:                  "Management Device",
+	ManagementDeviceComponent:         "Management Device Component",
+	ManagementDeviceThresholdData:     "Management Device Threshold Data",
+	MemoryChannel:                     "Memory Channel",
+	IPMIDevice:                        "IPMI Device Information",
+	PowerSupply:                       "System Power Supply",
+	AdditionalInformation:             "Additional Information",
+	OnBoardDevicesExtendedInformation: "Onboard Device",
+	ManagementControllerHostInterface: "Management Controller Host Interface",
+	TPMDevice:                         "TPM Device",
+	44:                                "Processor Additional Information",
+	45:                                "Firmware Inventory Information",
+	46:                                "String Property",
+	Inactive:                          "Inactive",
+	EndOfTable:                        "End Of Table",
+}
+
+func dmiTypeTitle(t TableType) string {
+	if title, ok := dmiTypeTitles[t]; ok {
+		return title
+	}
+	if t >= 128 {
+		return "OEM-specific Type"
+	}
+	return "Unknown Type"
+}
+
+var dmiRenderers = map[TableType]func(*dmiWriter, any){
+	BIOS:                              func(dw *dmiWriter, v any) { dmiBIOS(dw, v.(*Type0BIOS)) },
+	System:                            func(dw *dmiWriter, v any) { dmiSystem(dw, v.(*Type1System)) },
+	BaseBoard:                         func(dw *dmiWriter, v any) { dmiBaseBoard(dw, v.(*Type2BaseBoard)) },
+	Chassis:                           func(dw *dmiWriter, v any) { dmiChassis(dw, v.(*Type3Chassis)) },
+	Processor:                         func(dw *dmiWriter, v any) { dmiProcessor(dw, v.(*Type4Processor)) },
+	PortConnector:                     func(dw *dmiWriter, v any) { dmiPortConnector(dw, v.(*Type8PortConnector)) },
+	SystemSlots:                       func(dw *dmiWriter, v any) { dmiSystemSlots(dw, v.(*Type9SystemSlots)) },
+	OEMStrings:                        func(dw *dmiWriter, v any) { dmiOEMStrings(dw, v.(*Type11OEMStrings)) },
+	BIOSLanguage:                      func(dw *dmiWriter, v any) { dmiBIOSLanguage(dw, v.(*Type13BIOSLanguage)) },
+	PhysicalMemoryArray:               func(dw *dmiWriter, v any) { dmiPhysicalMemoryArray(dw, v.(*Type16PhysicalMemoryArray)) },
+	MemoryDevice:                      func(dw *dmiWriter, v any) { dmiMemoryDevice(dw, v.(*Type17MemoryDevice)) },
+	MemoryArrayMappedAddress:          func(dw *dmiWriter, v any) { dmiMemoryArrayMappedAddress(dw, v.(*Type19MemoryArrayMappedAddress)) },
+	MemoryDeviceMappedAddress:         func(dw *dmiWriter, v any) { dmiMemoryDeviceMappedAddress(dw, v.(*Type20MemoryDeviceMappedAddress)) },
+	IPMIDevice:                        func(dw *dmiWriter, v any) { dmiIPMIDevice(dw, v.(*Type38IPMIDevice)) },
+	PowerSupply:                       func(dw *dmiWriter, v any) { dmiPowerSupply(dw, v.(*Type39SystemPowerSupply)) },
+	OnBoardDevicesExtendedInformation: func(dw *dmiWriter, v any) { dmiOnboardDevice(dw, v.(*Type41OnboardDevicesExtended)) },
+	TPMDevice:                         func(dw *dmiWriter, v any) { dmiTPMDevice(dw, v.(*Type43TPMDevice)) },
+}
+
+// dmiBIOSCharsExt2 uses the dmidecode wording, which differs from biosCharExt2Map.
+var dmiBIOSCharsExt2 = []string{
+	"BIOS boot specification is supported",
+	"Function key-initiated network boot is supported",
+	"Targeted content distribution is supported",
+	"UEFI is supported",
+	"System is a virtual machine",
+	"Manufacturing mode is supported",
+	"Manufacturing mode is enabled",
+}
+
+func dmiBIOS(dw *dmiWriter, b *Type0BIOS) {
+	dw.printf("%s\n", dmiTypeTitle(BIOS))
+	dw.attr("Vendor", "%s", dmiString(b.Vendor))
+	dw.attr("Version", "%s", dmiString(b.Version))
+	dw.attr("Release Date", "%s", dmiString(b.ReleaseDate))
+	if b.AddressSegment != 0 {
+		dw.attr("Address", "0x%04X0", b.AddressSegment)
+		size := (0x10000 - uint32(b.AddressSegment)) << 4
+		if size&0x3FF != 0 {
+			dw.attr("Runtime Size", "%d bytes", size)
+		} else {
+			dw.attr("Runtime Size", "%d kB", size>>10)
+		}
+	}
+	dw.attr("ROM Size", "%s", strings.Replace(b.GetROMSize(), " KB", " kB", 1))
+
+	var chars []string
+	if b.Characteristics&BIOSCharsAreNotSupported != 0 {
+		chars = append(chars, biosCharMap[BIOSCharsAreNotSupported])
+	} else {
+		for i := 4; i < 32; i++ {
+			if b.Characteristics&(1<<i) != 0 {
+				chars = append(chars, biosCharMap[1<<i])
+			}
+		}
+		if b.Length >= 0x13 {
+			for i := 0; i < 8; i++ {
+				if b.CharacteristicsExt1&(1<<i) != 0 {
+					chars = append(chars, biosCharExt1Map[1<<i])
+				}
+			}
+		}
+		if b.Length >= 0x14 {
+			chars = append(chars, bitNames(uint8(b.CharacteristicsExt2), dmiBIOSCharsExt2)...)
+		}
+	}
+	dw.list("Characteristics", chars)
+
+	if b.Length < 0x18 {
+		return
+	}
+	if b.BIOSMajorRelease != 0xFF && b.BIOSMinorRelease != 0xFF {
+		dw.attr("BIOS Revision", "%d.%d", b.BIOSMajorRelease, b.BIOSMinorRelease)
+	}
+	if b.ECMajorRelease != 0xFF && b.ECMinorRelease != 0xFF {
+		dw.attr("Firmware Revision", "%d.%d", b.ECMajorRelease, b.ECMinorRelease)
+	}
+}
+
+// dmiUUID formats the system UUID in upper case.
+func dmiUUID(u UUID) string {
+	s := u.String()
+	if s == uuidNotPresent || s == uuidNotSettable {
+		return s
+	}
+	return strings.ToUpper(s)
+}
+
+func dmiSystem(dw *dmiWriter, s *Type1System) {
+	dw.printf("%s\n", dmiTypeTitle(System))
+	dw.attr("Manufacturer", "%s", dmiString(s.Manufacturer))
+	dw.attr("Product Name", "%s", dmiString(s.ProductName))
+	dw.attr("Version", "%s", dmiString(s.Version))
+	dw.attr("Serial Number", "%s", dmiString(s.SerialNumber))
+	if s.Length < 0x19 {
+		return
+	}
+	dw.attr("UUID", "%s", dmiUUID(s.UUID))
+	dw.attr("Wake-up Type", "%s", s.WakeUpType)
+	if s.Length < 0x1B {
+		return
+	}
+	dw.attr("SKU Number", "%s", dmiString(s.SKU))
+	dw.attr("Family", "%s", dmiString(s.Family))
+}
+
+// dmiBoardTypes uses the dmidecode wording, which differs from boardTypeStr.
+var dmiBoardTypes = []string{
+	"",
+	"Unknown",
+	"Other",
+	"Server Blade",
+	"Connectivity Switch",
+	"System Management Module",
+	"Processor Module",
+	"I/O Module",
+	"Memory Module",
+	"Daughter Board",
+	"Motherboard",
+	"Processor+Memory Module",
+	"Processor+I/O Module",
+	"Interconnect Board",
+}
+
+func dmiBaseBoard(dw *dmiWriter, b *Type2BaseBoard) {
+	dw.printf("%s\n", dmiTypeTitle(BaseBoard))
+	dw.attr("Manufacturer", "%s", dmiString(b.Manufacturer))
+	dw.attr("Product Name", "%s", dmiString(b.Product))
+	dw.attr("Version", "%s", dmiString(b.Version))
+	dw.attr("Serial Number", "%s", dmiString(b.SerialNumber))
+	if b.Length < 0x09 {
+		return
+	}
+	dw.attr("Asset Tag", "%s", dmiString(b.AssetTag))
+	if b.Length < 0x0A {
+		return
+	}
+	var features []string
+	for i := 0; i < 5; i++ {
+		if b.FeatureFlags&(1<<i) != 0 {
+			features = append(features, boardFeatureStr[1<<i])
+		}
+	}
+	dw.list("Features", features)
+	if b.Length < 0x0E {
+		return
+	}
+	dw.attr("Location In Chassis", "%s", dmiString(b.Location))
+	dw.attr("Chassis Handle", "0x%04X", b.ChassisHandle)
+	dw.attr("Type", "%s", indexName(int(b.BoardType), dmiBoardTypes))
+	if b.Length < 0x0F {
+		return
+	}
+	dw.attr("Contained Object Handles", "%d", len(b.ObjectHandles))
+	for _, h := range b.ObjectHandles {
+		dw.printf("\t\t0x%04X\n", h)
+	}
+}
+
+func dmiChassis(dw *dmiWriter, c *Type3Chassis) {
+	dw.printf("%s\n", dmiTypeTitle(Chassis))
+	dw.attr("Manufacturer", "%s", dmiString(c.Manufacturer))
+	dw.attr("Type", "%s", c.ChassisType)
+	if c.ChassisType&0x80 != 0 {
+		dw.attr("Lock", "Present")
+	} else {
+		dw.attr("Lock", "Not Present")
+	}
+	dw.attr("Version", "%s", dmiString(c.Version))
+	dw.attr("Serial Number", "%s", dmiString(c.SerialNumber))
+	dw.attr("Asset Tag", "%s", dmiString(c.AssetTag))
+	if c.Length < 0x0D {
+		return
+	}
+	dw.attr("Boot-up State", "%s", c.BootupState)
+	dw.attr("Power Supply State", "%s", c.PowerSupplyState)
+	dw.attr("Thermal State", "%s", c.ThermalState)
+	dw.attr("Security Status", "%s", c.SecurityStatus)
+	if c.Length < 0x11 {
+		return
+	}
+	dw.attr("OEM Information", "0x%08X", c.OEMDefined)
+	if c.Length < 0x13 {
+		return
+	}
+	if c.Height == 0 {
+		dw.attr("Height", "Unspecified")
+	} else {
+		dw.attr("Height", "%d U", c.Height)
+	}
+	if c.NumberOfPowerCords == 0 {
+		dw.attr("Number Of Power Cords", "Unspecified")
+	} else {
+		dw.attr("Number Of Power Cords", "%d", c.NumberOfPowerCords)
+	}
+	if c.Length < 0x15 {
+		return
+	}
+	dw.attr("Contained Elements", "%d", len(c.ContainedElements))
+	for _, e := range c.ContainedElements {
+		// Bit 7 selects an SMBIOS structure type, otherwise a board type.
+		name := indexName(int(e.Type&0x7F), dmiBoardTypes)
+		if e.Type&0x80 != 0 {
+			name = dmiTypeTitle(TableType(e.Type & 0x7F))
+		}
+		if e.Min == e.Max {
+			dw.printf("\t\t%s (%d)\n", name, e.Min)
+		} else {
+			dw.printf("\t\t%s (%d-%d)\n", name, e.Min, e.Max)
+		}
+	}
+	if int(c.Length) > 0x15+3*len(c.ContainedElements) {
+		dw.attr("SKU Number", "%s", dmiString(c.SKU))
+	}
+}
+
+// dmiCPUIDFlags names the CPUID leaf 1 EDX feature bits; empty names are reserved.
+var dmiCPUIDFlags = []string{
+	"FPU (Floating-point unit on-chip)",
+	"VME (Virtual mode extension)",
+	"DE (Debugging extension)",
+	"PSE (Page size extension)",
+	"TSC (Time stamp counter)",
+	"MSR (Model specific registers)",
+	"PAE (Physical address extension)",
+	"MCE (Machine check exception)",
+	"CX8 (CMPXCHG8 instruction supported)",
+	"APIC (On-chip APIC hardware supported)",
+	"",
+	"SEP (Fast system call)",
+	"MTRR (Memory type range registers)",
+	"PGE (Page global enable)",
+	"MCA (Machine check architecture)",
+	"CMOV (Conditional move instruction supported)",
+	"PAT (Page attribute table)",
+	"PSE-36 (36-bit page size extension)",
+	"PSN (Processor serial number present and enabled)",
+	"CLFSH (CLFLUSH instruction supported)",
+	"",
+	"DS (Debug store)",
+	"ACPI (ACPI supported)",
+	"MMX (MMX technology supported)",
+	"FXSR (FXSAVE and FXSTOR instructions supported)",
+	"SSE (Streaming SIMD extensions)",
+	"SSE2 (Streaming SIMD extensions 2)",
+	"SS (Self-snoop)",
+	"HTT (Multi-threading)",
+	"TM (Thermal monitor supported)",
+	"",
+	"PBE (Pending break enabled)",
+}
+
+func dmiProcessor(dw *dmiWriter, p *Type4Processor) {
+	dw.printf("%s\n", dmiTypeTitle(Processor))
+	dw.attr("Socket Designation", "%s", dmiString(p.SocketDesignation))
+	dw.attr("Type", "%s", p.ProcessorType)
+	dw.attr("Family", "%s", p.GetFamilyName())
+	dw.attr("Manufacturer", "%s", dmiString(p.Manufacturer))
+
+	id := make([]string, 8)
+	for i := range id {
+		id[i] = fmt.Sprintf("%02X", byte(p.ID>>(8*i)))
+	}
+	dw.attr("ID", "%s", strings.Join(id, " "))
+	if sig := p.GetSignature(); sig != "" {
+		dw.attr("Signature", "%s", sig)
+	}
+	if p.GetX86Vendor() != "" {
+		edx := uint32(p.ID >> 32)
+		var flags []string
+		for i, name := range dmiCPUIDFlags {
+			if name != "" && edx&(1<<i) != 0 {
+				flags = append(flags, name)
+			}
+		}
+		dw.list("Flags", flags)
+	}
+
+	dw.attr("Version", "%s", dmiString(p.Version))
+	if p.Voltage&0x80 != 0 {
+		dw.attr("Voltage", "%.1f V", p.GetVoltage())
+	} else {
+		var volts []string
+		for i, v := range []string{"5.0 V", "3.3 V", "2.9 V"} {
+			if p.Voltage&(1<<i) != 0 {
+				volts = append(volts, v)
+			}
+		}
+		if len(volts) == 0 {
+			volts = append(volts, "Unknown")
+		}
+		dw.attr("Voltage", "%s", strings.Join(volts, " "))
+	}
+	dw.attr("External Clock", "%s", dmiSpeed(uint32(p.ExternalClock), "MHz"))
+	dw.attr("Max Speed", "%s", dmiSpeed(uint32(p.MaxSpeed), "MHz"))
+	dw.attr("Current Speed", "%s", dmiSpeed(uint32(p.CurrentSpeed), "MHz"))
+	dw.attr("Status", "%s", p.Status)
+	dw.attr("Upgrade", "%s", p.ProcessorUpgrade)
+	if p.Length < 0x20 {
+		return
+	}
+	dw.attr("L1 Cache Handle", "%s", dmiOptionalHandle(p.L1CacheHanle))
+	dw.attr("L2 Cache Handle", "%s", dmiOptionalHandle(p.L2CacheHanle))
+	dw.attr("L3 Cache Handle", "%s", dmiOptionalHandle(p.L3CacheHanle))
+	if p.Length < 0x23 {
+		return
+	}
+	dw.attr("Serial Number", "%s", dmiString(p.SerialNumber))
+	dw.attr("Asset Tag", "%s", dmiString(p.AssetTag))
+	dw.attr("Part Number", "%s", dmiString(p.PartNumber))
+	if p.Length < 0x28 {
+		return
+	}
+	dw.attr("Core Count", "%d", p.GetCoreCount())
+	dw.attr("Core Enabled", "%d", p.GetCoreEnabled())
+	dw.attr("Thread Count", "%d", p.GetThreadCount())
+	if p.Length >= 0x32 {
+		dw.attr("Thread Enabled", "%d", p.TreadEnabled)
+	}
+
+	var chars []string
+	if p.Characteristics&ProcessorCharacteristicsUnknown != 0 {
+		chars = append(chars, "Unknown")
+	} else {
+		for _, c := range p.Characteristics.StringList() {
+			if c != procChars[ProcessorCharacteristicsReserved] {
+				chars = append(chars, c)
+			}
+		}
+	}
+	dw.list("Characteristics", chars)
+}
+
+func dmiPortConnector(dw *dmiWriter, pc *Type8PortConnector) {
+	dw.printf("%s\n", dmiTypeTitle(PortConnector))
+	dw.attr("Internal Reference Designator", "%s", dmiString(pc.InternalReferenceDesignator))
+	dw.attr("Internal Connector Type", "%s", pc.InternalConnectorType)
+	dw.attr("External Reference Designator", "%s", dmiString(pc.ExternalReferenceDesignator))
+	dw.attr("External Connector Type", "%s", pc.ExternalConnectorType)
+	dw.attr("Port Type", "%s", pc.PortType)
+}
+
+func dmiSystemSlots(dw *dmiWriter, s *Type9SystemSlots) {
+	dw.printf("%s\n", dmiTypeTitle(SystemSlots))
+	dw.attr("Designation", "%s", dmiString(s.SlotDesignation))
+
+	// dmidecode prefixes the type with the bus width, except Other/Unknown.
+	width := ""
+	if s.SlotDataBusWidth > 0x02 {
+		if _, ok := slotWidthNames[s.SlotDataBusWidth]; ok {
+			width = s.SlotDataBusWidth.String() + " "
+		}
+	}
+	dw.attr("Type", "%s%s", width, s.SlotType)
+	dw.attr("Current Usage", "%s", s.CurrentUsage)
+	dw.attr("Length", "%s", s.SlotLength)
+
+	switch st := s.SlotType; {
+	case st == 0x07:
+		dw.attr("ID", "Adapter %d, Socket %d", s.SlotID&0xFF, s.SlotID>>8)
+	case st >= 0x04 && st <= 0x06, st >= 0x0e && st <= 0x13, st.IsPCIExpress():
+		dw.attr("ID", "%d", s.SlotID)
+	}
+
+	var chars []string
+	switch {
+	case s.Characteristics1&1 != 0:
+		chars = append(chars, "Unknown")
+	case s.Characteristics1 != 0 || s.Characteristics2 != 0:
+		chars = append(chars, s.Characteristics1.StringList()...)
+		if s.Length >= 0x0D {
+			chars = append(chars, s.Characteristics2.StringList()...)
+		}
+	}
+	dw.list("Characteristics", chars)
+
+	if s.Length < 0x11 {
+		return
+	}
+	if s.HasPCIAddress() {
+		dw.attr("Bus Address", "%s", s.GetPCIAddress())
+	}
+	if s.Length < 0x13 {
+		return
+	}
+	dw.attr("Data Bus Width", "%d", s.DataBusWidth)
+	dw.attr("Peer Devices", "%d", len(s.PeerGroups))
+	for i, g := range s.PeerGroups {
+		dw.attr("Peer Device "+strconv.Itoa(i+1), "%s", g)
+	}
+
+	if int(s.Length) < 0x18+5*len(s.PeerGroups) {
+		return
+	}
+	if s.SlotType.IsPCIExpress() && s.SlotInformation != 0 {
+		dw.attr("PCI Express Generation", "%d", s.SlotInformation)
+	}
+	dw.attr("Slot Physical Width", "%s", s.SlotPhysicalWidth)
+	if s.SlotPitch == 0 {
+		dw.attr("Pitch", "Unknown")
+	} else {
+		dw.attr("Pitch", "%d.%02d mm", s.SlotPitch/100, s.SlotPitch%100)
+	}
+	if int(s.Length) >= 0x19+5*len(s.PeerGroups) {
+		dw.attr("Height", "%s", s.SlotHeight)
+	}
+}
+
+func dmiOEMStrings(dw *dmiWriter, o *Type11OEMStrings) {
+	dw.printf("%s\n", dmiTypeTitle(OEMStrings))
+	for i, s := range o.Strings {
+		dw.attr("String "+strconv.Itoa(i+1), "%s", dmiString(s))
+	}
+}
+
+func dmiBIOSLanguage(dw *dmiWriter, l *Type13BIOSLanguage) {
+	dw.printf("%s\n", dmiTypeTitle(BIOSLanguage))
+	dw.attr("Language Description Format", "%s", l.Flags)
+	dw.attr("Installable Languages", "%d", l.InstallableLanguages)
+	for _, lang := range l.Languages {
+		dw.printf("\t\t%s\n", lang)
+	}
+	dw.attr("Currently Installed Language", "%s", dmiString(l.CurrentLanguage))
+}
+
+func dmiPhysicalMemoryArray(dw *dmiWriter, a *Type16PhysicalMemoryArray) {
+	dw.printf("%s\n", dmiTypeTitle(PhysicalMemoryArray))
+	dw.attr("Location", "%s", a.Location)
+	dw.attr("Use", "%s", a.Use)
+	dw.attr("Error Correction Type", "%s", a.ErrorCorrection)
+	if v := a.GetMaximumCapacity(); v != 0 {
+		dw.attr("Maximum Capacity", "%s", dmiSize(v))
+	} else {
+		dw.attr("Maximum Capacity", "Unknown")
+	}
+	dw.attr("Error Information Handle", "%s", dmiErrorHandle(a.MemoryErrorInfoHandle))
+	dw.attr("Number Of Devices", "%d", a.NumberOfMemoryDevices)
+}
+
+// dmiMemoryWidth formats a memory device width in bits; 0xFFFF is unknown.
+func dmiMemoryWidth(v uint16) string {
+	if v == 0xFFFF || v == 0 {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%d bits", v)
+}
+
+// dmiVoltage formats millivolts the way dmidecode does.
+func dmiVoltage(mv uint16) string {
+	if mv == 0 {
+		return "Unknown"
+	}
+	if mv%100 != 0 {
+		return strconv.FormatFloat(float64(mv)/1000, 'g', -1, 32) + " V"
+	}
+	return fmt.Sprintf("%.1f V", float64(mv)/1000)
+}
+
+// dmiJEDECID formats a JEDEC manufacturer ID as bank and code.
+func dmiJEDECID(id uint16) string {
+	if id == 0 {
+		return "Unknown"
+	}
+	return fmt.Sprintf("Bank %d, Hex 0x%02X", id&0x7F+1, id>>8)
+}
+
+func dmiProductID(id uint16) string {
+	if id == 0 {
+		return "Unknown"
+	}
+	return fmt.Sprintf("0x%04X", id)
+}
+
+// dmiMemoryDeviceSize formats the non-volatile, volatile, cache and logical sizes.
+func dmiMemoryDeviceSize(v uint64) string {
+	switch v {
+	case 0:
+		return "None"
+	case 0xFFFFFFFFFFFFFFFF:
+		return "Unknown"
+	}
+	return dmiSize(v)
+}
+
+func dmiMemoryDevice(dw *dmiWriter, m *Type17MemoryDevice) {
+	dw.printf("%s\n", dmiTypeTitle(MemoryDevice))
+	dw.attr("Array Handle", "0x%04X", m.PhysicalMemoryArrayHandle)
+	dw.attr("Error Information Handle", "%s", dmiErrorHandle(m.MemoryErrorInfoHandle))
+	dw.attr("Total Width", "%s", dmiMemoryWidth(m.TotalWidth))
+	dw.attr("Data Width", "%s", dmiMemoryWidth(m.DataWidth))
+	dw.attr("Size", "%s", strings.Replace(m.GetSizeString(), " KB", " kB", 1))
+	dw.attr("Form Factor", "%s", m.FormFactor)
+	switch m.DeviceSet {
+	case 0:
+		dw.attr("Set", "None")
+	case 0xFF:
+		dw.attr("Set", "Unknown")
+	default:
+		dw.attr("Set", "%d", m.DeviceSet)
+	}
+	dw.attr("Locator", "%s", dmiString(m.DeviceLocator))
+	dw.attr("Bank Locator", "%s", dmiString(m.BankLocator))
+	dw.attr("Type", "%s", m.Type)
+	dw.attr("Type Detail", "%s", m.TypeDetail)
+	if m.Length < 0x17 {
+		return
+	}
+
+	speed := uint32(m.Speed)
+	if speed == 0xFFFF && m.Length >= 0x5C {
+		speed = m.ExtendedSpeed
+	}
+	dw.attr("Speed", "%s", dmiSpeed(speed, "MT/s"))
+	if m.Length < 0x1B {
+		return
+	}
+	dw.attr("Manufacturer", "%s", dmiString(m.Manufacturer))
+	dw.attr("Serial Number", "%s", dmiString(m.SerialNumber))
+	dw.attr("Asset Tag", "%s", dmiString(m.AssetTag))
+	dw.attr("Part Number", "%s", dmiString(m.PartNumber))
+	if m.Length < 0x1C {
+		return
+	}
+	dw.attr("Rank", "%s", m.GetRankString())
+	if m.Length < 0x22 {
+		return
+	}
+	configured := uint32(m.ConfiguredSpeed)
+	if configured == 0xFFFF && m.Length >= 0x5C {
+		configured = m.ExtendedConfiguredSpeed
+	}
+	dw.attr("Configured Memory Speed", "%s", dmiSpeed(configured, "MT/s"))
+	if m.Length < 0x28 {
+		return
+	}
+	dw.attr("Minimum Voltage", "%s", dmiVoltage(m.MinimumVoltage))
+	dw.attr("Maximum Voltage", "%s", dmiVoltage(m.MaximumVoltage))
+	dw.attr("Configured Voltage", "%s", dmiVoltage(m.ConfiguredVoltage))
+	if m.Length < 0x34 {
+		return
+	}
+	dw.attr("Memory Technology", "%s", m.Technology)
+	dw.attr("Memory Operating Mode Capability", "%s", m.OperatingModeCapability)
+	dw.attr("Firmware Version", "%s", dmiString(m.FirmwareVersion))
+	dw.attr("Module Manufacturer ID", "%s", dmiJEDECID(m.ModuleManufacturerID))
+	dw.attr("Module Product ID", "%s", dmiProductID(m.ModuleProductID))
+	dw.attr("Memory Subsystem Controller Manufacturer ID", "%s", dmiJEDECID(m.SubsystemControllerManufacturerID))
+	dw.attr("Memory Subsystem Controller Product ID", "%s", dmiProductID(m.SubsystemControllerProductID))
+	dw.attr("Non-Volatile Size", "%s", dmiMemoryDeviceSize(m.NonvolatileSize))
+	dw.attr("Volatile Size", "%s", dmiMemoryDeviceSize(m.VolatileSize))
+	dw.attr("Cache Size", "%s", dmiMemoryDeviceSize(m.CacheSize))
+	dw.attr("Logical Size", "%s", dmiMemoryDeviceSize(m.LogicalSize))
+}
+
+// dmiMappedAddresses prints the address range of a Type 19 or 20 structure:
+// KB-granular addresses as dmidecode shows them, or the extended byte addresses.
+func dmiMappedAddresses(dw *dmiWriter, start, end uint32, extStart, extEnd uint64) {
+	first, last := mappedRange(start, end, extStart, extEnd)
+	if start == 0xFFFFFFFF {
+		dw.attr("Starting Address", "0x%016X", first)
+		dw.attr("Ending Address", "0x%016X", last)
+	} else {
+		dw.attr("Starting Address", "0x%08X%03X", start>>22, (start&0x3FFFFF)<<10)
+		dw.attr("Ending Address", "0x%08X%03X", end>>22, (end&0x3FFFFF)<<10|0x3FF)
+	}
+	if last < first {
+		dw.attr("Range Size", "Invalid")
+		return
+	}
+	dw.attr("Range Size", "%s", dmiSize(last-first+1))
+}
+
+func dmiMemoryArrayMappedAddress(dw *dmiWriter, m *Type19MemoryArrayMappedAddress) {
+	dw.printf("%s\n", dmiTypeTitle(MemoryArrayMappedAddress))
+	dmiMappedAddresses(dw, m.StartingAddress, m.EndingAddress, m.ExtendedStartingAddress, m.ExtendedEndingAddress)
+	dw.attr("Physical Array Handle", "0x%04X", m.MemoryArrayHandle)
+	dw.attr("Partition Width", "%d", m.PartitionWidth)
+}
+
+// dmiPosition formats a Type 20 position or depth; 0xFF is unknown.
+func dmiPosition(dw *dmiWriter, name string, v uint8) {
+	switch v {
+	case 0:
+	case 0xFF:
+		dw.attr(name, "Unknown")
+	default:
+		dw.attr(name, "%d", v)
+	}
+}
+
+func dmiMemoryDeviceMappedAddress(dw *dmiWriter, m *Type20MemoryDeviceMappedAddress) {
+	dw.printf("%s\n", dmiTypeTitle(MemoryDeviceMappedAddress))
+	dmiMappedAddresses(dw, m.StartingAddress, m.EndingAddress, m.ExtendedStartingAddress, m.ExtendedEndingAddress)
+	dw.attr("Physical Device Handle", "0x%04X", m.MemoryDeviceHandle)
+	dw.attr("Memory Array Mapped Address Handle", "0x%04X", m.MemoryArrayMappedAddressHandle)
+	switch m.PartitionRowPosition {
+	case 0:
+		dw.attr("Partition Row Position", "<OUT OF SPEC>")
+	case 0xFF:
+		dw.attr("Partition Row Position", "Unknown")
+	default:
+		dw.attr("Partition Row Position", "%d", m.PartitionRowPosition)
+	}
+	dmiPosition(dw, "Interleave Position", m.InterleavePosition)
+	dmiPosition(dw, "Interleaved Data Depth", m.InterleavedDataDepth)
+}
+
+func dmiIPMIDevice(dw *dmiWriter, d *Type38IPMIDevice) {
+	dw.printf("%s\n", dmiTypeTitle(IPMIDevice))
+	dw.attr("Interface Type", "%s", d.InterfaceType)
+	dw.attr("Specification Version", "%s", d.GetSpecificationVersion())
+	dw.attr("I2C Slave Address", "0x%02x", d.I2CTargetAddress>>1)
+	if d.NVStorageDeviceAddress != 0xFF {
+		dw.attr("NV Storage Device Address", "%d", d.NVStorageDeviceAddress)
+	} else {
+		dw.attr("NV Storage Device", "Not Present")
+	}
+
+	if d.InterfaceType == IPMIInterfaceTypeSSIF {
+		dw.attr("Base Address", "0x%02X (SMBus)", d.GetBaseAddress())
+	} else {
+		space := "Memory-mapped"
+		if d.IsIOSpace() {
+			space = "I/O"
+		}
+		addr := d.GetBaseAddress()
+		dw.attr("Base Address", "0x%08X%08X (%s)", addr>>32, addr&0xFFFFFFFF, space)
+	}
+
+	if d.Length < 0x12 {
+		return
+	}
+	if d.InterfaceType != IPMIInterfaceTypeSSIF {
+		dw.attr("Register Spacing", "%s", d.GetRegisterSpacing())
+	}
+	if d.HasInterrupt() {
+		dw.attr("Interrupt Polarity", "%s", d.GetInterruptPolarity())
+		dw.attr("Interrupt Trigger Mode", "%s", d.GetInterruptTriggerMode())
+	}
+	if d.InterruptNumber != 0 {
+		dw.attr("Interrupt Number", "%d", d.InterruptNumber)
+	}
+}
+
+func dmiYesNo(v bool) string {
+	if v {
+		return "Yes"
+	}
+	return "No"
+}
+
+func dmiPowerSupply(dw *dmiWriter, ps *Type39SystemPowerSupply) {
+	dw.printf("%s\n", dmiTypeTitle(PowerSupply))
+	if ps.PowerUnitGroup != 0 {
+		dw.attr("Power Unit Group", "%d", ps.PowerUnitGroup)
+	}
+	dw.attr("Location", "%s", dmiString(ps.Location))
+	dw.attr("Name", "%s", dmiString(ps.DeviceName))
+	dw.attr("Manufacturer", "%s", dmiString(ps.Manufacturer))
+	dw.attr("Serial Number", "%s", dmiString(ps.SerialNumber))
+	dw.attr("Asset Tag", "%s", dmiString(ps.AssetTagNumber))
+	dw.attr("Model Part Number", "%s", dmiString(ps.ModelPartNumber))
+	dw.attr("Revision", "%s", dmiString(ps.RevisionLevel))
+	dw.attr("Max Power Capacity", "%s", ps.GetMaxPowerCapacityString())
+
+	c := ps.PowerSupplyCharacteristics
+	if c.IsPresent() {
+		dw.attr("Status", "Present, %s", c.GetStatus())
+	} else {
+		dw.attr("Status", "Not Present")
+	}
+	dw.attr("Type", "%s", c.GetType())
+	dw.attr("Input Voltage Range Switching", "%s", c.GetInputVoltageRangeSwitching())
+	dw.attr("Plugged", "%s", dmiYesNo(c.IsPlugged()))
+	dw.attr("Hot Replaceable", "%s", dmiYesNo(c.IsHotReplaceable()))
+
+	if ps.Length < 0x16 {
+		return
+	}
+	if ps.InputVoltageProbeHandle != 0xFFFF {
+		dw.attr("Input Voltage Probe Handle", "0x%04X", ps.InputVoltageProbeHandle)
+	}
+	if ps.CoolingDeviceHandle != 0xFFFF {
+		dw.attr("Cooling Device Handle", "0x%04X", ps.CoolingDeviceHandle)
+	}
+	if ps.InputCurrentProbeHandle != 0xFFFF {
+		dw.attr("Input Current Probe Handle", "0x%04X", ps.InputCurrentProbeHandle)
+	}
+}
+
+func dmiOnboardDevice(dw *dmiWriter, d *Type41OnboardDevicesExtended) {
+	dw.printf("%s\n", dmiTypeTitle(OnBoardDevicesExtendedInformation))
+	dw.attr("Reference Designation", "%s", dmiString(d.ReferenceDesignation))
+	dw.attr("Type", "%s", d.DeviceType)
+	if d.DeviceType.IsEnabled() {
+		dw.attr("Status", "Enabled")
+	} else {
+		dw.attr("Status", "Disabled")
+	}
+	dw.attr("Type Instance", "%d", d.DeviceTypeInstance)
+	if d.HasPCIAddress() {
+		dw.attr("Bus Address", "%s", d.GetPCIAddress())
+	}
+}
+
+func dmiTPMDevice(dw *dmiWriter, d *Type43TPMDevice) {
+	dw.printf("%s\n", dmiTypeTitle(TPMDevice))
+	dw.attr("Vendor ID", "%s", d.GetVendorID())
+	dw.attr("Specification Version", "%s", d.GetSpecVersion())
+	if fw := d.GetFirmwareVersion(); fw != "" {
+		dw.attr("Firmware Revision", "%s", fw)
+	}
+	dw.attr("Description", "%s", dmiString(d.Description))
+	dw.list("Characteristics", d.Characteristics.StringList())
+	if d.Length >= 0x1F {
+		dw.attr("OEM-specific Information", "0x%08X", d.OEMDefined)
+	}
+}
+
+// dmiTypeKeywords are the dmidecode -t keywords and the types they select.
+var dmiTypeKeywords = map[string][]TableType{
+	"bios":      {BIOS, BIOSLanguage},
+	"system":    {System, SystemConfigurationOptions, SystemEventLog, SystemReset, SystemBoot},
+	"baseboard": {BaseBoard, OnBoardDevices, OnBoardDevicesExtendedInformation},
+	"chassis":   {Chassis},
+	"processor": {Processor},
+	"memory":    {Controller, Module, PhysicalMemoryArray, MemoryDevice},
+	"cache":     {Cache},
+	"connector": {PortConnector},
+	"slot":      {SystemSlots},
+}
+
+// ParseDMITypes parses dmidecode -t arguments: type numbers, comma-separated
+// lists of them, or keywords such as "memory" and "slot".
+func ParseDMITypes(args []string) ([]TableType, error) {
+	var types []TableType
+	for _, arg := range args {
+		for _, field := range strings.Split(arg, ",") {
+			field = strings.TrimSpace(strings.ToLower(field))
+			if field == "" {
+				continue
+			}
+			if kw, ok := dmiTypeKeywords[field]; ok {
+				types = append(types, kw...)
+				continue
+			}
+			n, err := strconv.ParseUint(field, 0, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid type keyword: %s", field)
+			}
+			types = append(types, TableType(n))
+		}
+	}
+	slices.Sort(types)
+	return slices.Compact(types), nil
+}
+
+// dmiStringKeywords are the dmidecode -s keywords baize can answer.
+var dmiStringKeywords = map[string]struct {
+	typ   TableType
+	value func(any) string
+}{
+	"bios-vendor":       {BIOS, func(v any) string { return v.(*Type0BIOS).Vendor }},
+	"bios-version":      {BIOS, func(v any) string { return v.(*Type0BIOS).Version }},
+	"bios-release-date": {BIOS, func(v any) string { return v.(*Type0BIOS).ReleaseDate }},
+	"bios-revision": {BIOS, func(v any) string {
+		b := v.(*Type0BIOS)
+		if b.BIOSMajorRelease == 0xFF || b.BIOSMinorRelease == 0xFF {
+			return ""
+		}
+		return fmt.Sprintf("%d.%d", b.BIOSMajorRelease, b.BIOSMinorRelease)
+	}},
+	"firmware-revision": {BIOS, func(v any) string {
+		b := v.(*Type0BIOS)
+		if b.ECMajorRelease == 0xFF || b.ECMinorRelease == 0xFF {
+			return ""
+		}
+		return fmt.Sprintf("%d.%d", b.ECMajorRelease, b.ECMinorRelease)
+	}},
+	"system-manufacturer":     {System, func(v any) string { return v.(*Type1System).Manufacturer }},
+	"system-product-name":     {System, func(v any) string { return v.(*Type1System).ProductName }},
+	"system-version":          {System, func(v any) string { return v.(*Type1System).Version }},
+	"system-serial-number":    {System, func(v any) string { return v.(*Type1System).SerialNumber }},
+	"system-uuid":             {System, func(v any) string { return dmiUUID(v.(*Type1System).UUID) }},
+	"system-sku-number":       {System, func(v any) string { return v.(*Type1System).SKU }},
+	"system-family":           {System, func(v any) string { return v.(*Type1System).Family }},
+	"baseboard-manufacturer":  {BaseBoard, func(v any) string { return v.(*Type2BaseBoard).Manufacturer }},
+	"baseboard-product-name":  {BaseBoard, func(v any) string { return v.(*Type2BaseBoard).Product }},
+	"baseboard-version":       {BaseBoard, func(v any) string { return v.(*Type2BaseBoard).Version }},
+	"baseboard-serial-number": {BaseBoard, func(v any) string { return v.(*Type2BaseBoard).SerialNumber }},
+	"baseboard-asset-tag":     {BaseBoard, func(v any) string { return v.(*Type2BaseBoard).AssetTag }},
+	"chassis-manufacturer":    {Chassis, func(v any) string { return v.(*Type3Chassis).Manufacturer }},
+	"chassis-type":            {Chassis, func(v any) string { return v.(*Type3Chassis).ChassisType.String() }},
+	"chassis-version":         {Chassis, func(v any) string { return v.(*Type3Chassis).Version }},
+	"chassis-serial-number":   {Chassis, func(v any) string { return v.(*Type3Chassis).SerialNumber }},
+	"chassis-asset-tag":       {Chassis, func(v any) string { return v.(*Type3Chassis).AssetTag }},
+	"processor-family":        {Processor, func(v any) string { return v.(*Type4Processor).GetFamilyName() }},
+	"processor-manufacturer":  {Processor, func(v any) string { return v.(*Type4Processor).Manufacturer }},
+	"processor-version":       {Processor, func(v any) string { return v.(*Type4Processor).Version }},
+	"processor-frequency": {Processor, func(v any) string {
+		return dmiSpeed(uint32(v.(*Type4Processor).CurrentSpeed), "MHz")
+	}},
+}
+
+// DMIStringKeywords returns the keywords accepted by GetString, sorted.
+func DMIStringKeywords() []string {
+	return slices.Sorted(maps.Keys(dmiStringKeywords))
+}
+
+// GetString returns the value of a dmidecode -s keyword, one entry per
+// structure of its type, e.g. one per socket for "processor-version".
+func GetString(keyword string) ([]string, error) {
+	kw, ok := dmiStringKeywords[keyword]
+	if !ok {
+		return nil, fmt.Errorf("invalid string keyword: %s", keyword)
+	}
+	d, err := New(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := d.getParserData(kw.typ)
+	if len(data) == 0 {
+		return nil, err
+	}
+
+	res := make([]string, 0, len(data))
+	for _, v := range data {
+		res = append(res, kw.value(v))
+	}
+	return res, nil
+}
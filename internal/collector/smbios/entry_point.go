@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 )
@@ -15,8 +16,14 @@ const (
 	anchor64Len = 0x18
 )
 
+// ErrInvalidChecksum is returned for an entry point whose checksum does not
+// match. parseEntryPoint tolerates it so that tables from such firmware can
+// still be read; Lint reports it.
+var ErrInvalidChecksum = errors.New("invalid checksum")
+
 type EntryPoint interface {
 	Table() (int, int)
+	Version() string
 	MarshalBinary() ([]byte, error)
 	UnmarshalBinary([]byte) error
 }
@@ -44,7 +51,7 @@ func parseEntryPoint(r io.Reader) (EntryPoint, error) {
 		return nil, err
 	}
 
-	if err := eps.UnmarshalBinary(data); err != nil {
+	if err := eps.UnmarshalBinary(data); err != nil && !errors.Is(err, ErrInvalidChecksum) {
 		return nil, err
 	}
 
@@ -83,6 +90,10 @@ func (e *entryPoint32) Table() (int, int) {
 	return int(e.TableAddress), int(e.TableLength)
 }
 
+func (e *entryPoint32) Version() string {
+	return fmt.Sprintf("%d.%d", e.MajorVersion, e.MinorVersion)
+}
+
 func (e *entryPoint32) MarshalBinary() ([]byte, error) {
 	var buf bytes.Buffer
 	if err := binary.Write(&buf, binary.LittleEndian, e); err != nil {
@@ -103,16 +114,16 @@ func (e *entryPoint32) UnmarshalBinary(data []byte) error {
 		return fmt.Errorf("invalid length %d", e.Length)
 	}
 
-	if e.Checksum != calChecksum(data, 4) {
-		return fmt.Errorf("invalid checksum %d", e.Checksum)
-	}
-
 	if !bytes.Equal(e.IntermediateAnchorString[:], []byte("_DMI_")) {
 		return fmt.Errorf("invalid intermediate anchor string %s", e.IntermediateAnchorString[:])
 	}
 
+	if e.Checksum != calChecksum(data, 4) {
+		return fmt.Errorf("%w %d", ErrInvalidChecksum, e.Checksum)
+	}
+
 	if e.IntermediateChecksum != calChecksum(data[0x10:0x1F], 5) {
-		return fmt.Errorf("invalid intermediate checksum %d", e.IntermediateChecksum)
+		return fmt.Errorf("intermediate %w %d", ErrInvalidChecksum, e.IntermediateChecksum)
 	}
 
 	return nil
@@ -135,6 +146,10 @@ func (e *entryPoint64) Table() (int, int) {
 	return int(e.TableAddress), int(e.MaximumStructureSize)
 }
 
+func (e *entryPoint64) Version() string {
+	return fmt.Sprintf("%d.%d.%d", e.MajorVersion, e.MinorVersion, e.DocumentationRevision)
+}
+
 func (e *entryPoint64) MarshalBinary() ([]byte, error) {
 	var buf bytes.Buffer
 	if err := binary.Write(&buf, binary.LittleEndian, e); err != nil {
@@ -156,7 +171,7 @@ func (e *entryPoint64) UnmarshalBinary(data []byte) error {
 	}
 
 	if e.Checksum != calChecksum(data, 5) {
-		return fmt.Errorf("invalid checksum %d", e.Checksum)
+		return fmt.Errorf("%w %d", ErrInvalidChecksum, e.Checksum)
 	}
 
 	return nil
@@ -0,0 +1,297 @@
+package smbios
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/zenithax-cc/baize/pkg/utils"
+)
+
+const (
+	LintError   = "ERROR"
+	LintWarning = "WARNING"
+)
+
+// LintIssue is one problem found in the SMBIOS data.
+type LintIssue struct {
+	Severity string
+	Where    string // "entry point" or the structure, e.g. "Handle 0x0004, DMI type 4"
+	Message  string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%-7s %s: %s", i.Severity, i.Where, i.Message)
+}
+
+// Lint checks the SMBIOS data for integrity problems: entry point checksums,
+// structure lengths below what the declared SMBIOS version requires,
+// structures that fail to decode (e.g. string indexes past the string table),
+// duplicate handles, handle references to missing structures, and
+// placeholder serial numbers, asset tags and UUIDs.
+func Lint() ([]LintIssue, error) {
+	d, err := New(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return d.lint(), nil
+}
+
+func (d *Decoder) lint() []LintIssue {
+	var issues []LintIssue
+	issues = append(issues, d.lintEntryPoint()...)
+
+	handles := make(map[uint16]TableType, len(d.all))
+	for _, t := range d.all {
+		where := tableWhere(t)
+		if prev, ok := handles[t.Handle]; ok {
+			issues = append(issues, LintIssue{LintError, where,
+				fmt.Sprintf("handle is already used by a type %d structure", prev)})
+		} else {
+			handles[t.Handle] = TableType(t.Type)
+		}
+
+		if want := d.minLength(t); int(t.Length) < want {
+			major, minor := d.specVersion()
+			issues = append(issues, LintIssue{LintWarning, where,
+				fmt.Sprintf("length %d bytes is shorter than the %d bytes SMBIOS %d.%d requires", t.Length, want, major, minor)})
+		}
+
+		if parser, ok := d.parsers[TableType(t.Type)]; ok {
+			if _, err := parser(t); err != nil {
+				issues = append(issues, LintIssue{LintError, where, err.Error()})
+			}
+		}
+
+		issues = append(issues, lintPlaceholders(t)...)
+	}
+
+	for _, t := range d.all {
+		issues = append(issues, lintReferences(t, handles)...)
+	}
+
+	if n := len(d.all); n == 0 || d.all[n-1].Type != uint8(EndOfTable) {
+		issues = append(issues, LintIssue{LintWarning, "table", "no End Of Table (type 127) structure"})
+	}
+
+	return issues
+}
+
+func tableWhere(t *Table) string {
+	return fmt.Sprintf("Handle 0x%04X, DMI type %d", t.Handle, t.Type)
+}
+
+func (d *Decoder) lintEntryPoint() []LintIssue {
+	const where = "entry point"
+
+	if d.EntryPoint == nil {
+		return []LintIssue{{LintError, where, "no SMBIOS entry point"}}
+	}
+	data, err := d.EntryPoint.MarshalBinary()
+	if err != nil {
+		return []LintIssue{{LintError, where, err.Error()}}
+	}
+
+	var issues []LintIssue
+	switch ep := d.EntryPoint.(type) {
+	case *entryPoint32:
+		if want := calChecksum(data, 4); ep.Checksum != want {
+			issues = append(issues, LintIssue{LintError, where,
+				fmt.Sprintf("checksum 0x%02X, expected 0x%02X", ep.Checksum, want)})
+		}
+		if want := calChecksum(data[0x10:0x1F], 5); ep.IntermediateChecksum != want {
+			issues = append(issues, LintIssue{LintError, where,
+				fmt.Sprintf("intermediate checksum 0x%02X, expected 0x%02X", ep.IntermediateChecksum, want)})
+		}
+		if int(ep.NumberOfStructures) != len(d.all) {
+			issues = append(issues, LintIssue{LintWarning, where,
+				fmt.Sprintf("declares %d structures, the table holds %d", ep.NumberOfStructures, len(d.all))})
+		}
+	case *entryPoint64:
+		if want := calChecksum(data, 5); ep.Checksum != want {
+			issues = append(issues, LintIssue{LintError, where,
+				fmt.Sprintf("checksum 0x%02X, expected 0x%02X", ep.Checksum, want)})
+		}
+	}
+
+	return issues
+}
+
+// specVersion returns the SMBIOS version the entry point declares.
+func (d *Decoder) specVersion() (uint8, uint8) {
+	switch ep := d.EntryPoint.(type) {
+	case *entryPoint32:
+		return ep.MajorVersion, ep.MinorVersion
+	case *entryPoint64:
+		return ep.MajorVersion, ep.MinorVersion
+	}
+	return 0, 0
+}
+
+type specLength struct {
+	major, minor uint8
+	length       int
+}
+
+// specLengths lists the structure lengths each SMBIOS version requires,
+// oldest first.
+var specLengths = map[TableType][]specLength{
+	BIOS:                              {{2, 0, 0x12}, {2, 4, 0x18}, {3, 1, 0x1A}},
+	System:                            {{2, 0, 0x08}, {2, 1, 0x19}, {2, 4, 0x1B}},
+	BaseBoard:                         {{2, 0, 0x08}},
+	Chassis:                           {{2, 0, 0x09}, {2, 1, 0x0D}, {2, 3, 0x15}},
+	Processor:                         {{2, 0, 0x1A}, {2, 1, 0x20}, {2, 3, 0x23}, {2, 5, 0x28}, {2, 6, 0x2A}, {3, 0, 0x30}, {3, 6, 0x32}},
+	Cache:                             {{2, 0, 0x0F}, {2, 1, 0x13}, {3, 1, 0x1B}},
+	PortConnector:                     {{2, 0, 0x09}},
+	SystemSlots:                       {{2, 0, 0x0C}, {2, 1, 0x0D}, {2, 6, 0x11}, {3, 2, 0x13}},
+	OEMStrings:                        {{2, 0, 0x05}},
+	BIOSLanguage:                      {{2, 0, 0x16}},
+	PhysicalMemoryArray:               {{2, 1, 0x0F}, {2, 7, 0x17}},
+	MemoryDevice:                      {{2, 1, 0x15}, {2, 3, 0x1B}, {2, 6, 0x1C}, {2, 7, 0x22}, {2, 8, 0x28}, {3, 2, 0x54}, {3, 3, 0x5C}},
+	MemoryArrayMappedAddress:          {{2, 1, 0x0F}, {2, 7, 0x1F}},
+	MemoryDeviceMappedAddress:         {{2, 1, 0x13}, {2, 7, 0x23}},
+	SystemBoot:                        {{2, 0, 0x0B}},
+	IPMIDevice:                        {{2, 0, 0x10}},
+	PowerSupply:                       {{2, 3, 0x10}},
+	OnBoardDevicesExtendedInformation: {{2, 6, 0x0B}},
+	TPMDevice:                         {{3, 1, 0x1F}},
+	EndOfTable:                        {{2, 0, 0x04}},
+}
+
+// minLength returns the length the declared SMBIOS version requires for the
+// structure, including its variable-length parts, or 0 when unknown.
+func (d *Decoder) minLength(t *Table) int {
+	major, minor := d.specVersion()
+	atLeast := func(maj, min uint8) bool {
+		return major > maj || major == maj && minor >= min
+	}
+
+	want := 0
+	for _, l := range specLengths[TableType(t.Type)] {
+		if atLeast(l.major, l.minor) {
+			want = l.length
+		}
+	}
+
+	switch TableType(t.Type) {
+	case Chassis:
+		// Contained elements: count at 13h, record length at 14h; SKU after them.
+		if atLeast(2, 3) && t.Length >= 0x15 {
+			want += int(t.FormattedArea[0x13-headerLength]) * int(t.FormattedArea[0x14-headerLength])
+			if atLeast(2, 7) {
+				want++
+			}
+		}
+	case SystemSlots:
+		// Peer groups: count at 12h, 5 bytes each; slot information, physical
+		// width and pitch after them since 3.4, height since 3.5.
+		if atLeast(3, 2) && t.Length >= 0x13 {
+			want += 5 * int(t.FormattedArea[0x12-headerLength])
+			switch {
+			case atLeast(3, 5):
+				want += 5
+			case atLeast(3, 4):
+				want += 4
+			}
+		}
+	}
+
+	return want
+}
+
+type handleReference struct {
+	from   TableType
+	offset int
+	name   string
+	to     []TableType
+	none   []uint16 // values meaning "no structure referenced"
+}
+
+var handleReferences = []handleReference{
+	{Processor, 0x1A, "L1 Cache Handle", []TableType{Cache}, []uint16{0xFFFF}},
+	{Processor, 0x1C, "L2 Cache Handle", []TableType{Cache}, []uint16{0xFFFF}},
+	{Processor, 0x1E, "L3 Cache Handle", []TableType{Cache}, []uint16{0xFFFF}},
+	{PhysicalMemoryArray, 0x0B, "Error Information Handle", []TableType{Bit32MemoryError, Bit64MemoryError}, []uint16{0xFFFE, 0xFFFF}},
+	{MemoryDevice, 0x04, "Array Handle", []TableType{PhysicalMemoryArray}, nil},
+	{MemoryDevice, 0x06, "Error Information Handle", []TableType{Bit32MemoryError, Bit64MemoryError}, []uint16{0xFFFE, 0xFFFF}},
+	{MemoryArrayMappedAddress, 0x0C, "Physical Array Handle", []TableType{PhysicalMemoryArray}, nil},
+	{MemoryDeviceMappedAddress, 0x0C, "Physical Device Handle", []TableType{MemoryDevice}, nil},
+	{MemoryDeviceMappedAddress, 0x0E, "Memory Array Mapped Address Handle", []TableType{MemoryArrayMappedAddress}, nil},
+}
+
+// lintReferences reports handles in t that point to no structure, or to one
+// of the wrong type.
+func lintReferences(t *Table, handles map[uint16]TableType) []LintIssue {
+	var issues []LintIssue
+	for _, ref := range handleReferences {
+		if TableType(t.Type) != ref.from {
+			continue
+		}
+		h, err := t.GetWordAt(ref.offset - headerLength)
+		if err != nil || slices.Contains(ref.none, h) {
+			continue
+		}
+
+		typ, ok := handles[h]
+		switch {
+		case !ok:
+			issues = append(issues, LintIssue{LintError, tableWhere(t),
+				fmt.Sprintf("%s 0x%04X does not exist", ref.name, h)})
+		case !slices.Contains(ref.to, typ):
+			issues = append(issues, LintIssue{LintError, tableWhere(t),
+				fmt.Sprintf("%s 0x%04X is a type %d structure, expected type %d", ref.name, h, typ, ref.to[0])})
+		}
+	}
+	return issues
+}
+
+// placeholderUUIDs are UUIDs firmware ships before the system is provisioned.
+var placeholderUUIDs = []string{
+	uuidNotPresent,
+	uuidNotSettable,
+	"03000200-0400-0500-0006-000700080009",
+}
+
+type identityField struct {
+	typ    TableType
+	offset int
+	name   string
+}
+
+var identityFields = []identityField{
+	{System, 0x07, "Serial Number"},
+	{BaseBoard, 0x07, "Serial Number"},
+	{BaseBoard, 0x08, "Asset Tag"},
+	{Chassis, 0x07, "Serial Number"},
+	{Chassis, 0x08, "Asset Tag"},
+}
+
+// lintPlaceholders reports missing or placeholder serial numbers, asset tags
+// and system UUIDs.
+func lintPlaceholders(t *Table) []LintIssue {
+	var issues []LintIssue
+	for _, f := range identityFields {
+		if TableType(t.Type) != f.typ || int(t.Length) <= f.offset {
+			continue
+		}
+		s, err := t.GetStringAt(f.offset - headerLength)
+		switch {
+		case err != nil:
+		case s == "" && f.typ == System:
+			issues = append(issues, LintIssue{LintError, tableWhere(t), f.name + " is not set"})
+		case s != "" && utils.IsPlaceholder(s):
+			issues = append(issues, LintIssue{LintError, tableWhere(t),
+				fmt.Sprintf("%s is a placeholder: %q", f.name, s)})
+		}
+	}
+
+	if TableType(t.Type) == System && t.Length >= 0x19 {
+		var u UUID
+		copy(u[:], t.FormattedArea[0x08-headerLength:])
+		if s := u.String(); slices.Contains(placeholderUUIDs, s) {
+			issues = append(issues, LintIssue{LintError, tableWhere(t), "UUID is a placeholder: " + s})
+		}
+	}
+
+	return issues
+}
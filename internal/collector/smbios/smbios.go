@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 )
@@ -18,6 +20,9 @@ const (
 	endAddr         = 0x100000
 	maxTableSize    = 1024 * 1024 // 1MB limit
 	readTimeout     = 10 * time.Second
+
+	sourceSysfs  = "sysfs"
+	sourceDevMem = devMem
 )
 
 // 错误类型定义
@@ -201,25 +206,151 @@ func (r *devMemReader) findEntryPointAddr(ctx context.Context) (int, error) {
 	return 0, fmt.Errorf("SMBIOS entry point not found in memory range 0x%x-0x%x", startAddr, endAddr)
 }
 
-func smbiosReader(ctx context.Context) (EntryPoint, []*Table, error) {
+// file reader implementation, for SMBIOS dumps taken on another host: a
+// `dmidecode --dump-bin` file, which holds the entry point followed by the
+// table at the offset the entry point names, or a copy of the sysfs DMI and
+// smbios_entry_point files.
+type fileReader struct {
+	entryPoint string // smbios_entry_point, or the dump-bin file itself
+	table      string // DMI; empty when the table is inside the dump-bin file
+}
+
+// NewFileReader opens a dump. path is a dmidecode --dump-bin file, a
+// directory holding DMI and smbios_entry_point, or a DMI file with
+// smbios_entry_point next to it.
+func NewFileReader(path string) (*fileReader, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, &SMBIOSError{Op: "stat", Path: path, Err: err}
+	}
+
+	r := &fileReader{
+		entryPoint: filepath.Join(path, filepath.Base(sysfsEntryPoint)),
+		table:      filepath.Join(path, filepath.Base(sysfsDMI)),
+	}
+	if !info.IsDir() {
+		anchor, err := readAnchor(path)
+		if err != nil {
+			return nil, &SMBIOSError{Op: "read", Path: path, Err: err}
+		}
+		if strings.HasPrefix(anchor, "_SM") {
+			return &fileReader{entryPoint: path}, nil
+		}
+		r.entryPoint = filepath.Join(filepath.Dir(path), filepath.Base(sysfsEntryPoint))
+		r.table = path
+	}
+
+	for _, f := range []string{r.entryPoint, r.table} {
+		if _, err := os.Stat(f); err != nil {
+			return nil, &SMBIOSError{Op: "stat", Path: f, Err: err}
+		}
+	}
+
+	return r, nil
+}
+
+// readAnchor returns the first bytes of a file, enough to tell an entry point.
+func readAnchor(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	b := make([]byte, len(anchor64))
+	n, err := io.ReadFull(file, b)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return string(b[:n]), nil
+}
+
+func (r *fileReader) readTables(ctx context.Context, tableAddr, tableLen int) ([]*Table, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if r.table != "" {
+		file, err := os.Open(r.table)
+		if err != nil {
+			return nil, &SMBIOSError{Op: "open", Path: r.table, Err: err}
+		}
+		defer file.Close()
+
+		return parseTables(file)
+	}
+
+	data, err := os.ReadFile(r.entryPoint)
+	if err != nil {
+		return nil, &SMBIOSError{Op: "read", Path: r.entryPoint, Err: err}
+	}
+	if tableAddr < 0 || tableAddr >= len(data) {
+		return nil, fmt.Errorf("table address 0x%x is outside dump file %s", tableAddr, r.entryPoint)
+	}
+	if tableLen <= 0 || tableLen > maxTableSize {
+		return nil, fmt.Errorf("invalid table length: %d", tableLen)
+	}
+
+	// The SMBIOS 3 entry point only gives the maximum table size.
+	end := min(tableAddr+tableLen, len(data))
+	return parseTables(bytes.NewReader(data[tableAddr:end]))
+}
+
+func (r *fileReader) readEntryPoint(ctx context.Context) (EntryPoint, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	file, err := os.Open(r.entryPoint)
+	if err != nil {
+		return nil, &SMBIOSError{Op: "open", Path: r.entryPoint, Err: err}
+	}
+	defer file.Close()
+
+	return parseEntryPoint(file)
+}
+
+func (r *fileReader) Close() error {
+	return nil
+}
+
+// smbiosReader reads the entry point and tables from the dump file set with
+// SetDumpFile, from sysfs, or by scanning /dev/mem on older kernels. source
+// describes where they came from.
+func smbiosReader(ctx context.Context) (ep EntryPoint, tables []*Table, source string, err error) {
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), readTimeout)
 		defer cancel()
 	}
 
+	if dumpFile != "" {
+		reader, err := NewFileReader(dumpFile)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		ep, tables, err = readFromSource(ctx, reader)
+		return ep, tables, dumpFile, err
+	}
+
 	if _, err := os.Stat(sysfsEntryPoint); err == nil {
 		reader := &sysfsReader{}
-		return readFromSource(ctx, reader)
+		ep, tables, err = readFromSource(ctx, reader)
+		return ep, tables, sourceSysfs, err
 	}
 
 	reader, err := NewDevMemReader()
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create devmem reader: %w", err)
+		return nil, nil, "", fmt.Errorf("failed to create devmem reader: %w", err)
 	}
 	defer reader.Close()
 
-	return readFromSource(ctx, reader)
+	ep, tables, err = readFromSource(ctx, reader)
+	return ep, tables, sourceDevMem, err
 }
 
 func readFromSource(ctx context.Context, reader Reader) (EntryPoint, []*Table, error) {
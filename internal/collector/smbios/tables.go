@@ -26,6 +26,11 @@ func parseTables(r io.Reader) ([]*Table, error) {
 			return nil, err
 		}
 		tables = append(tables, t)
+
+		// Anything after the end-of-table structure is padding.
+		if TableType(t.Type) == EndOfTable {
+			return tables, nil
+		}
 	}
 }
 
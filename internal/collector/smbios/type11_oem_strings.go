@@ -0,0 +1,40 @@
+package smbios
+
+import "fmt"
+
+type Type11OEMStrings struct {
+	Header  `smbios:"-"`
+	Count   uint8    // 04h
+	Strings []string `smbios:"-"`
+}
+
+func parseType11OEMStrings(t *Table) (*Type11OEMStrings, error) {
+	if t.Header.Type != 11 {
+		return nil, fmt.Errorf("%s:%d", ErrInvalidTableType, t.Header.Type)
+	}
+
+	if t.Header.Length < 0x05 {
+		return nil, fmt.Errorf("%s: OEM strings table must be at least %d bytes", ErrInvalidTableLength, 0x05)
+	}
+
+	o := &Type11OEMStrings{
+		Header: t.Header,
+	}
+
+	if _, err := parseType(t, 0, false, o); err != nil {
+		return nil, fmt.Errorf("failed to parse Type 11 OEM Strings: %w", err)
+	}
+
+	o.Strings = tableStrings(t, int(o.Count))
+
+	return o, nil
+}
+
+// tableStrings returns the first n strings of the string area, for the
+// structures whose strings are not referenced from formatted fields.
+func tableStrings(t *Table, n int) []string {
+	if n > len(t.StringArea) {
+		n = len(t.StringArea)
+	}
+	return t.StringArea[:n:n]
+}
@@ -0,0 +1,46 @@
+package smbios
+
+import "fmt"
+
+type Type13BIOSLanguage struct {
+	Header               `smbios:"-"`
+	InstallableLanguages uint8             // 04h
+	Flags                BIOSLanguageFlags // 05h
+	CurrentLanguage      string            `smbios:"skip=15"` // 15h
+	Languages            []string          `smbios:"-"`
+}
+
+func parseType13BIOSLanguage(t *Table) (*Type13BIOSLanguage, error) {
+	if t.Header.Type != 13 {
+		return nil, fmt.Errorf("%s:%d", ErrInvalidTableType, t.Header.Type)
+	}
+
+	if t.Header.Length < 0x16 {
+		return nil, fmt.Errorf("%s: BIOS language table must be at least %d bytes", ErrInvalidTableLength, 0x16)
+	}
+
+	l := &Type13BIOSLanguage{
+		Header: t.Header,
+	}
+
+	if _, err := parseType(t, 0, false, l); err != nil {
+		return nil, fmt.Errorf("failed to parse Type 13 BIOS Language: %w", err)
+	}
+
+	l.Languages = tableStrings(t, int(l.InstallableLanguages))
+
+	return l, nil
+}
+
+type BIOSLanguageFlags uint8
+
+// BIOSLanguageFlagsAbbreviated marks language names in the abbreviated
+// "enUS" format rather than "en|US|iso8859-1".
+const BIOSLanguageFlagsAbbreviated BIOSLanguageFlags = 1 << 0
+
+func (v BIOSLanguageFlags) String() string {
+	if v&BIOSLanguageFlagsAbbreviated != 0 {
+		return "Abbreviated"
+	}
+	return "Long"
+}
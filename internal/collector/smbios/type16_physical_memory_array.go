@@ -0,0 +1,153 @@
+package smbios
+
+import "fmt"
+
+type Type16PhysicalMemoryArray struct {
+	Header                  `smbios:"-"`
+	Location                MemoryArrayLocation        // 04h
+	Use                     MemoryArrayUse             // 05h
+	ErrorCorrection         MemoryArrayErrorCorrection // 06h
+	MaximumCapacity         uint32                     // 07h
+	MemoryErrorInfoHandle   uint16                     // 0Bh
+	NumberOfMemoryDevices   uint16                     // 0Dh
+	ExtendedMaximumCapacity uint64                     // 0Fh
+}
+
+func parseType16PhysicalMemoryArray(t *Table) (*Type16PhysicalMemoryArray, error) {
+	if t.Header.Type != 16 {
+		return nil, fmt.Errorf("%s:%d", ErrInvalidTableType, t.Header.Type)
+	}
+
+	if t.Header.Length < 0x0F {
+		return nil, fmt.Errorf("%s: physical memory array table must be at least %d bytes", ErrInvalidTableLength, 0x0F)
+	}
+
+	ma := &Type16PhysicalMemoryArray{
+		Header: t.Header,
+	}
+
+	if _, err := parseType(t, 0, false, ma); err != nil {
+		return nil, fmt.Errorf("failed to parse Type 16 Physical Memory Array: %w", err)
+	}
+
+	return ma, nil
+}
+
+// GetMaximumCapacity returns the maximum capacity in bytes. 0x80000000 in the
+// KB field defers to the extended field, which is in bytes.
+func (t *Type16PhysicalMemoryArray) GetMaximumCapacity() uint64 {
+	if t.MaximumCapacity == 0x80000000 {
+		return t.ExtendedMaximumCapacity
+	}
+	return uint64(t.MaximumCapacity) * KB
+}
+
+func (t *Type16PhysicalMemoryArray) GetMaximumCapacityString() string {
+	if v := t.GetMaximumCapacity(); v != 0 {
+		return kgmt(v)
+	}
+	return "Unknown"
+}
+
+type MemoryArrayLocation uint8
+
+const (
+	MemoryArrayLocationOther             MemoryArrayLocation = 0x01 // Other
+	MemoryArrayLocationUnknown           MemoryArrayLocation = 0x02 // Unknown
+	MemoryArrayLocationSystemBoard       MemoryArrayLocation = 0x03 // System board or motherboard
+	MemoryArrayLocationISAAddOnCard      MemoryArrayLocation = 0x04 // ISA add-on card
+	MemoryArrayLocationEISAAddOnCard     MemoryArrayLocation = 0x05 // EISA add-on card
+	MemoryArrayLocationPCIAddOnCard      MemoryArrayLocation = 0x06 // PCI add-on card
+	MemoryArrayLocationMCAAddOnCard      MemoryArrayLocation = 0x07 // MCA add-on card
+	MemoryArrayLocationPCMCIAAddOnCard   MemoryArrayLocation = 0x08 // PCMCIA add-on card
+	MemoryArrayLocationProprietaryAddOn  MemoryArrayLocation = 0x09 // Proprietary add-on card
+	MemoryArrayLocationNuBus             MemoryArrayLocation = 0x0a // NuBus
+	MemoryArrayLocationPC98C20AddOnCard  MemoryArrayLocation = 0xa0 // PC-98/C20 add-on card
+	MemoryArrayLocationPC98C24AddOnCard  MemoryArrayLocation = 0xa1 // PC-98/C24 add-on card
+	MemoryArrayLocationPC98EAddOnCard    MemoryArrayLocation = 0xa2 // PC-98/E add-on card
+	MemoryArrayLocationPC98LocalBusAddOn MemoryArrayLocation = 0xa3 // PC-98/Local bus add-on card
+	MemoryArrayLocationCXLAddOnCard      MemoryArrayLocation = 0xa4 // CXL add-on card
+)
+
+var memoryArrayLocationNames = map[MemoryArrayLocation]string{
+	MemoryArrayLocationOther:             "Other",
+	MemoryArrayLocationUnknown:           "Unknown",
+	MemoryArrayLocationSystemBoard:       "System Board Or Motherboard",
+	MemoryArrayLocationISAAddOnCard:      "ISA Add-on Card",
+	MemoryArrayLocationEISAAddOnCard:     "EISA Add-on Card",
+	MemoryArrayLocationPCIAddOnCard:      "PCI Add-on Card",
+	MemoryArrayLocationMCAAddOnCard:      "MCA Add-on Card",
+	MemoryArrayLocationPCMCIAAddOnCard:   "PCMCIA Add-on Card",
+	MemoryArrayLocationProprietaryAddOn:  "Proprietary Add-on Card",
+	MemoryArrayLocationNuBus:             "NuBus",
+	MemoryArrayLocationPC98C20AddOnCard:  "PC-98/C20 Add-on Card",
+	MemoryArrayLocationPC98C24AddOnCard:  "PC-98/C24 Add-on Card",
+	MemoryArrayLocationPC98EAddOnCard:    "PC-98/E Add-on Card",
+	MemoryArrayLocationPC98LocalBusAddOn: "PC-98/Local Bus Add-on Card",
+	MemoryArrayLocationCXLAddOnCard:      "CXL Add-on Card",
+}
+
+func (v MemoryArrayLocation) String() string {
+	if name, ok := memoryArrayLocationNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("%#x", uint8(v))
+}
+
+type MemoryArrayUse uint8
+
+const (
+	MemoryArrayUseOther          MemoryArrayUse = 0x01 // Other
+	MemoryArrayUseUnknown        MemoryArrayUse = 0x02 // Unknown
+	MemoryArrayUseSystemMemory   MemoryArrayUse = 0x03 // System memory
+	MemoryArrayUseVideoMemory    MemoryArrayUse = 0x04 // Video memory
+	MemoryArrayUseFlashMemory    MemoryArrayUse = 0x05 // Flash memory
+	MemoryArrayUseNonVolatileRAM MemoryArrayUse = 0x06 // Non-volatile RAM
+	MemoryArrayUseCacheMemory    MemoryArrayUse = 0x07 // Cache memory
+)
+
+var memoryArrayUseNames = map[MemoryArrayUse]string{
+	MemoryArrayUseOther:          "Other",
+	MemoryArrayUseUnknown:        "Unknown",
+	MemoryArrayUseSystemMemory:   "System Memory",
+	MemoryArrayUseVideoMemory:    "Video Memory",
+	MemoryArrayUseFlashMemory:    "Flash Memory",
+	MemoryArrayUseNonVolatileRAM: "Non-volatile RAM",
+	MemoryArrayUseCacheMemory:    "Cache Memory",
+}
+
+func (v MemoryArrayUse) String() string {
+	if name, ok := memoryArrayUseNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("%#x", uint8(v))
+}
+
+type MemoryArrayErrorCorrection uint8
+
+const (
+	MemoryArrayErrorCorrectionOther        MemoryArrayErrorCorrection = 0x01 // Other
+	MemoryArrayErrorCorrectionUnknown      MemoryArrayErrorCorrection = 0x02 // Unknown
+	MemoryArrayErrorCorrectionNone         MemoryArrayErrorCorrection = 0x03 // None
+	MemoryArrayErrorCorrectionParity       MemoryArrayErrorCorrection = 0x04 // Parity
+	MemoryArrayErrorCorrectionSingleBitECC MemoryArrayErrorCorrection = 0x05 // Single-bit ECC
+	MemoryArrayErrorCorrectionMultiBitECC  MemoryArrayErrorCorrection = 0x06 // Multi-bit ECC
+	MemoryArrayErrorCorrectionCRC          MemoryArrayErrorCorrection = 0x07 // CRC
+)
+
+var memoryArrayErrorCorrectionNames = map[MemoryArrayErrorCorrection]string{
+	MemoryArrayErrorCorrectionOther:        "Other",
+	MemoryArrayErrorCorrectionUnknown:      "Unknown",
+	MemoryArrayErrorCorrectionNone:         "None",
+	MemoryArrayErrorCorrectionParity:       "Parity",
+	MemoryArrayErrorCorrectionSingleBitECC: "Single-bit ECC",
+	MemoryArrayErrorCorrectionMultiBitECC:  "Multi-bit ECC",
+	MemoryArrayErrorCorrectionCRC:          "CRC",
+}
+
+func (v MemoryArrayErrorCorrection) String() string {
+	if name, ok := memoryArrayErrorCorrectionNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("%#x", uint8(v))
+}
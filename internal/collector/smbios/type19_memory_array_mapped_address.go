@@ -0,0 +1,57 @@
+package smbios
+
+import "fmt"
+
+type Type19MemoryArrayMappedAddress struct {
+	Header                  `smbios:"-"`
+	StartingAddress         uint32 // 04h
+	EndingAddress           uint32 // 08h
+	MemoryArrayHandle       uint16 // 0Ch
+	PartitionWidth          uint8  // 0Eh
+	ExtendedStartingAddress uint64 // 0Fh
+	ExtendedEndingAddress   uint64 // 17h
+}
+
+func parseType19MemoryArrayMappedAddress(t *Table) (*Type19MemoryArrayMappedAddress, error) {
+	if t.Header.Type != 19 {
+		return nil, fmt.Errorf("%s:%d", ErrInvalidTableType, t.Header.Type)
+	}
+
+	if t.Header.Length < 0x0F {
+		return nil, fmt.Errorf("%s: memory array mapped address table must be at least %d bytes", ErrInvalidTableLength, 0x0F)
+	}
+
+	ma := &Type19MemoryArrayMappedAddress{
+		Header: t.Header,
+	}
+
+	if _, err := parseType(t, 0, false, ma); err != nil {
+		return nil, fmt.Errorf("failed to parse Type 19 Memory Array Mapped Address: %w", err)
+	}
+
+	return ma, nil
+}
+
+// GetRange returns the first and last mapped byte address.
+func (t *Type19MemoryArrayMappedAddress) GetRange() (uint64, uint64) {
+	return mappedRange(t.StartingAddress, t.EndingAddress, t.ExtendedStartingAddress, t.ExtendedEndingAddress)
+}
+
+// GetSize returns the size of the mapped range in bytes.
+func (t *Type19MemoryArrayMappedAddress) GetSize() uint64 {
+	start, end := t.GetRange()
+	if end < start {
+		return 0
+	}
+	return end - start + 1
+}
+
+// mappedRange decodes the address range shared by Types 19 and 20: KB
+// addresses, or byte addresses in the extended fields when the starting
+// address is 0xFFFFFFFF.
+func mappedRange(start, end uint32, extStart, extEnd uint64) (uint64, uint64) {
+	if start == 0xFFFFFFFF {
+		return extStart, extEnd
+	}
+	return uint64(start) * KB, uint64(end)*KB + KB - 1
+}
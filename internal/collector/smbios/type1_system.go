@@ -44,12 +44,17 @@ func (u *UUID) parseField(t *Table, off int) (int, error) {
 	return off + 16, nil
 }
 
+const (
+	uuidNotPresent  = "Not Present"
+	uuidNotSettable = "Not Settable"
+)
+
 func (u UUID) String() string {
-	if bytes.Equal(u[:], []byte{0, 0, 0, 0, 0, 0, 0, 0}) {
-		return "Not Present"
+	if bytes.Equal(u[:], bytes.Repeat([]byte{0}, 16)) {
+		return uuidNotPresent
 	}
-	if bytes.Equal(u[:], []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}) {
-		return "Not Settable"
+	if bytes.Equal(u[:], bytes.Repeat([]byte{0xff}, 16)) {
+		return uuidNotSettable
 	}
 	return fmt.Sprintf("%02x%02x%02x%02x-%02x%02x-%02x%02x-%02x%02x-%02x%02x%02x%02x%02x%02x",
 		u[3], u[2], u[1], u[0],
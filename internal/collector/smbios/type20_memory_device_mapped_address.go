@@ -0,0 +1,50 @@
+package smbios
+
+import "fmt"
+
+type Type20MemoryDeviceMappedAddress struct {
+	Header                         `smbios:"-"`
+	StartingAddress                uint32 // 04h
+	EndingAddress                  uint32 // 08h
+	MemoryDeviceHandle             uint16 // 0Ch
+	MemoryArrayMappedAddressHandle uint16 // 0Eh
+	PartitionRowPosition           uint8  // 10h
+	InterleavePosition             uint8  // 11h
+	InterleavedDataDepth           uint8  // 12h
+	ExtendedStartingAddress        uint64 // 13h
+	ExtendedEndingAddress          uint64 // 1Bh
+}
+
+func parseType20MemoryDeviceMappedAddress(t *Table) (*Type20MemoryDeviceMappedAddress, error) {
+	if t.Header.Type != 20 {
+		return nil, fmt.Errorf("%s:%d", ErrInvalidTableType, t.Header.Type)
+	}
+
+	if t.Header.Length < 0x13 {
+		return nil, fmt.Errorf("%s: memory device mapped address table must be at least %d bytes", ErrInvalidTableLength, 0x13)
+	}
+
+	md := &Type20MemoryDeviceMappedAddress{
+		Header: t.Header,
+	}
+
+	if _, err := parseType(t, 0, false, md); err != nil {
+		return nil, fmt.Errorf("failed to parse Type 20 Memory Device Mapped Address: %w", err)
+	}
+
+	return md, nil
+}
+
+// GetRange returns the first and last mapped byte address.
+func (t *Type20MemoryDeviceMappedAddress) GetRange() (uint64, uint64) {
+	return mappedRange(t.StartingAddress, t.EndingAddress, t.ExtendedStartingAddress, t.ExtendedEndingAddress)
+}
+
+// GetSize returns the size of the mapped range in bytes.
+func (t *Type20MemoryDeviceMappedAddress) GetSize() uint64 {
+	start, end := t.GetRange()
+	if end < start {
+		return 0
+	}
+	return end - start + 1
+}
@@ -0,0 +1,119 @@
+package smbios
+
+import "fmt"
+
+type Type38IPMIDevice struct {
+	Header                 `smbios:"-"`
+	InterfaceType          IPMIInterfaceType // 04h
+	SpecificationRevision  uint8             // 05h
+	I2CTargetAddress       uint8             // 06h
+	NVStorageDeviceAddress uint8             // 07h
+	BaseAddress            uint64            // 08h
+	BaseAddressModifier    uint8             // 10h
+	InterruptNumber        uint8             // 11h
+}
+
+func parseType38IPMIDevice(t *Table) (*Type38IPMIDevice, error) {
+	if t.Header.Type != 38 {
+		return nil, fmt.Errorf("%s:%d", ErrInvalidTableType, t.Header.Type)
+	}
+
+	if t.Header.Length < 0x10 {
+		return nil, fmt.Errorf("%s: IPMI device table must be at least %d bytes", ErrInvalidTableLength, 0x10)
+	}
+
+	d := &Type38IPMIDevice{
+		Header: t.Header,
+	}
+
+	if _, err := parseType(t, 0, false, d); err != nil {
+		return nil, fmt.Errorf("failed to parse Type 38 IPMI Device: %w", err)
+	}
+
+	return d, nil
+}
+
+// GetSpecificationVersion returns the IPMI version, e.g. "2.0".
+func (d *Type38IPMIDevice) GetSpecificationVersion() string {
+	return fmt.Sprintf("%d.%d", d.SpecificationRevision>>4, d.SpecificationRevision&0x0F)
+}
+
+// IsIOSpace reports whether the base address is in I/O space rather than
+// memory-mapped. It is meaningless for SSIF.
+func (d *Type38IPMIDevice) IsIOSpace() bool {
+	return d.BaseAddress&1 != 0
+}
+
+// GetBaseAddress returns the interface base address. For SSIF it is the
+// SMBus target address; otherwise bit 0 is replaced by the LSB carried in
+// bit 4 of the modifier.
+func (d *Type38IPMIDevice) GetBaseAddress() uint64 {
+	if d.InterfaceType == IPMIInterfaceTypeSSIF {
+		return d.BaseAddress >> 1
+	}
+	return d.BaseAddress&^1 | uint64(d.BaseAddressModifier>>4)&1
+}
+
+// GetRegisterSpacing returns the register spacing from the modifier, or ""
+// when the structure has no modifier field.
+func (d *Type38IPMIDevice) GetRegisterSpacing() string {
+	if d.Length < 0x12 {
+		return ""
+	}
+	switch d.BaseAddressModifier >> 6 {
+	case 0:
+		return "Successive Byte Boundaries"
+	case 1:
+		return "32-bit Boundaries"
+	case 2:
+		return "16-byte Boundaries"
+	}
+	return "Unknown"
+}
+
+// HasInterrupt reports whether the interrupt polarity, trigger mode and
+// number are specified.
+func (d *Type38IPMIDevice) HasInterrupt() bool {
+	return d.Length >= 0x12 && d.BaseAddressModifier&(1<<3) != 0
+}
+
+// GetInterruptPolarity returns "Active High" or "Active Low".
+func (d *Type38IPMIDevice) GetInterruptPolarity() string {
+	if d.BaseAddressModifier&(1<<1) != 0 {
+		return "Active High"
+	}
+	return "Active Low"
+}
+
+// GetInterruptTriggerMode returns "Level" or "Edge".
+func (d *Type38IPMIDevice) GetInterruptTriggerMode() string {
+	if d.BaseAddressModifier&1 != 0 {
+		return "Level"
+	}
+	return "Edge"
+}
+
+type IPMIInterfaceType uint8
+
+const (
+	IPMIInterfaceTypeUnknown IPMIInterfaceType = 0x00
+	IPMIInterfaceTypeKCS     IPMIInterfaceType = 0x01
+	IPMIInterfaceTypeSMIC    IPMIInterfaceType = 0x02
+	IPMIInterfaceTypeBT      IPMIInterfaceType = 0x03
+	IPMIInterfaceTypeSSIF    IPMIInterfaceType = 0x04
+)
+
+var ipmiInterfaceTypeNames = map[IPMIInterfaceType]string{
+	IPMIInterfaceTypeUnknown: "Unknown",
+	IPMIInterfaceTypeKCS:     "KCS (Keyboard Control Style)",
+	IPMIInterfaceTypeSMIC:    "SMIC (Server Management Interface Chip)",
+	IPMIInterfaceTypeBT:      "BT (Block Transfer)",
+	IPMIInterfaceTypeSSIF:    "SSIF (SMBus System Interface)",
+}
+
+func (v IPMIInterfaceType) String() string {
+	if name, ok := ipmiInterfaceTypeNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("%#x", uint8(v))
+}
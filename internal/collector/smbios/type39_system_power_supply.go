@@ -0,0 +1,91 @@
+package smbios
+
+import "fmt"
+
+type Type39SystemPowerSupply struct {
+	Header                     `smbios:"-"`
+	PowerUnitGroup             uint8                      // 04h
+	Location                   string                     // 05h
+	DeviceName                 string                     // 06h
+	Manufacturer               string                     // 07h
+	SerialNumber               string                     // 08h
+	AssetTagNumber             string                     // 09h
+	ModelPartNumber            string                     // 0Ah
+	RevisionLevel              string                     // 0Bh
+	MaxPowerCapacity           uint16                     // 0Ch
+	PowerSupplyCharacteristics PowerSupplyCharacteristics // 0Eh
+	InputVoltageProbeHandle    uint16                     `smbios:"default=0xffff"` // 10h
+	CoolingDeviceHandle        uint16                     `smbios:"default=0xffff"` // 12h
+	InputCurrentProbeHandle    uint16                     `smbios:"default=0xffff"` // 14h
+}
+
+func parseType39SystemPowerSupply(t *Table) (*Type39SystemPowerSupply, error) {
+	if t.Header.Type != 39 {
+		return nil, fmt.Errorf("%s:%d", ErrInvalidTableType, t.Header.Type)
+	}
+
+	if t.Header.Length < 0x10 {
+		return nil, fmt.Errorf("%s: system power supply table must be at least %d bytes", ErrInvalidTableLength, 0x10)
+	}
+
+	ps := &Type39SystemPowerSupply{
+		Header: t.Header,
+	}
+
+	if _, err := parseType(t, 0, false, ps); err != nil {
+		return nil, fmt.Errorf("failed to parse Type 39 System Power Supply: %w", err)
+	}
+
+	return ps, nil
+}
+
+// GetMaxPowerCapacityString returns the capacity in watts; 0x8000 is unknown.
+func (ps *Type39SystemPowerSupply) GetMaxPowerCapacityString() string {
+	if ps.MaxPowerCapacity == 0x8000 {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%d W", ps.MaxPowerCapacity)
+}
+
+// PowerSupplyCharacteristics packs the type (bits 13:10), status (9:7),
+// input voltage range switching (6:3), unplugged (2), present (1) and
+// hot replaceable (0) flags.
+type PowerSupplyCharacteristics uint16
+
+var (
+	powerSupplyTypeNames      = []string{"", "Other", "Unknown", "Linear", "Switching", "Battery", "UPS", "Converter", "Regulator"}
+	powerSupplyStatusNames    = []string{"", "Other", "Unknown", "OK", "Non-critical", "Critical"}
+	powerSupplySwitchingNames = []string{"", "Other", "Unknown", "Manual", "Auto-switch", "Wide Range", "N/A"}
+)
+
+func (v PowerSupplyCharacteristics) GetType() string {
+	return indexName(int(v>>10&0x0F), powerSupplyTypeNames)
+}
+
+func (v PowerSupplyCharacteristics) GetStatus() string {
+	return indexName(int(v>>7&0x07), powerSupplyStatusNames)
+}
+
+func (v PowerSupplyCharacteristics) GetInputVoltageRangeSwitching() string {
+	return indexName(int(v>>3&0x0F), powerSupplySwitchingNames)
+}
+
+func (v PowerSupplyCharacteristics) IsPlugged() bool {
+	return v&(1<<2) == 0
+}
+
+func (v PowerSupplyCharacteristics) IsPresent() bool {
+	return v&(1<<1) != 0
+}
+
+func (v PowerSupplyCharacteristics) IsHotReplaceable() bool {
+	return v&1 != 0
+}
+
+// indexName returns names[i], or "<OUT OF SPEC>" for reserved values.
+func indexName(i int, names []string) string {
+	if i > 0 && i < len(names) {
+		return names[i]
+	}
+	return "<OUT OF SPEC>"
+}
@@ -0,0 +1,79 @@
+package smbios
+
+import "fmt"
+
+type Type41OnboardDevicesExtended struct {
+	Header               `smbios:"-"`
+	ReferenceDesignation string            // 04h
+	DeviceType           OnboardDeviceType // 05h
+	DeviceTypeInstance   uint8             // 06h
+	SegmentGroupNumber   uint16            // 07h
+	BusNumber            uint8             // 09h
+	DeviceFunctionNumber uint8             // 0Ah
+}
+
+func parseType41OnboardDevicesExtended(t *Table) (*Type41OnboardDevicesExtended, error) {
+	if t.Header.Type != 41 {
+		return nil, fmt.Errorf("%s:%d", ErrInvalidTableType, t.Header.Type)
+	}
+
+	if t.Header.Length < 0x0B {
+		return nil, fmt.Errorf("%s: onboard devices extended table must be at least %d bytes", ErrInvalidTableLength, 0x0B)
+	}
+
+	d := &Type41OnboardDevicesExtended{
+		Header: t.Header,
+	}
+
+	if _, err := parseType(t, 0, false, d); err != nil {
+		return nil, fmt.Errorf("failed to parse Type 41 Onboard Devices Extended Information: %w", err)
+	}
+
+	return d, nil
+}
+
+// HasPCIAddress reports whether the device carries a PCI address; 0xFF bus
+// and device/function mean not applicable.
+func (d *Type41OnboardDevicesExtended) HasPCIAddress() bool {
+	return !(d.BusNumber == 0xFF && d.DeviceFunctionNumber == 0xFF)
+}
+
+// GetPCIAddress returns the device address in sysfs form, e.g.
+// "0000:19:00.0", or "" if the device has none.
+func (d *Type41OnboardDevicesExtended) GetPCIAddress() string {
+	if !d.HasPCIAddress() {
+		return ""
+	}
+	return pciAddress(d.SegmentGroupNumber, d.BusNumber, d.DeviceFunctionNumber)
+}
+
+// OnboardDeviceType holds the device status in bit 7 and the type in bits 6:0.
+type OnboardDeviceType uint8
+
+var onboardDeviceTypeNames = []string{
+	"",
+	"Other",
+	"Unknown",
+	"Video",
+	"SCSI Controller",
+	"Ethernet",
+	"Token Ring",
+	"Sound",
+	"PATA Controller",
+	"SATA Controller",
+	"SAS Controller",
+	"Wireless LAN",
+	"Bluetooth",
+	"WWAN",
+	"eMMC",
+	"NVMe Controller",
+	"UFS Controller",
+}
+
+func (v OnboardDeviceType) IsEnabled() bool {
+	return v&0x80 != 0
+}
+
+func (v OnboardDeviceType) String() string {
+	return indexName(int(v&0x7F), onboardDeviceTypeNames)
+}
@@ -0,0 +1,98 @@
+package smbios
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Type43TPMDevice struct {
+	Header           `smbios:"-"`
+	VendorID         uint32                   // 04h
+	MajorSpecVersion uint8                    // 08h
+	MinorSpecVersion uint8                    // 09h
+	FirmwareVersion1 uint32                   // 0Ah
+	FirmwareVersion2 uint32                   // 0Eh
+	Description      string                   // 12h
+	Characteristics  TPMDeviceCharacteristics // 13h
+	OEMDefined       uint32                   // 1Bh
+}
+
+func parseType43TPMDevice(t *Table) (*Type43TPMDevice, error) {
+	if t.Header.Type != 43 {
+		return nil, fmt.Errorf("%s:%d", ErrInvalidTableType, t.Header.Type)
+	}
+
+	if t.Header.Length < 0x1F {
+		return nil, fmt.Errorf("%s: TPM device table must be at least %d bytes", ErrInvalidTableLength, 0x1F)
+	}
+
+	d := &Type43TPMDevice{
+		Header: t.Header,
+	}
+
+	if _, err := parseType(t, 0, false, d); err != nil {
+		return nil, fmt.Errorf("failed to parse Type 43 TPM Device: %w", err)
+	}
+
+	return d, nil
+}
+
+// GetVendorID returns the four ASCII character TCG vendor ID, e.g. "IFX".
+func (d *Type43TPMDevice) GetVendorID() string {
+	var b strings.Builder
+	for i := 0; i < 4; i++ {
+		c := byte(d.VendorID >> (8 * i))
+		if c < 0x20 || c > 0x7E {
+			break
+		}
+		b.WriteByte(c)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// GetSpecVersion returns the TPM family, e.g. "2.0".
+func (d *Type43TPMDevice) GetSpecVersion() string {
+	return fmt.Sprintf("%d.%d", d.MajorSpecVersion, d.MinorSpecVersion)
+}
+
+// GetFirmwareVersion decodes the vendor firmware revision. TPM 1.2 stores a
+// TPM_VERSION structure, TPM 2.0 a 16.16 major/minor pair.
+func (d *Type43TPMDevice) GetFirmwareVersion() string {
+	switch d.MajorSpecVersion {
+	case 0x01:
+		return fmt.Sprintf("%d.%d", d.FirmwareVersion1>>16&0xFF, d.FirmwareVersion1>>24)
+	case 0x02:
+		return fmt.Sprintf("%d.%d", d.FirmwareVersion1>>16, d.FirmwareVersion1&0xFFFF)
+	}
+	return ""
+}
+
+type TPMDeviceCharacteristics uint64
+
+// TPMDeviceCharacteristicsNotSupported means no other bit is meaningful.
+const TPMDeviceCharacteristicsNotSupported TPMDeviceCharacteristics = 1 << 2
+
+var tpmDeviceCharacteristicsStr = map[TPMDeviceCharacteristics]string{
+	1 << 2: "TPM Device characteristics not supported",
+	1 << 3: "Family configurable via firmware update",
+	1 << 4: "Family configurable via platform software support",
+	1 << 5: "Family configurable via OEM proprietary mechanism",
+}
+
+func (v TPMDeviceCharacteristics) StringList() []string {
+	if v&TPMDeviceCharacteristicsNotSupported != 0 {
+		return []string{tpmDeviceCharacteristicsStr[TPMDeviceCharacteristicsNotSupported]}
+	}
+
+	var res []string
+	for i := 3; i <= 5; i++ {
+		if v&(1<<i) != 0 {
+			res = append(res, tpmDeviceCharacteristicsStr[1<<i])
+		}
+	}
+	return res
+}
+
+func (v TPMDeviceCharacteristics) String() string {
+	return strings.Join(v.StringList(), ", ")
+}
@@ -2,6 +2,7 @@ package smbios
 
 import (
 	"fmt"
+	"strings"
 )
 
 type Type4Processor struct {
@@ -62,6 +63,106 @@ func (p *Type4Processor) GetFamily() ProcessorFamily {
 	return ProcessorFamily(p.Family)
 }
 
+// IsARM reports whether the processor belongs to an ARM family.
+func (p *Type4Processor) IsARM() bool {
+	switch p.GetFamily() {
+	case ProcessorFamilyARMv7, ProcessorFamilyARMv8, ProcessorFamilyARMv9,
+		ProcessorFamilyARM, ProcessorFamilyStrongARM:
+		return true
+	}
+	return false
+}
+
+// x86Manufacturers maps lower-case manufacturer substrings to the x86 vendor.
+// Hygon and Zhaoxin firmware often reports the processor family as "Other"
+// or an out-of-spec value, so the manufacturer string identifies them.
+var x86Manufacturers = []struct {
+	keyword string
+	vendor  string
+}{
+	{"intel", "Intel"},
+	{"advanced micro devices", "AMD"},
+	{"amd", "AMD"},
+	{"hygon", "Hygon"},
+	{"haiguang", "Hygon"},
+	{"zhaoxin", "Zhaoxin"},
+	{"shanghai", "Zhaoxin"},
+	{"centaur", "Zhaoxin"},
+}
+
+// GetX86Vendor returns the x86 vendor derived from the manufacturer string,
+// or an empty string for non-x86 processors.
+func (p *Type4Processor) GetX86Vendor() string {
+	if p.IsARM() {
+		return ""
+	}
+
+	m := strings.ToLower(p.Manufacturer)
+	for _, v := range x86Manufacturers {
+		if strings.Contains(m, v.keyword) {
+			return v.vendor
+		}
+	}
+	return ""
+}
+
+// GetFamilyName returns the processor family name. Hygon and Zhaoxin
+// processors have no family code in DSP0134, so when the firmware reports
+// "Other", "Unknown" or an undefined value the vendor family is returned instead.
+func (p *Type4Processor) GetFamilyName() string {
+	family := p.GetFamily()
+	_, known := processorFamilyStr[family]
+	if known && family != ProcessorFamilyOther && family != ProcessorFamilyUnknown {
+		return family.String()
+	}
+
+	switch p.GetX86Vendor() {
+	case "Hygon":
+		return "Hygon Dhyana"
+	case "Zhaoxin":
+		return "Zhaoxin"
+	}
+	return family.String()
+}
+
+// GetSignature decodes the Processor ID field (DSP0134 7.5.3). For x86 the low
+// DWORD is the CPUID leaf 1 EAX signature. For ARM, when the ARM64 SoC ID
+// characteristic is set, the field holds the SMCCC SoC_ID version in the low
+// DWORD and the SoC revision in the high DWORD; otherwise the low DWORD is the
+// MIDR_EL1 register. An empty string is returned for other architectures or
+// when the field is not filled in.
+func (p *Type4Processor) GetSignature() string {
+	low := uint32(p.ID)
+	if low == 0 {
+		return ""
+	}
+
+	if p.GetX86Vendor() != "" {
+		family := (low >> 8) & 0xf
+		model := (low >> 4) & 0xf
+		if family == 0xf {
+			family += (low >> 20) & 0xff
+		}
+		if family == 0x6 || family >= 0xf {
+			model |= (low >> 12) & 0xf0
+		}
+		return fmt.Sprintf("Type %d, Family %d, Model %d, Stepping %d",
+			(low>>12)&0x3, family, model, low&0xf)
+	}
+
+	if !p.IsARM() {
+		return ""
+	}
+
+	if p.Characteristics&ProcessorCharacteristicsArm64SoCID != 0 {
+		return fmt.Sprintf("JEP-106 Continuation 0x%02x Code 0x%02x, SoC ID 0x%04x, SoC Revision 0x%08x",
+			(low>>24)&0x7f, (low>>16)&0x7f, low&0xffff, uint32(p.ID>>32))
+	}
+
+	return fmt.Sprintf("Implementor 0x%02x, Variant 0x%x, Architecture %d, Part 0x%03x, Revision %d",
+		low>>24, (low>>20)&0xf, (low>>16)&0xf, (low>>4)&0xfff, low&0xf)
+}
+
 func (p *Type4Processor) GetVoltage() float32 {
 	if p.Voltage&0x80 == 0 {
 		switch {
@@ -332,6 +433,7 @@ const (
 	ProcessorFamilyI960                         ProcessorFamily = 0xfb  // i960
 	ProcessorFamilyARMv7                        ProcessorFamily = 0x100 // ARMv7
 	ProcessorFamilyARMv8                        ProcessorFamily = 0x101 // ARMv8
+	ProcessorFamilyARMv9                        ProcessorFamily = 0x102 // ARMv9
 	ProcessorFamilySH3                          ProcessorFamily = 0x104 // SH-3
 	ProcessorFamilySH4                          ProcessorFamily = 0x105 // SH-4
 	ProcessorFamilyARM                          ProcessorFamily = 0x118 // ARM
@@ -575,6 +677,7 @@ var processorFamilyStr = map[ProcessorFamily]string{
 	ProcessorFamilyI960:                         "i960",
 	ProcessorFamilyARMv7:                        "ARMv7",
 	ProcessorFamilyARMv8:                        "ARMv8",
+	ProcessorFamilyARMv9:                        "ARMv9",
 	ProcessorFamilySH3:                          "SH-3",
 	ProcessorFamilySH4:                          "SH-4",
 	ProcessorFamilyARM:                          "ARM",
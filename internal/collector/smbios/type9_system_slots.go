@@ -0,0 +1,346 @@
+package smbios
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Type9SystemSlots struct {
+	Header               `smbios:"-"`
+	SlotDesignation      string               // 04h
+	SlotType             SlotType             // 05h
+	SlotDataBusWidth     SlotWidth            // 06h
+	CurrentUsage         SlotUsage            // 07h
+	SlotLength           SlotLength           // 08h
+	SlotID               uint16               // 09h
+	Characteristics1     SlotCharacteristics1 // 0Bh
+	Characteristics2     SlotCharacteristics2 // 0Ch
+	SegmentGroupNumber   uint16               `smbios:"default=0xffff"` // 0Dh
+	BusNumber            uint8                `smbios:"default=0xff"`   // 0Fh
+	DeviceFunctionNumber uint8                `smbios:"default=0xff"`   // 10h
+	DataBusWidth         uint8                // 11h
+	PeerGroups           SlotPeerGroups       // 12h
+	SlotInformation      uint8                // 13h + 5*n
+	SlotPhysicalWidth    SlotWidth            // 14h + 5*n
+	SlotPitch            uint16               // 15h + 5*n
+	SlotHeight           SlotHeight           // 17h + 5*n
+}
+
+func parseType9SystemSlots(t *Table) (*Type9SystemSlots, error) {
+	if t.Header.Type != 9 {
+		return nil, fmt.Errorf("%s:%d", ErrInvalidTableType, t.Header.Type)
+	}
+
+	if t.Header.Length < 0x0C {
+		return nil, fmt.Errorf("%s: system slots table must be at least %d bytes", ErrInvalidTableLength, 0x0C)
+	}
+
+	s := &Type9SystemSlots{
+		Header: t.Header,
+	}
+
+	if _, err := parseType(t, 0, false, s); err != nil {
+		return nil, fmt.Errorf("failed to parse Type 9 System Slots: %w", err)
+	}
+
+	return s, nil
+}
+
+// HasPCIAddress reports whether the slot carries a PCI segment, bus and
+// device/function; 0xFF bus and device/function mean not applicable.
+func (s *Type9SystemSlots) HasPCIAddress() bool {
+	return !(s.BusNumber == 0xFF && s.DeviceFunctionNumber == 0xFF)
+}
+
+// GetPCIAddress returns the address of the slot's root or downstream port
+// in sysfs form, e.g. "0000:3a:02.0", or "" if the slot has none.
+func (s *Type9SystemSlots) GetPCIAddress() string {
+	if !s.HasPCIAddress() {
+		return ""
+	}
+	return pciAddress(s.SegmentGroupNumber, s.BusNumber, s.DeviceFunctionNumber)
+}
+
+// pciAddress formats a segment, bus and device/function number as used by
+// Types 9 and 41.
+func pciAddress(segment uint16, bus, devfn uint8) string {
+	return fmt.Sprintf("%04x:%02x:%02x.%x", segment, bus, devfn>>3, devfn&0x07)
+}
+
+// SlotPeerGroup is a device that shares the slot's lanes, e.g. with bifurcation.
+type SlotPeerGroup struct {
+	SegmentGroupNumber   uint16
+	BusNumber            uint8
+	DeviceFunctionNumber uint8
+	DataBusWidth         uint8
+}
+
+func (g SlotPeerGroup) String() string {
+	return fmt.Sprintf("%s (Width %d)", pciAddress(g.SegmentGroupNumber, g.BusNumber, g.DeviceFunctionNumber), g.DataBusWidth)
+}
+
+type SlotPeerGroups []SlotPeerGroup
+
+func (pg *SlotPeerGroups) parseField(t *Table, offset int) (int, error) {
+	num, err := t.GetByteAt(offset)
+	if err != nil {
+		return offset, err
+	}
+	offset++
+
+	for i := uint8(0); i < num; i++ {
+		b, err := t.GetBytesAt(offset, 5)
+		if err != nil {
+			return offset, err
+		}
+		*pg = append(*pg, SlotPeerGroup{
+			SegmentGroupNumber:   uint16(b[0]) | uint16(b[1])<<8,
+			BusNumber:            b[2],
+			DeviceFunctionNumber: b[3],
+			DataBusWidth:         b[4],
+		})
+		offset += 5
+	}
+	return offset, nil
+}
+
+type SlotType uint8
+
+var slotTypeNames = map[SlotType]string{
+	0x01: "Other",
+	0x02: "Unknown",
+	0x03: "ISA",
+	0x04: "MCA",
+	0x05: "EISA",
+	0x06: "PCI",
+	0x07: "PC Card (PCMCIA)",
+	0x08: "VLB",
+	0x09: "Proprietary",
+	0x0a: "Processor Card",
+	0x0b: "Proprietary Memory Card",
+	0x0c: "I/O Riser Card",
+	0x0d: "NuBus",
+	0x0e: "PCI-66",
+	0x0f: "AGP",
+	0x10: "AGP 2x",
+	0x11: "AGP 4x",
+	0x12: "PCI-X",
+	0x13: "AGP 8x",
+	0x14: "M.2 Socket 1-DP",
+	0x15: "M.2 Socket 1-SD",
+	0x16: "M.2 Socket 2",
+	0x17: "M.2 Socket 3",
+	0x18: "MXM Type I",
+	0x19: "MXM Type II",
+	0x1a: "MXM Type III",
+	0x1b: "MXM Type III-HE",
+	0x1c: "MXM Type IV",
+	0x1d: "MXM 3.0 Type A",
+	0x1e: "MXM 3.0 Type B",
+	0x1f: "PCI Express 2 SFF-8639 (U.2)",
+	0x20: "PCI Express 3 SFF-8639 (U.2)",
+	0x21: "PCI Express Mini 52-pin with bottom-side keep-outs",
+	0x22: "PCI Express Mini 52-pin without bottom-side keep-outs",
+	0x23: "PCI Express Mini 76-pin",
+	0x24: "PCI Express 4 SFF-8639 (U.2)",
+	0x25: "PCI Express 5 SFF-8639 (U.2)",
+	0x26: "OCP NIC 3.0 Small Form Factor (SFF)",
+	0x27: "OCP NIC 3.0 Large Form Factor (LFF)",
+	0x28: "OCP NIC Prior to 3.0",
+	0x30: "CXL FLexbus 1.0",
+	0xa0: "PC-98/C20",
+	0xa1: "PC-98/C24",
+	0xa2: "PC-98/E",
+	0xa3: "PC-98/Local Bus",
+	0xa4: "PC-98/Card",
+	0xa5: "PCI Express",
+	0xa6: "PCI Express x1",
+	0xa7: "PCI Express x2",
+	0xa8: "PCI Express x4",
+	0xa9: "PCI Express x8",
+	0xaa: "PCI Express x16",
+	0xab: "PCI Express 2",
+	0xac: "PCI Express 2 x1",
+	0xad: "PCI Express 2 x2",
+	0xae: "PCI Express 2 x4",
+	0xaf: "PCI Express 2 x8",
+	0xb0: "PCI Express 2 x16",
+	0xb1: "PCI Express 3",
+	0xb2: "PCI Express 3 x1",
+	0xb3: "PCI Express 3 x2",
+	0xb4: "PCI Express 3 x4",
+	0xb5: "PCI Express 3 x8",
+	0xb6: "PCI Express 3 x16",
+	0xb8: "PCI Express 4",
+	0xb9: "PCI Express 4 x1",
+	0xba: "PCI Express 4 x2",
+	0xbb: "PCI Express 4 x4",
+	0xbc: "PCI Express 4 x8",
+	0xbd: "PCI Express 4 x16",
+	0xbe: "PCI Express 5",
+	0xbf: "PCI Express 5 x1",
+	0xc0: "PCI Express 5 x2",
+	0xc1: "PCI Express 5 x4",
+	0xc2: "PCI Express 5 x8",
+	0xc3: "PCI Express 5 x16",
+	0xc4: "PCI Express 6+",
+	0xc5: "EDSFF E1",
+	0xc6: "EDSFF E3",
+}
+
+func (v SlotType) String() string {
+	if name, ok := slotTypeNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("%#x", uint8(v))
+}
+
+// IsPCIExpress reports whether the slot is a PCI Express, U.2, M.2, OCP or
+// EDSFF connector, i.e. one that holds a PCIe device.
+func (v SlotType) IsPCIExpress() bool {
+	switch {
+	case v >= 0x14 && v <= 0x17, v >= 0x1f && v <= 0x28, v == 0x30, v >= 0xa5 && v <= 0xc6:
+		return true
+	}
+	return false
+}
+
+type SlotWidth uint8
+
+var slotWidthNames = map[SlotWidth]string{
+	0x01: "Other",
+	0x02: "Unknown",
+	0x03: "8-bit",
+	0x04: "16-bit",
+	0x05: "32-bit",
+	0x06: "64-bit",
+	0x07: "128-bit",
+	0x08: "x1",
+	0x09: "x2",
+	0x0a: "x4",
+	0x0b: "x8",
+	0x0c: "x12",
+	0x0d: "x16",
+	0x0e: "x32",
+}
+
+func (v SlotWidth) String() string {
+	if name, ok := slotWidthNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("%#x", uint8(v))
+}
+
+// Lanes returns the PCI Express lane count of the width, or 0 for widths
+// that are not given in lanes.
+func (v SlotWidth) Lanes() int {
+	if v < 0x08 || v > 0x0e {
+		return 0
+	}
+	return []int{1, 2, 4, 8, 12, 16, 32}[v-0x08]
+}
+
+type SlotUsage uint8
+
+var slotUsageNames = map[SlotUsage]string{
+	0x01: "Other",
+	0x02: "Unknown",
+	0x03: "Available",
+	0x04: "In Use",
+	0x05: "Unavailable",
+}
+
+func (v SlotUsage) String() string {
+	if name, ok := slotUsageNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("%#x", uint8(v))
+}
+
+type SlotLength uint8
+
+var slotLengthNames = map[SlotLength]string{
+	0x01: "Other",
+	0x02: "Unknown",
+	0x03: "Short",
+	0x04: "Long",
+	0x05: "2.5\" drive form factor",
+	0x06: "3.5\" drive form factor",
+}
+
+func (v SlotLength) String() string {
+	if name, ok := slotLengthNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("%#x", uint8(v))
+}
+
+type SlotHeight uint8
+
+var slotHeightNames = map[SlotHeight]string{
+	0x00: "Not applicable",
+	0x01: "Other",
+	0x02: "Unknown",
+	0x03: "Full height",
+	0x04: "Low-profile",
+}
+
+func (v SlotHeight) String() string {
+	if name, ok := slotHeightNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("%#x", uint8(v))
+}
+
+type SlotCharacteristics1 uint8
+
+var slotCharacteristics1Str = []string{
+	"Characteristics Unknown",
+	"5.0 V is provided",
+	"3.3 V is provided",
+	"Opening is shared",
+	"PC Card-16 is supported",
+	"Cardbus is supported",
+	"Zoom Video is supported",
+	"Modem ring resume is supported",
+}
+
+func (v SlotCharacteristics1) StringList() []string {
+	return bitNames(uint8(v), slotCharacteristics1Str)
+}
+
+func (v SlotCharacteristics1) String() string {
+	return strings.Join(v.StringList(), ", ")
+}
+
+type SlotCharacteristics2 uint8
+
+var slotCharacteristics2Str = []string{
+	"PME signal is supported",
+	"Hot-plug devices are supported",
+	"SMBus signal is supported",
+	"PCIe slot bifurcation is supported",
+	"Async/surprise removal is supported",
+	"Flexbus slot, CXL 1.0 capable",
+	"Flexbus slot, CXL 2.0 capable",
+	"Flexbus slot, CXL 3.0 capable",
+}
+
+func (v SlotCharacteristics2) StringList() []string {
+	return bitNames(uint8(v), slotCharacteristics2Str)
+}
+
+func (v SlotCharacteristics2) String() string {
+	return strings.Join(v.StringList(), ", ")
+}
+
+// bitNames returns the names of the bits set in v, lowest bit first.
+func bitNames(v uint8, names []string) []string {
+	var res []string
+	for i, name := range names {
+		if v&(1<<i) != 0 {
+			res = append(res, name)
+		}
+	}
+	return res
+}
@@ -13,8 +13,10 @@ import (
 	"github.com/zenithax-cc/baize/internal/collector/gpu"
 	"github.com/zenithax-cc/baize/internal/collector/health"
 	"github.com/zenithax-cc/baize/internal/collector/ipmi"
+	"github.com/zenithax-cc/baize/internal/collector/mce"
 	"github.com/zenithax-cc/baize/internal/collector/memory"
 	"github.com/zenithax-cc/baize/internal/collector/network"
+	"github.com/zenithax-cc/baize/internal/collector/pci"
 	"github.com/zenithax-cc/baize/internal/collector/product"
 	"github.com/zenithax-cc/baize/internal/collector/raid"
 )
@@ -40,25 +42,31 @@ const (
 	ModuleTypeNetwork moduleType = "network"
 	ModuleTypeBond    moduleType = "bond"
 	ModuleTypeGPU     moduleType = "gpu"
+	ModuleTypePCI     moduleType = "pci"
 	ModuleTypeIPMI    moduleType = "ipmi"
+	ModuleTypeMCE     moduleType = "mce"
 	moduleTypeHealth  moduleType = "health"
 )
 
 // supportedModules is the ordered registry of all available collector modules.
-// Each entry pairs a module name with a freshly instantiated Collector.
+// Each entry pairs a module name with a freshly instantiated Collector; smbios
+// marks the modules that can decode an SMBIOS dump instead of this host.
 var supportedModules = []struct {
 	module    moduleType
 	collector Collector
+	smbios    bool
 }{
-	{ModuleTypeProduct, product.New()},
-	{ModuleTypeCPU, cpu.New()},
-	{ModuleTypeMemory, memory.New()},
-	{ModuleTypeRAID, raid.New()},
-	{ModuleTypeNetwork, network.New()},
-	{ModuleTypeBond, network.New()},
-	{ModuleTypeGPU, gpu.New()},
-	{ModuleTypeIPMI, ipmi.New()},
-	{moduleTypeHealth, health.New()},
+	{ModuleTypeProduct, product.New(), true},
+	{ModuleTypeCPU, cpu.New(), true},
+	{ModuleTypeMemory, memory.New(), true},
+	{ModuleTypeRAID, raid.New(), false},
+	{ModuleTypeNetwork, network.New(), false},
+	{ModuleTypeBond, network.New(), false},
+	{ModuleTypeGPU, gpu.New(), false},
+	{ModuleTypePCI, pci.NewTopology(), false},
+	{ModuleTypeIPMI, ipmi.New(), false},
+	{ModuleTypeMCE, mce.New(), false},
+	{moduleTypeHealth, health.New(), false},
 }
 
 // Manager controls which modules to run and how to present their output.
@@ -67,6 +75,7 @@ type Manager struct {
 	Json       bool         // output as JSON when true
 	Detail     bool         // output detailed view when true
 	Log        *slog.Logger // logger for operational messages
+	SMBIOSOnly bool         // only run the modules that decode an SMBIOS dump
 	collectors map[string]Collector
 }
 
@@ -89,14 +98,21 @@ func NewManager(m *Manager) error {
 
 	m.collectors = make(map[string]Collector)
 	m.SetModule()
+	if len(m.collectors) == 0 && m.SMBIOSOnly {
+		return fmt.Errorf("module %s cannot be decoded from an SMBIOS dump", m.Module)
+	}
 
 	return m.Collect(context.Background())
 }
 
 // SetModule populates the collectors map based on the requested Module name.
-// When Module is "all", every supported module is registered.
+// When Module is "all", every supported module is registered. SMBIOSOnly
+// leaves out the modules that read this host.
 func (m *Manager) SetModule() {
 	for _, c := range supportedModules {
+		if m.SMBIOSOnly && !c.smbios {
+			continue
+		}
 		if m.Module == "all" {
 			m.collectors[string(c.module)] = c.collector
 			continue
@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// readVarint decodes a SQLite big-endian variable-length integer and returns
+// its value and length. A zero length means buf was too short.
+func readVarint(buf []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 9; i++ {
+		if i >= len(buf) {
+			return 0, 0
+		}
+		if i == 8 {
+			return v<<8 | uint64(buf[i]), 9
+		}
+		v = v<<7 | uint64(buf[i]&0x7f)
+		if buf[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return v, 9
+}
+
+// decodeRecord decodes a record payload into its column values.
+func decodeRecord(payload []byte) ([]any, error) {
+	hdrSize, n := readVarint(payload)
+	if n == 0 || hdrSize > uint64(len(payload)) || int(hdrSize) < n {
+		return nil, fmt.Errorf("%w: bad record header", ErrCorrupt)
+	}
+
+	types := make([]uint64, 0, 16)
+	for off := n; off < int(hdrSize); {
+		t, m := readVarint(payload[off:hdrSize])
+		if m == 0 {
+			return nil, fmt.Errorf("%w: bad serial type", ErrCorrupt)
+		}
+		types = append(types, t)
+		off += m
+	}
+
+	values := make([]any, 0, len(types))
+	body := payload[hdrSize:]
+	for _, t := range types {
+		size := serialSize(t)
+		if size < 0 || size > len(body) {
+			return nil, fmt.Errorf("%w: record body too short", ErrCorrupt)
+		}
+		values = append(values, decodeValue(t, body[:size]))
+		body = body[size:]
+	}
+
+	return values, nil
+}
+
+// serialSize returns the number of body bytes used by serial type t.
+func serialSize(t uint64) int {
+	switch {
+	case t <= 4:
+		return []int{0, 1, 2, 3, 4}[t]
+	case t == 5:
+		return 6
+	case t == 6, t == 7:
+		return 8
+	case t < 12:
+		return 0
+	default:
+		return int((t - 12) / 2)
+	}
+}
+
+// decodeValue converts the body bytes of serial type t into a Go value.
+func decodeValue(t uint64, b []byte) any {
+	switch {
+	case t == 0:
+		return nil
+	case t >= 1 && t <= 6:
+		// Big-endian two's complement integers of 1, 2, 3, 4, 6 or 8 bytes.
+		v := int64(int8(b[0]))
+		for _, c := range b[1:] {
+			v = v<<8 | int64(c)
+		}
+		return v
+	case t == 7:
+		return math.Float64frombits(binary.BigEndian.Uint64(b))
+	case t == 8:
+		return int64(0)
+	case t == 9:
+		return int64(1)
+	case t >= 12 && t%2 == 0:
+		return append([]byte(nil), b...)
+	case t >= 13:
+		return string(b)
+	}
+	// Serial types 10 and 11 are reserved.
+	return nil
+}
@@ -0,0 +1,152 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+)
+
+// schemaRootPage is the root page of the sqlite_master table.
+const schemaRootPage = 1
+
+// tableConstraints are the keywords that start a table constraint rather than
+// a column definition in CREATE TABLE.
+var tableConstraints = []string{"CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "FOREIGN"}
+
+// loadSchema reads sqlite_master and records every rowid table.
+func (db *DB) loadSchema() error {
+	db.tables = make(map[string]*tableInfo, 8)
+
+	return db.walkTable(schemaRootPage, func(_ int64, values []any) error {
+		// Columns: type, name, tbl_name, rootpage, sql.
+		if len(values) < 5 {
+			return nil
+		}
+
+		typ, _ := values[0].(string)
+		name, _ := values[1].(string)
+		root, _ := values[3].(int64)
+		sql, _ := values[4].(string)
+		if typ != "table" || root <= 0 || strings.HasPrefix(name, "sqlite_") {
+			return nil
+		}
+
+		columns, rowidColumn, ok := parseCreateTable(sql)
+		if !ok {
+			return fmt.Errorf("%w: cannot parse schema of table %s", ErrCorrupt, name)
+		}
+		if columns == nil {
+			// WITHOUT ROWID tables are stored as index b-trees.
+			return nil
+		}
+
+		db.tables[name] = &tableInfo{
+			name:        name,
+			rootPage:    int(root),
+			columns:     columns,
+			rowidColumn: rowidColumn,
+		}
+		return nil
+	})
+}
+
+// parseCreateTable extracts the column names of a CREATE TABLE statement and
+// the index of its INTEGER PRIMARY KEY column, whose value is stored as the
+// rowid instead of in the record. A nil column list with ok set means the
+// table is a WITHOUT ROWID table.
+func parseCreateTable(sql string) ([]string, int, bool) {
+	open := strings.Index(sql, "(")
+	closing := strings.LastIndex(sql, ")")
+	if open < 0 || closing < open {
+		return nil, -1, false
+	}
+
+	if strings.Contains(strings.ToUpper(sql[closing:]), "WITHOUT ROWID") {
+		return nil, -1, true
+	}
+
+	var (
+		columns     []string
+		rowidColumn = -1
+	)
+	for _, def := range splitTopLevel(sql[open+1 : closing]) {
+		def = strings.TrimSpace(def)
+		if def == "" {
+			continue
+		}
+
+		upper := strings.ToUpper(def)
+		first := strings.Fields(upper)[0]
+		if isTableConstraint(first) {
+			continue
+		}
+
+		name, rest := splitIdentifier(def)
+		fields := strings.Fields(strings.ToUpper(rest))
+		if len(fields) > 0 && fields[0] == "INTEGER" && strings.Contains(strings.ToUpper(rest), "PRIMARY KEY") {
+			rowidColumn = len(columns)
+		}
+		columns = append(columns, name)
+	}
+
+	return columns, rowidColumn, len(columns) > 0
+}
+
+// isTableConstraint reports whether a column definition keyword starts a
+// table constraint.
+func isTableConstraint(word string) bool {
+	for _, c := range tableConstraints {
+		if word == c {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTopLevel splits s at commas that are not nested in parentheses or quotes.
+func splitTopLevel(s string) []string {
+	var (
+		res   []string
+		depth int
+		quote byte
+		start int
+	)
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			quote = ']'
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			res = append(res, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(res, s[start:])
+}
+
+// splitIdentifier returns the leading, possibly quoted, identifier of def and
+// the remainder of the definition.
+func splitIdentifier(def string) (string, string) {
+	closers := map[byte]byte{'"': '"', '`': '`', '[': ']', '\'': '\''}
+	if end, ok := closers[def[0]]; ok {
+		if i := strings.IndexByte(def[1:], end); i >= 0 {
+			return def[1 : i+1], def[i+2:]
+		}
+	}
+
+	if i := strings.IndexAny(def, " \t\r\n"); i >= 0 {
+		return def[:i], def[i+1:]
+	}
+	return def, ""
+}
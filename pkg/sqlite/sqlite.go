@@ -0,0 +1,348 @@
+// Package sqlite is a minimal, read-only reader for SQLite 3 database files.
+// It supports full scans of rowid tables, which is all that is needed to
+// ingest the event stores of tools such as rasdaemon without cgo or an
+// external sqlite3 binary. Indexes, WITHOUT ROWID tables, and uncommitted WAL
+// content are not supported.
+package sqlite
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	headerMagic = "SQLite format 3\x00"
+	headerSize  = 100
+
+	// B-tree page types.
+	pageInteriorTable = 0x05
+	pageLeafTable     = 0x0d
+
+	// maxTreeDepth guards against cycles in corrupt files.
+	maxTreeDepth = 64
+
+	// minUsableSize is the smallest usable page size the file format allows.
+	minUsableSize = 480
+)
+
+var (
+	ErrNotSQLite     = errors.New("not a SQLite 3 database")
+	ErrTableNotFound = errors.New("table not found")
+	ErrCorrupt       = errors.New("database file is malformed")
+)
+
+// DB is an open database file.
+type DB struct {
+	file       *os.File
+	pageSize   int
+	usableSize int
+	pageCount  int
+	tables     map[string]*tableInfo
+}
+
+// tableInfo describes one rowid table found in sqlite_master.
+type tableInfo struct {
+	name     string
+	rootPage int
+	columns  []string
+	// rowidColumn is the index of the INTEGER PRIMARY KEY column, or -1.
+	rowidColumn int
+}
+
+// Row is one table row keyed by column name. Values are int64, float64,
+// string, []byte or nil.
+type Row map[string]any
+
+// Open opens a database file read-only and loads its schema.
+func Open(path string) (*DB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &DB{file: f}
+	if err := db.readHeader(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := db.loadSchema(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return db, nil
+}
+
+// Close closes the underlying file.
+func (db *DB) Close() error {
+	return db.file.Close()
+}
+
+// Tables returns the names of all rowid tables.
+func (db *DB) Tables() []string {
+	res := make([]string, 0, len(db.tables))
+	for name := range db.tables {
+		res = append(res, name)
+	}
+	return res
+}
+
+// HasTable reports whether the database contains the named table.
+func (db *DB) HasTable(name string) bool {
+	_, ok := db.tables[name]
+	return ok
+}
+
+// Columns returns the column names of a table in declaration order.
+func (db *DB) Columns(table string) ([]string, error) {
+	t, ok := db.tables[table]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTableNotFound, table)
+	}
+	return t.columns, nil
+}
+
+// Rows returns all rows of a table in rowid order.
+func (db *DB) Rows(table string) ([]Row, error) {
+	t, ok := db.tables[table]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTableNotFound, table)
+	}
+
+	var res []Row
+	err := db.walkTable(t.rootPage, func(rowid int64, values []any) error {
+		row := make(Row, len(t.columns))
+		for i, col := range t.columns {
+			switch {
+			case i == t.rowidColumn:
+				row[col] = rowid
+			case i < len(values):
+				row[col] = values[i]
+			default:
+				// Columns added by ALTER TABLE are absent from older records.
+				row[col] = nil
+			}
+		}
+		res = append(res, row)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read table %s: %w", table, err)
+	}
+
+	return res, nil
+}
+
+// readHeader validates the 100-byte database header.
+func (db *DB) readHeader() error {
+	hdr := make([]byte, headerSize)
+	if _, err := io.ReadFull(db.file, hdr); err != nil {
+		return ErrNotSQLite
+	}
+
+	if string(hdr[:16]) != headerMagic {
+		return ErrNotSQLite
+	}
+
+	db.pageSize = int(binary.BigEndian.Uint16(hdr[16:18]))
+	if db.pageSize == 1 {
+		db.pageSize = 65536
+	}
+	if db.pageSize < 512 || db.pageSize&(db.pageSize-1) != 0 {
+		return fmt.Errorf("%w: invalid page size %d", ErrCorrupt, db.pageSize)
+	}
+
+	db.usableSize = db.pageSize - int(hdr[20])
+	if db.usableSize < minUsableSize {
+		return fmt.Errorf("%w: usable page size %d", ErrCorrupt, db.usableSize)
+	}
+
+	if enc := binary.BigEndian.Uint32(hdr[56:60]); enc > 1 {
+		return fmt.Errorf("unsupported text encoding %d (only UTF-8)", enc)
+	}
+
+	info, err := db.file.Stat()
+	if err != nil {
+		return err
+	}
+	db.pageCount = int(info.Size() / int64(db.pageSize))
+
+	return nil
+}
+
+// readPage returns the contents of page n (1-based).
+func (db *DB) readPage(n int) ([]byte, error) {
+	if n < 1 || n > db.pageCount {
+		return nil, fmt.Errorf("%w: page %d out of range", ErrCorrupt, n)
+	}
+
+	buf := make([]byte, db.pageSize)
+	if _, err := db.file.ReadAt(buf, int64(n-1)*int64(db.pageSize)); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// walkTable visits every row of the table b-tree rooted at page n.
+func (db *DB) walkTable(n int, fn func(rowid int64, values []any) error) error {
+	return db.walkPage(n, 0, make(map[int]bool), fn)
+}
+
+// walkPage visits the rows below page n. A page referenced twice means the
+// b-tree has a cycle.
+func (db *DB) walkPage(n, depth int, seen map[int]bool, fn func(rowid int64, values []any) error) error {
+	if depth > maxTreeDepth {
+		return fmt.Errorf("%w: b-tree too deep", ErrCorrupt)
+	}
+	if seen[n] {
+		return fmt.Errorf("%w: page %d referenced twice", ErrCorrupt, n)
+	}
+	seen[n] = true
+
+	page, err := db.readPage(n)
+	if err != nil {
+		return err
+	}
+
+	// Page 1 starts with the database header.
+	off := 0
+	if n == 1 {
+		off = headerSize
+	}
+	if off+8 > len(page) {
+		return fmt.Errorf("%w: short page %d", ErrCorrupt, n)
+	}
+
+	typ := page[off]
+	cells := int(binary.BigEndian.Uint16(page[off+3 : off+5]))
+
+	hdrLen := 8
+	if typ == pageInteriorTable {
+		hdrLen = 12
+	}
+	ptrs := off + hdrLen
+	if ptrs+2*cells > len(page) {
+		return fmt.Errorf("%w: cell pointers overflow page %d", ErrCorrupt, n)
+	}
+
+	for i := 0; i < cells; i++ {
+		cell := int(binary.BigEndian.Uint16(page[ptrs+2*i:]))
+		if cell >= len(page) {
+			return fmt.Errorf("%w: cell offset out of page %d", ErrCorrupt, n)
+		}
+
+		switch typ {
+		case pageInteriorTable:
+			if cell+4 > len(page) {
+				return fmt.Errorf("%w: interior cell out of page %d", ErrCorrupt, n)
+			}
+			child := int(binary.BigEndian.Uint32(page[cell:]))
+			if err := db.walkPage(child, depth+1, seen, fn); err != nil {
+				return err
+			}
+		case pageLeafTable:
+			rowid, values, err := db.readLeafCell(page, cell)
+			if err != nil {
+				return fmt.Errorf("page %d cell %d: %w", n, i, err)
+			}
+			if err := fn(rowid, values); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: page %d is not a table b-tree page (type 0x%02x)", ErrCorrupt, n, typ)
+		}
+	}
+
+	if typ == pageInteriorTable {
+		right := int(binary.BigEndian.Uint32(page[off+8 : off+12]))
+		return db.walkPage(right, depth+1, seen, fn)
+	}
+
+	return nil
+}
+
+// readLeafCell decodes a table leaf cell, following overflow pages as needed.
+func (db *DB) readLeafCell(page []byte, off int) (int64, []any, error) {
+	size, n := readVarint(page[off:])
+	if n == 0 {
+		return 0, nil, ErrCorrupt
+	}
+	// A payload cannot be larger than the file; the check also rejects sizes
+	// that overflow int.
+	if size > uint64(db.pageCount)*uint64(db.pageSize) {
+		return 0, nil, fmt.Errorf("%w: payload size %d exceeds database", ErrCorrupt, size)
+	}
+	off += n
+
+	rowid, n := readVarint(page[off:])
+	if n == 0 {
+		return 0, nil, ErrCorrupt
+	}
+	off += n
+
+	payload, err := db.readPayload(page, off, int(size))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	values, err := decodeRecord(payload)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return int64(rowid), values, nil
+}
+
+// readPayload assembles a cell payload of total size p whose local part
+// starts at page[off]. The split between local and overflow content follows
+// the table b-tree leaf rules of the file format specification.
+func (db *DB) readPayload(page []byte, off, p int) ([]byte, error) {
+	u := db.usableSize
+	x := u - 35
+
+	local := p
+	if p > x {
+		m := ((u-12)*32)/255 - 23
+		k := m + (p-m)%(u-4)
+		local = m
+		if k <= x {
+			local = k
+		}
+	}
+
+	if off+local > len(page) {
+		return nil, fmt.Errorf("%w: payload exceeds page", ErrCorrupt)
+	}
+
+	payload := make([]byte, 0, p)
+	payload = append(payload, page[off:off+local]...)
+	if local == p {
+		return payload, nil
+	}
+
+	if off+local+4 > len(page) {
+		return nil, fmt.Errorf("%w: missing overflow pointer", ErrCorrupt)
+	}
+	next := int(binary.BigEndian.Uint32(page[off+local:]))
+
+	for hops := 0; len(payload) < p; hops++ {
+		if next == 0 || hops > db.pageCount {
+			return nil, fmt.Errorf("%w: truncated overflow chain", ErrCorrupt)
+		}
+
+		ovf, err := db.readPage(next)
+		if err != nil {
+			return nil, err
+		}
+
+		next = int(binary.BigEndian.Uint32(ovf[:4]))
+		chunk := min(p-len(payload), u-4)
+		payload = append(payload, ovf[4:4+chunk]...)
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,277 @@
+package sqlite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fixture is a rasdaemon-like database with 512-byte pages, so mc_event spans
+// an interior page, several leaf pages and an overflow chain.
+const fixture = "testdata/rasdaemon.db"
+
+const fixturePageSize = 512
+
+// findPage returns the first page after page 1 with the given b-tree type.
+func findPage(t *testing.T, data []byte, typ byte) int {
+	t.Helper()
+	for n := 2; n*fixturePageSize <= len(data); n++ {
+		if data[(n-1)*fixturePageSize] == typ {
+			return n
+		}
+	}
+	t.Fatalf("no page of type 0x%02x in fixture", typ)
+	return 0
+}
+
+// firstCell returns the file offset of the first cell of page n.
+func firstCell(data []byte, n int) int {
+	page := (n - 1) * fixturePageSize
+	return page + int(binary.BigEndian.Uint16(data[page+8:]))
+}
+
+// readAll opens path and reads every table, returning the first error.
+func readAll(path string) error {
+	db, err := Open(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	for _, table := range db.Tables() {
+		if _, err := db.Rows(table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRows(t *testing.T) {
+	db, err := Open(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Rows("mc_event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 41 {
+		t.Fatalf("mc_event: got %d rows, want 41", len(rows))
+	}
+	for i, row := range rows {
+		if row["id"] != int64(i+1) {
+			t.Fatalf("row %d: id %v, want %d", i, row["id"], i+1)
+		}
+	}
+
+	last := rows[40]
+	tests := []struct {
+		column string
+		want   any
+	}{
+		{"err_type", "Uncorrected"},
+		{"label", "CPU_SrcID#1_MC#1_Chan#2_DIMM#1"},
+		{"err_count", int64(3)},
+		{"mc", int64(1)},
+		{"address", int64(-1)},
+		{"driver_detail", string(bytes.Repeat([]byte("x"), 2000))},
+	}
+	for _, tt := range tests {
+		if got := last[tt.column]; got != tt.want {
+			t.Errorf("mc_event %s = %v, want %v", tt.column, got, tt.want)
+		}
+	}
+
+	blobs, err := db.Rows("blobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 2 {
+		t.Fatalf("blobs: got %d rows, want 2", len(blobs))
+	}
+	if got, _ := blobs[0]["data"].([]byte); !bytes.Equal(got, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}) {
+		t.Errorf("blobs data = %v", blobs[0]["data"])
+	}
+	if blobs[0]["ratio"] != 0.5 {
+		t.Errorf("blobs ratio = %v, want 0.5", blobs[0]["ratio"])
+	}
+	if blobs[1]["data"] != nil || blobs[1]["ratio"] != nil {
+		t.Errorf("blobs row 2 = %v, want NULLs", blobs[1])
+	}
+
+	if _, err := db.Rows("missing"); !errors.Is(err, ErrTableNotFound) {
+		t.Errorf("Rows(missing) error = %v, want ErrTableNotFound", err)
+	}
+}
+
+func TestColumns(t *testing.T) {
+	db, err := Open(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	cols, err := db.Columns("aer_event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"id", "timestamp", "dev_name", "err_type", "err_msg"}
+	if len(cols) != len(want) {
+		t.Fatalf("aer_event columns = %v, want %v", cols, want)
+	}
+	for i := range want {
+		if cols[i] != want[i] {
+			t.Fatalf("aer_event columns = %v, want %v", cols, want)
+		}
+	}
+}
+
+func TestCorrupt(t *testing.T) {
+	orig, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(t *testing.T, b []byte) []byte
+		want   error
+	}{
+		{
+			name:   "intact",
+			mutate: func(t *testing.T, b []byte) []byte { return b },
+		},
+		{
+			name:   "empty",
+			mutate: func(t *testing.T, b []byte) []byte { return nil },
+			want:   ErrNotSQLite,
+		},
+		{
+			name:   "truncated header",
+			mutate: func(t *testing.T, b []byte) []byte { return b[:50] },
+			want:   ErrNotSQLite,
+		},
+		{
+			name:   "bad magic",
+			mutate: func(t *testing.T, b []byte) []byte { b[0] = 'X'; return b },
+			want:   ErrNotSQLite,
+		},
+		{
+			name:   "truncated pages",
+			mutate: func(t *testing.T, b []byte) []byte { return b[:len(b)/2] },
+			want:   ErrCorrupt,
+		},
+		{
+			name: "invalid page size",
+			mutate: func(t *testing.T, b []byte) []byte {
+				binary.BigEndian.PutUint16(b[16:], 300)
+				return b
+			},
+			want: ErrCorrupt,
+		},
+		{
+			name:   "reserved space too large",
+			mutate: func(t *testing.T, b []byte) []byte { b[20] = 64; return b },
+			want:   ErrCorrupt,
+		},
+		{
+			name: "negative payload size",
+			mutate: func(t *testing.T, b []byte) []byte {
+				off := firstCell(b, findPage(t, b, pageLeafTable))
+				copy(b[off:], bytes.Repeat([]byte{0xff}, 9))
+				return b
+			},
+			want: ErrCorrupt,
+		},
+		{
+			name: "huge payload size",
+			mutate: func(t *testing.T, b []byte) []byte {
+				off := firstCell(b, findPage(t, b, pageLeafTable))
+				copy(b[off:], []byte{0x87, 0xff, 0xff, 0xff, 0x7f})
+				return b
+			},
+			want: ErrCorrupt,
+		},
+		{
+			name: "unknown page type",
+			mutate: func(t *testing.T, b []byte) []byte {
+				n := findPage(t, b, pageLeafTable)
+				b[(n-1)*fixturePageSize] = 0x42
+				return b
+			},
+			want: ErrCorrupt,
+		},
+		{
+			name: "child page out of range",
+			mutate: func(t *testing.T, b []byte) []byte {
+				off := firstCell(b, findPage(t, b, pageInteriorTable))
+				binary.BigEndian.PutUint32(b[off:], 9999)
+				return b
+			},
+			want: ErrCorrupt,
+		},
+		{
+			name: "b-tree cycle",
+			mutate: func(t *testing.T, b []byte) []byte {
+				n := findPage(t, b, pageInteriorTable)
+				binary.BigEndian.PutUint32(b[(n-1)*fixturePageSize+8:], uint32(n))
+				return b
+			},
+			want: ErrCorrupt,
+		},
+		{
+			name: "cell offset out of page",
+			mutate: func(t *testing.T, b []byte) []byte {
+				n := findPage(t, b, pageLeafTable)
+				binary.BigEndian.PutUint16(b[(n-1)*fixturePageSize+8:], 0xffff)
+				return b
+			},
+			want: ErrCorrupt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.mutate(t, bytes.Clone(orig))
+			path := filepath.Join(t.TempDir(), "ras-mc_event.db")
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := readAll(path)
+			switch {
+			case tt.want == nil && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.want != nil && !errors.Is(err, tt.want):
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadVarint(t *testing.T) {
+	tests := []struct {
+		in   []byte
+		want uint64
+		n    int
+	}{
+		{[]byte{0x00}, 0, 1},
+		{[]byte{0x7f}, 0x7f, 1},
+		{[]byte{0x81, 0x00}, 0x80, 2},
+		{[]byte{0x81, 0x80, 0x00}, 0x4000, 3},
+		{bytes.Repeat([]byte{0xff}, 9), 0xffffffffffffffff, 9},
+		{[]byte{0x81}, 0, 0},
+		{nil, 0, 0},
+	}
+	for _, tt := range tests {
+		got, n := readVarint(tt.in)
+		if got != tt.want || n != tt.n {
+			t.Errorf("readVarint(% x) = %d, %d; want %d, %d", tt.in, got, n, tt.want, tt.n)
+		}
+	}
+}
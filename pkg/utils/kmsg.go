@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/zenithax-cc/baize/pkg/execute"
+)
+
+const (
+	procUptime = "/proc/uptime"
+	devKmsg    = "/dev/kmsg"
+	dmesg      = "/usr/bin/dmesg"
+
+	// kmsgRecordSize is large enough for any single /dev/kmsg record.
+	kmsgRecordSize = 8192
+)
+
+// KernelLogEntry is one record of the kernel ring buffer.
+type KernelLogEntry struct {
+	// Priority is the syslog level (0 emerg .. 7 debug), or -1 when unknown.
+	Priority int
+	// Timestamp is the time since boot at which the message was logged.
+	Timestamp time.Duration
+	Message   string
+}
+
+// ReadKernelLog returns the current contents of the kernel ring buffer. It
+// reads /dev/kmsg directly and falls back to dmesg when the device cannot be
+// opened (e.g. in containers or with kernel.dmesg_restrict).
+func ReadKernelLog() ([]KernelLogEntry, error) {
+	entries, err := readKmsg()
+	if err == nil {
+		return entries, nil
+	}
+
+	output := execute.Command(dmesg)
+	if output.Err != nil {
+		return nil, errors.Join(err, output.Err)
+	}
+
+	return parseDmesg(string(output.Stdout)), nil
+}
+
+// readKmsg drains /dev/kmsg without blocking. Each read returns exactly one
+// record; EAGAIN marks the end of the buffer. The file is opened with raw
+// syscalls because an os.File would park the reader on the runtime poller
+// instead of returning EAGAIN.
+func readKmsg() ([]KernelLogEntry, error) {
+	fd, err := syscall.Open(devKmsg, syscall.O_RDONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.Close(fd)
+
+	var (
+		res []KernelLogEntry
+		buf = make([]byte, kmsgRecordSize)
+	)
+	for {
+		n, err := syscall.Read(fd, buf)
+		switch {
+		case errors.Is(err, syscall.EAGAIN):
+			return res, nil
+		case errors.Is(err, syscall.EPIPE):
+			// The record was overwritten while reading; continue with the next one.
+			continue
+		case errors.Is(err, syscall.EINTR):
+			continue
+		case err != nil:
+			return res, err
+		case n <= 0:
+			return res, nil
+		}
+
+		if entry, ok := parseKmsgRecord(string(buf[:n])); ok {
+			res = append(res, entry)
+		}
+	}
+}
+
+// parseKmsgRecord parses a /dev/kmsg record such as
+// "4,1234,5678901,-;mce: [Hardware Error]: ...". Continuation lines holding
+// device properties are dropped.
+func parseKmsgRecord(record string) (KernelLogEntry, bool) {
+	prefix, msg, ok := strings.Cut(record, ";")
+	if !ok {
+		return KernelLogEntry{}, false
+	}
+
+	fields := strings.Split(prefix, ",")
+	if len(fields) < 3 {
+		return KernelLogEntry{}, false
+	}
+
+	prio, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return KernelLogEntry{}, false
+	}
+	usec, err := strconv.ParseUint(fields[2], 10, 64)
+	if err != nil {
+		return KernelLogEntry{}, false
+	}
+
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		msg = msg[:i]
+	}
+
+	return KernelLogEntry{
+		Priority:  prio & 7,
+		Timestamp: time.Duration(usec) * time.Microsecond,
+		Message:   msg,
+	}, true
+}
+
+// parseDmesg parses the default dmesg output "[  123.456789] message".
+func parseDmesg(output string) []KernelLogEntry {
+	lines := strings.Split(output, "\n")
+	res := make([]KernelLogEntry, 0, len(lines))
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		entry := KernelLogEntry{Priority: -1, Message: line}
+		if strings.HasPrefix(line, "[") {
+			if ts, msg, ok := strings.Cut(line[1:], "]"); ok {
+				if sec, err := strconv.ParseFloat(strings.TrimSpace(ts), 64); err == nil {
+					entry.Timestamp = time.Duration(sec * float64(time.Second))
+					entry.Message = strings.TrimPrefix(msg, " ")
+				}
+			}
+		}
+
+		res = append(res, entry)
+	}
+
+	return res
+}
+
+// BootTime derives the system boot time from /proc/uptime. Adding a
+// KernelLogEntry.Timestamp to it gives the wall-clock time of the message.
+func BootTime() (time.Time, error) {
+	line, err := ReadOneLineFile(procUptime)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return time.Time{}, fmt.Errorf("empty %s", procUptime)
+	}
+	sec, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse %s: %w", procUptime, err)
+	}
+
+	return time.Now().Add(-time.Duration(sec * float64(time.Second))), nil
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// placeholderStrings are firmware defaults left in SMBIOS strings, compared
+// case-insensitively.
+var placeholderStrings = []string{
+	"",
+	"to be filled by o.e.m.",
+	"default string",
+	"not specified",
+	"not applicable",
+	"system serial number",
+	"chassis serial number",
+	"base board serial number",
+	"type2 - board serial number",
+	"serial number",
+	"asset tag",
+	"asset-1234567890",
+	"no asset tag",
+	"no dimm",
+	"none",
+	"n/a",
+	"na",
+	"undefined",
+	"oem",
+	"o.e.m.",
+	"default",
+	"unknown",
+	"0123456789",
+	"123456789",
+	"1234567890",
+}
+
+// placeholderPrefixes are followed by a slot number in BIOS defaults such as
+// AMI's "SerNum0" or "PartNum3".
+var placeholderPrefixes = []string{"manufacturer", "sernum", "serial", "partnum", "part"}
+
+// IsPlaceholder reports whether an SMBIOS string is empty, a known firmware
+// default such as "To Be Filled By O.E.M." or "NO DIMM", a numbered default
+// such as "SerNum0", or a run of one repeated character such as "00000000".
+func IsPlaceholder(s string) bool {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if slices.Contains(placeholderStrings, s) {
+		return true
+	}
+	for _, p := range placeholderPrefixes {
+		if rest, ok := strings.CutPrefix(s, p); ok {
+			if _, err := strconv.Atoi(rest); err == nil {
+				return true
+			}
+		}
+	}
+	return len(s) >= 4 && strings.Count(s, s[:1]) == len(s)
+}